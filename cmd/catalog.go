@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"github.com/spf13/pflag"
+)
+
+const catalogUsage = `usage:
+  catalog import <movies|screenings> --file <path> [--format csv|json] [--dry-run]
+  catalog export <movies|screenings> [--format csv|json] [--out <path>] [--from DD-MM-YYYY] [--to DD-MM-YYYY]`
+
+// Catalog runs bulk import and export from the command line
+func Catalog(args []string) {
+	if len(args) < 2 {
+		log.Fatal(catalogUsage)
+	}
+	action, kind := args[0], args[1]
+
+	flags := pflag.NewFlagSet("catalog", pflag.ExitOnError)
+	file := flags.String("file", "", "file to import")
+	format := flags.String("format", "", "csv or json, defaults to the file extension")
+	dryRun := flags.Bool("dry-run", false, "validate without saving")
+	out := flags.String("out", "", "export destination, defaults to stdout")
+	from := flags.String("from", "", "first screening date to export")
+	to := flags.String("to", "", "last screening date to export")
+	flags.Parse(args[2:])
+
+	// Configuration parses the global flags too, leave ours alone
+	pflag.CommandLine.ParseErrorsAllowlist.UnknownFlags = true
+	config, err := utils.ReadConfiguration()
+	if err != nil {
+		log.Fatalf("failed to read file config: %v", err)
+	}
+
+	db, err := database.InitDB(config.DB)
+	if err != nil {
+		log.Fatalf("failed to connect to postgres database: %v", err)
+	}
+	defer db.Close()
+
+	logger, err := utils.InitLogger(config.PathLogging, config.Debug)
+	if err != nil {
+		log.Fatalf("failed to init logger: %v", err)
+	}
+
+	repo := repository.NewRepository(db, logger)
+	catalog := usecase.NewCatalogUsecase(&repo, logger)
+
+	switch action {
+	case "import":
+		if *file == "" {
+			log.Fatal(catalogUsage)
+		}
+		if *format == "" {
+			*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("failed to open file: %v", err)
+		}
+		defer f.Close()
+
+		report, err := catalog.Import(kind, *format, f, *dryRun)
+		if err != nil {
+			log.Fatalf("import failed: %v", err)
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+		if report.Failed > 0 {
+			os.Exit(1)
+		}
+	case "export":
+		if *format == "" {
+			*format = "csv"
+		}
+
+		data, err := catalog.Export(kind, *format, *from, *to)
+		if err != nil {
+			log.Fatalf("export failed: %v", err)
+		}
+
+		if *out != "" {
+			if err := os.WriteFile(*out, data, 0o644); err != nil {
+				log.Fatalf("failed to write file: %v", err)
+			}
+			fmt.Printf("exported %s to %s\n", kind, *out)
+			return
+		}
+		os.Stdout.Write(data)
+	default:
+		log.Fatal(catalogUsage)
+	}
+}
@@ -0,0 +1,172 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type APIKeyHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewAPIKeyHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) APIKeyHandler {
+	return APIKeyHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.APIKeyRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto api key request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute create api key
+	result, err := h.Usecase.APIKeyUsecase.Create(user.ID, req)
+	if err != nil && err.Error() == utils.ErrNotFound("user").Error() {
+		h.Logger.Error("Error user not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "user not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling create api key: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "create api key failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "create api key success, store the key safely", result)
+}
+
+func (h *APIKeyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Execute get api keys
+	result, err := h.Usecase.APIKeyUsecase.GetAll()
+	if err != nil {
+		h.Logger.Error("Error handling get api keys: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get api keys failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get api keys success", result)
+}
+
+func (h *APIKeyHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get api key
+	result, err := h.Usecase.APIKeyUsecase.GetByID(id)
+	if err != nil && err.Error() == utils.ErrNotFound("api key").Error() {
+		h.Logger.Error("Error api key not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "api key not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get api key by id: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get api key failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get api key success", result)
+}
+
+func (h *APIKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute rotate api key
+	result, err := h.Usecase.APIKeyUsecase.Rotate(id)
+	if err != nil && err.Error() == utils.ErrNotFound("api key").Error() {
+		h.Logger.Error("Error api key not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "api key not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling rotate api key: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "rotate api key failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "rotate api key success, store the key safely", result)
+}
+
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute revoke api key
+	err = h.Usecase.APIKeyUsecase.Revoke(id)
+	if err != nil && err.Error() == utils.ErrNotFound("api key").Error() {
+		h.Logger.Error("Error api key not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "api key not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling revoke api key: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "revoke api key failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "revoke api key success", nil)
+}
+
+// allowBookingForKey restricts api keys to bookings at their cinemas,
+// requests signed in with a session are not affected
+func allowBookingForKey(uc usecase.Usecase, r *http.Request, bookingID int) error {
+	key, ok := r.Context().Value("api_key").(entity.APIKey)
+	if !ok {
+		return nil
+	}
+	return uc.APIKeyUsecase.AllowBooking(key, bookingID)
+}
+
+// allowScreeningForKey restricts api keys to screenings at their cinemas
+func allowScreeningForKey(uc usecase.Usecase, r *http.Request, screeningID int) error {
+	key, ok := r.Context().Value("api_key").(entity.APIKey)
+	if !ok {
+		return nil
+	}
+	return uc.APIKeyUsecase.AllowScreening(key, screeningID)
+}
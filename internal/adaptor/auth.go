@@ -50,6 +50,40 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Password accepted, second step pending
+	if result.TwoFactorRequired {
+		utils.ResponseSuccess(w, http.StatusOK, "two factor code required", result)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "login success", result)
+}
+
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorLoginRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto two factor login request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute verify two factor
+	result, err := h.Usecase.AuthUsecase.VerifyTwoFactor(req)
+	if err != nil {
+		h.Logger.Error("Error handling verify two factor: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusUnauthorized, "two factor verification failed", err.Error())
+		return
+	}
+
 	utils.ResponseSuccess(w, http.StatusOK, "login success", result)
 }
 
@@ -71,7 +105,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute register
-	result, err := h.Usecase.AuthUsecase.Register(req)
+	result, err := h.Usecase.AuthUsecase.Register(req, utils.GetLocale(r, h.Config))
 	if err != nil {
 		h.Logger.Error("Error handling register user: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusUnauthorized, "register user failed", err.Error())
@@ -127,14 +161,14 @@ func (h *AuthHandler) ResendOTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute resend OTP
-	result, err := h.Usecase.AuthUsecase.ResendOTP(req.Email)
+	err = h.Usecase.AuthUsecase.ResendOTP(req.Email)
 	if err != nil {
 		h.Logger.Error("Error handling verify email: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusUnauthorized, "send OTP failed", err.Error())
 		return
 	}
 
-	utils.ResponseSuccess(w, http.StatusOK, "OTP sent successfully", result)
+	utils.ResponseSuccess(w, http.StatusOK, "OTP sent successfully", nil)
 }
 
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
@@ -150,4 +184,85 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "logout success", nil)
+}
+
+// Cookie holding the state of an external login started in this browser
+const oidcStateCookie = "oidc_state"
+
+func (h *AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	// Execute start external login
+	result, err := h.Usecase.OIDCUsecase.Start()
+	if err != nil {
+		h.Logger.Error("Error handling start oidc login: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "start external login failed", err.Error())
+		return
+	}
+
+	// Bind the login to this browser against login CSRF
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    result.State,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Browser clients can be sent straight to the provider
+	if r.URL.Query().Get("redirect") == "true" {
+		http.Redirect(w, r, result.AuthorizationURL, http.StatusFound)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "redirect to authorization url", result)
+}
+
+func (h *AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	// Provider reported an error
+	if errCode := r.URL.Query().Get("error"); errCode != "" {
+		utils.ResponseFailed(w, http.StatusUnauthorized, "external login failed", errCode)
+		return
+	}
+
+	req := dto.OIDCCallbackRequest{
+		Code: r.URL.Query().Get("code"),
+		State: r.URL.Query().Get("state"),
+	}
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		req.BrowserState = cookie.Value
+	}
+
+	// The state is single use, drop it whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute external login
+	result, err := h.Usecase.OIDCUsecase.Callback(req)
+	if err != nil {
+		h.Logger.Error("Error handling oidc callback: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusUnauthorized, "external login failed", err.Error())
+		return
+	}
+
+	// Identity verified, second step pending
+	if result.TwoFactorRequired {
+		utils.ResponseSuccess(w, http.StatusOK, "two factor code required", result)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "login success", result)
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package adaptor
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -14,15 +15,15 @@ import (
 
 type BookingHandler struct {
 	Usecase usecase.Usecase
-	Logger *zap.Logger
-	Config utils.Configuration
+	Logger  *zap.Logger
+	Config  utils.Configuration
 }
 
 func NewBookingHandler(uc usecase.Usecase, log *zap.Logger, config utils.Configuration) BookingHandler {
 	return BookingHandler{
 		Usecase: uc,
-		Logger: log,
-		Config: config,
+		Logger:  log,
+		Config:  config,
 	}
 }
 
@@ -47,8 +48,14 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Restrict api keys to their cinemas
+	if err := allowScreeningForKey(h.Usecase, r, req.ScreeningID); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
 	// Execute create booking
-	result, err := h.Usecase.BookingUsecase.Create(req)
+	result, err := h.Usecase.BookingUsecase.Create(req, utils.GetLocale(r, h.Config))
 	if err != nil {
 		h.Logger.Error("Error handling create booking: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusBadRequest, "create booking failed", err.Error())
@@ -60,7 +67,7 @@ func (h *BookingHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 func (h *BookingHandler) GetBookingHistory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	
+
 	// Retrieve query
 	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
 	if err != nil {
@@ -89,8 +96,14 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
 	// Execute get booking
-	result, err := h.Usecase.BookingUsecase.GetByID(id)
+	result, err := h.Usecase.BookingUsecase.GetByID(id, utils.GetLocale(r, h.Config))
 	if err != nil && err.Error() == utils.ErrNotFound("booking").Error() {
 		h.Logger.Error("Error booking not found: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusNotFound, "booking not found", err.Error())
@@ -102,4 +115,207 @@ func (h *BookingHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	utils.ResponseSuccess(w, http.StatusOK, "get booking success", result)
-}
\ No newline at end of file
+}
+
+func (h *BookingHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute cancel booking
+	err = h.Usecase.BookingUsecase.Cancel(id, user.ID)
+	if err != nil && err.Error() == utils.ErrNotFound("booking").Error() {
+		h.Logger.Error("Error booking not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "booking not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling cancel booking: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "cancel booking failed", err.Error())
+		return
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "cancel booking success", nil)
+}
+
+func (h *BookingHandler) SwapSeats(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.SeatSwapRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto seat swap request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute swap seats
+	result, err := h.Usecase.BookingUsecase.SwapSeats(r.Context(), id, req, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("booking").Error() {
+		h.Logger.Error("Error booking not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "booking not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling swap seats: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "change seats failed", err.Error())
+		return
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "change seats success", result)
+}
+
+func (h *BookingHandler) Exchange(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.ExchangeRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto exchange request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+	if err := allowScreeningForKey(h.Usecase, r, req.ScreeningID); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute exchange
+	result, err := h.Usecase.BookingUsecase.Exchange(r.Context(), id, req, utils.GetLocale(r, h.Config))
+	if err != nil && (err.Error() == utils.ErrNotFound("booking").Error() || err.Error() == utils.ErrNotFound("screening").Error()) {
+		h.Logger.Error("Error booking or screening not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "data not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling exchange booking: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "exchange booking failed", err.Error())
+		return
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "exchange booking success", result)
+}
+
+func (h *BookingHandler) GetDocuments(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute get booking documents
+	result, err := h.Usecase.BookingUsecase.GetDocuments(r.Context(), id)
+	if err != nil && err.Error() == utils.ErrNotFound("booking").Error() {
+		h.Logger.Error("Error booking not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "booking not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get booking documents: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get booking documents failed", err.Error())
+		return
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "get booking documents success", result)
+}
+
+func (h *BookingHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id and kind
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+	kind := r.PathValue("kind")
+	if kind != "tickets" && kind != "invoice" {
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", "document must be tickets or invoice")
+		return
+	}
+
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, id); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute render booking document
+	data, fileName, err := h.Usecase.BookingUsecase.GetDocument(r.Context(), id, kind, utils.GetLocale(r, h.Config))
+	if err != nil && (err.Error() == utils.ErrNotFound("booking").Error() || err.Error() == utils.ErrNotFound("document").Error()) {
+		h.Logger.Error("Error booking document not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "document not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling download booking document: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "download document failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,214 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type BoxOfficeHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewBoxOfficeHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) BoxOfficeHandler {
+	return BoxOfficeHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *BoxOfficeHandler) Sell(w http.ResponseWriter, r *http.Request) {
+	var req dto.BoxOfficeRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto box office request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute box office sale
+	result, err := h.Usecase.BoxOfficeUsecase.Sell(r.Context(), req, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling box office sale: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "box office sale failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "box office sale success", result)
+}
+
+func (h *BoxOfficeHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	// Retrieve booking id
+	idStr := r.PathValue("id")
+	bookingID, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid booking id", err.Error())
+		return
+	}
+
+	// Execute render receipt
+	data, err := h.Usecase.BoxOfficeUsecase.GetReceipt(r.Context(), bookingID, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("sale").Error() {
+		h.Logger.Error("Error sale not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "sale not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get receipt: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get receipt failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", fmt.Sprintf("receipt-BO-%06d.pdf", bookingID)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *BoxOfficeHandler) OpenShift(w http.ResponseWriter, r *http.Request) {
+	var req dto.OpenShiftRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto open shift request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute open shift
+	result, err := h.Usecase.BoxOfficeUsecase.OpenShift(r.Context(), req)
+	if err != nil {
+		h.Logger.Error("Error handling open shift: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "open shift failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "open shift success", result)
+}
+
+func (h *BoxOfficeHandler) CurrentShift(w http.ResponseWriter, r *http.Request) {
+	// Execute get current shift
+	result, err := h.Usecase.BoxOfficeUsecase.CurrentShift(r.Context())
+	if err != nil && err.Error() == utils.ErrNotFound("shift").Error() {
+		h.Logger.Error("Error shift not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "no open shift", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get current shift: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get shift failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get shift success", result)
+}
+
+func (h *BoxOfficeHandler) CloseShift(w http.ResponseWriter, r *http.Request) {
+	var req dto.CloseShiftRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto close shift request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute close shift
+	result, err := h.Usecase.BoxOfficeUsecase.CloseShift(r.Context(), req)
+	if err != nil && err.Error() == utils.ErrNotFound("shift").Error() {
+		h.Logger.Error("Error shift not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "no open shift", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling close shift: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "close shift failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "close shift success", result)
+}
+
+func (h *BoxOfficeHandler) GetShift(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid shift id", err.Error())
+		return
+	}
+
+	// Execute get shift
+	result, err := h.Usecase.BoxOfficeUsecase.GetShift(r.Context(), id)
+	if err != nil && err.Error() == utils.ErrNotFound("shift").Error() {
+		h.Logger.Error("Error shift not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "shift not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get shift: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get shift failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get shift success", result)
+}
+
+func (h *BoxOfficeHandler) GetReports(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	query := dto.ShiftQuery{
+		Date: strings.TrimSpace(r.URL.Query().Get("date")),
+	}
+	if cashierStr := r.URL.Query().Get("cashierId"); cashierStr != "" {
+		cashierID, err := strconv.Atoi(cashierStr)
+		if err != nil || cashierID <= 0 {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "cashierId must be a positive number")
+			return
+		}
+		query.CashierID = cashierID
+	}
+
+	// Execute get shift reports
+	result, err := h.Usecase.BoxOfficeUsecase.Reports(query)
+	if err != nil {
+		h.Logger.Error("Error handling get shift reports: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get shift reports failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get shift reports success", result)
+}
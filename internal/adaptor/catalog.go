@@ -0,0 +1,113 @@
+package adaptor
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Upper bound for a single catalog file
+const catalogMaxBytes = 10 << 20
+
+type CatalogHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewCatalogHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) CatalogHandler {
+	return CatalogHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *CatalogHandler) Import(w http.ResponseWriter, r *http.Request) {
+	// Retrieve kind and format
+	kind := r.PathValue("kind")
+	format := catalogFormat(r)
+	if format == "" {
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", "format must be csv or json")
+		return
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	// Limit body
+	r.Body = http.MaxBytesReader(w, r.Body, catalogMaxBytes)
+
+	// Execute import catalog
+	result, err := h.Usecase.CatalogUsecase.Import(kind, format, r.Body, dryRun)
+	if err != nil {
+		h.Logger.Error("Error handling import catalog: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "import catalog failed", err.Error())
+		return
+	}
+
+	// Nothing is written when any row fails, the report explains why
+	if result.Failed > 0 {
+		utils.ResponseFailed(w, http.StatusUnprocessableEntity, "import catalog has invalid rows", result)
+		return
+	}
+	if dryRun {
+		utils.ResponseSuccess(w, http.StatusOK, "import catalog validated", result)
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "import catalog success", result)
+}
+
+func (h *CatalogHandler) Export(w http.ResponseWriter, r *http.Request) {
+	// Retrieve kind and format
+	kind := r.PathValue("kind")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", "format must be csv or json")
+		return
+	}
+
+	// Execute export catalog
+	data, err := h.Usecase.CatalogUsecase.Export(kind, format, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		h.Logger.Error("Error handling export catalog: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "export catalog failed", err.Error())
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == "json" {
+		contentType = "application/json"
+	}
+	filename := fmt.Sprintf("%s-%s.%s", kind, time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// catalogFormat prefers the format query and falls back to the content type
+func catalogFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		if format == "csv" || format == "json" {
+			return format
+		}
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return "csv"
+	case "application/json":
+		return "json"
+	}
+	return ""
+}
@@ -2,8 +2,10 @@ package adaptor
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/internal/usecase"
@@ -33,8 +35,14 @@ func (h *CinemaHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	query := dto.CinemaQuery{
+		PaginationQuery: q,
+		City: strings.TrimSpace(r.URL.Query().Get("city")),
+		Amenities: amenitiesParam(r),
+	}
+
 	// Execute get cinemas
-	result, pagination, err := h.Usecase.CinemaUsecase.GetAll(q)
+	result, pagination, err := h.Usecase.CinemaUsecase.GetAll(query)
 	if err != nil {
 		h.Logger.Error("Error handling get cinemas: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusBadRequest, "get cinemas failed", err.Error())
@@ -44,6 +52,51 @@ func (h *CinemaHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	utils.ResponseWithPagination(w, http.StatusOK, "get cinemas success", result, pagination)
 }
 
+func (h *CinemaHandler) GetNearest(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	query := dto.NearestCinemaQuery{
+		PaginationQuery: q,
+		City: strings.TrimSpace(r.URL.Query().Get("city")),
+		Amenities: amenitiesParam(r),
+	}
+
+	// Coordinates are required, radius is optional
+	query.Latitude, err = strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	if err != nil || math.IsNaN(query.Latitude) || query.Latitude < -90 || query.Latitude > 90 {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "lat must be between -90 and 90")
+		return
+	}
+	query.Longitude, err = strconv.ParseFloat(r.URL.Query().Get("lng"), 64)
+	if err != nil || math.IsNaN(query.Longitude) || query.Longitude < -180 || query.Longitude > 180 {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "lng must be between -180 and 180")
+		return
+	}
+	radiusStr := r.URL.Query().Get("radiusKm")
+	if radiusStr != "" {
+		query.RadiusKm, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil || math.IsNaN(query.RadiusKm) || query.RadiusKm <= 0 {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "radiusKm must be a positive number")
+			return
+		}
+	}
+
+	// Execute get nearest cinemas
+	result, pagination, err := h.Usecase.CinemaUsecase.GetNearest(query)
+	if err != nil {
+		h.Logger.Error("Error handling get nearest cinemas: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get nearest cinemas failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "get nearest cinemas success", result, pagination)
+}
+
 func (h *CinemaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Retrieve id
 	idStr := r.PathValue("id")
@@ -55,7 +108,7 @@ func (h *CinemaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute get cinemas
-	result, err := h.Usecase.CinemaUsecase.GetByID(id)
+	result, err := h.Usecase.CinemaUsecase.GetByID(id, utils.GetLocale(r, h.Config))
 	if err != nil && err.Error() == utils.ErrNotFound("cinema").Error() {
 		h.Logger.Error("Error cinema not found: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusNotFound, "cinema not found", err.Error())
@@ -163,4 +216,127 @@ func (h *CinemaHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "delete cinema success", nil)
-}
\ No newline at end of file
+}
+
+func (h *CinemaHandler) GetAmenities(w http.ResponseWriter, r *http.Request) {
+	// Execute get amenities
+	result, err := h.Usecase.CinemaUsecase.GetAmenities()
+	if err != nil {
+		h.Logger.Error("Error handling get amenities: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get amenities failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get amenities success", result)
+}
+
+func (h *CinemaHandler) GetClosures(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get closures
+	result, err := h.Usecase.CinemaUsecase.GetClosures(id)
+	if err != nil && err.Error() == utils.ErrNotFound("cinema").Error() {
+		h.Logger.Error("Error cinema not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "cinema not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get cinema closures: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get cinema closures failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get cinema closures success", result)
+}
+
+func (h *CinemaHandler) CreateClosure(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.CinemaClosureRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto cinema closure request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute create closure
+	result, err := h.Usecase.CinemaUsecase.CreateClosure(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("cinema").Error() {
+		h.Logger.Error("Error cinema not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "cinema not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling create cinema closure: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "create cinema closure failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "create cinema closure success", result)
+}
+
+func (h *CinemaHandler) DeleteClosure(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+	closureID, err := strconv.Atoi(r.PathValue("closureId"))
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete closure
+	err = h.Usecase.CinemaUsecase.DeleteClosure(id, closureID)
+	if err != nil && err.Error() == utils.ErrNotFound("closure").Error() {
+		h.Logger.Error("Error cinema closure not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "closure not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete cinema closure: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete cinema closure failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete cinema closure success", nil)
+}
+
+// amenitiesParam reads a comma separated amenities filter
+func amenitiesParam(r *http.Request) []string {
+	var amenities []string
+	for _, code := range strings.Split(r.URL.Query().Get("amenities"), ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code != "" {
+			amenities = append(amenities, code)
+		}
+	}
+	return amenities
+}
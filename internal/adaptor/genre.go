@@ -5,7 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/internal/usecase"
 	"github.com/project-app-bioskop-golang/pkg/utils"
 	"go.uber.org/zap"
@@ -70,7 +70,7 @@ func (h *GenreHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *GenreHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var req entity.Genre
+	var req dto.GenreRequest
 
 	// Decode request body
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -97,6 +97,48 @@ func (h *GenreHandler) Create(w http.ResponseWriter, r *http.Request) {
 	utils.ResponseSuccess(w, http.StatusCreated, "create genre success", result)
 }
 
+func (h *GenreHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.GenreRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto genre request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute update genre
+	result, err := h.Usecase.GenreUsecase.Update(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("genre").Error() {
+		h.Logger.Error("Error genre not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "genre not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update genre: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update genre failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update genre success", result)
+}
+
 func (h *GenreHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	// Retrieve id
 	idStr := r.PathValue("id")
@@ -121,4 +163,46 @@ func (h *GenreHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "delete genre success", nil)
-}
\ No newline at end of file
+}
+
+func (h *GenreHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.MergeGenreRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto merge genre request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute merge genres
+	result, err := h.Usecase.GenreUsecase.Merge(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("genre").Error() {
+		h.Logger.Error("Error genre not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "genre not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling merge genres: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "merge genres failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "merge genres success", result)
+}
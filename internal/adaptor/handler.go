@@ -8,6 +8,7 @@ import (
 
 type Handler struct{
 	AuthHandler AuthHandler
+	UserHandler UserHandler
 	CinemaHandler CinemaHandler
 	StudioHandler StudioHandler
 	GenreHandler GenreHandler
@@ -16,11 +17,24 @@ type Handler struct{
 	SeatHandler SeatHandler
 	BookingHandler BookingHandler
 	PaymentHandler PaymentHandler
+	PrivacyHandler PrivacyHandler
+	TwoFactorHandler TwoFactorHandler
+	APIKeyHandler APIKeyHandler
+	MovieRunHandler MovieRunHandler
+	PersonHandler PersonHandler
+	ReviewHandler ReviewHandler
+	MediaHandler MediaHandler
+	CatalogHandler CatalogHandler
+	TranslationHandler TranslationHandler
+	WaitlistHandler WaitlistHandler
+	TicketHandler TicketHandler
+	BoxOfficeHandler BoxOfficeHandler
 }
 
 func NewHandler(uc usecase.Usecase, log *zap.Logger, config utils.Configuration) Handler {
 	return Handler{
 		AuthHandler: NewAuthHandler(uc, log, config),
+		UserHandler: NewUserHandler(uc, log, config),
 		CinemaHandler: NewCinemaHandler(uc, log, config),
 		StudioHandler: NewStudioHandler(uc, log, config),
 		GenreHandler: NewGenreHandler(uc, log, config),
@@ -29,5 +43,17 @@ func NewHandler(uc usecase.Usecase, log *zap.Logger, config utils.Configuration)
 		SeatHandler: NewSeatHandler(uc, log, config),
 		BookingHandler: NewBookingHandler(uc, log, config),
 		PaymentHandler: NewPaymentHandler(uc, log, config),
+		PrivacyHandler: NewPrivacyHandler(uc, log, config),
+		TwoFactorHandler: NewTwoFactorHandler(uc, log, config),
+		APIKeyHandler: NewAPIKeyHandler(uc, log, config),
+		MovieRunHandler: NewMovieRunHandler(uc, log, config),
+		PersonHandler: NewPersonHandler(uc, log, config),
+		ReviewHandler: NewReviewHandler(uc, log, config),
+		MediaHandler: NewMediaHandler(uc, log, config),
+		CatalogHandler: NewCatalogHandler(uc, log, config),
+		TranslationHandler: NewTranslationHandler(uc, log, config),
+		WaitlistHandler: NewWaitlistHandler(uc, log, config),
+		TicketHandler: NewTicketHandler(uc, log, config),
+		BoxOfficeHandler: NewBoxOfficeHandler(uc, log, config),
 	}
 }
\ No newline at end of file
@@ -0,0 +1,266 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type MediaHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewMediaHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) MediaHandler {
+	return MediaHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	// Limit body, multipart framing gets some headroom over the file limit
+	r.Body = http.MaxBytesReader(w, r.Body, h.Config.MediaMaxSize+1<<20)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		h.Logger.Error("Error parse multipart form: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	// Retrieve file
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", "file is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.Config.MediaMaxSize+1))
+	if err != nil {
+		h.Logger.Error("Error read uploaded file: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute upload media
+	result, err := h.Usecase.MediaUsecase.Upload(user.ID, header.Filename, data)
+	if err != nil {
+		h.Logger.Error("Error handling upload media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "upload media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "upload media success", result)
+}
+
+func (h *MediaHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get media
+	result, err := h.Usecase.MediaUsecase.GetByID(id)
+	if err != nil && err.Error() == utils.ErrNotFound("media").Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "media not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get media success", result)
+}
+
+func (h *MediaHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id and variant
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+	variant := r.PathValue("variant")
+
+	// Execute open media file
+	obj, v, modTime, err := h.Usecase.MediaUsecase.Open(id, variant)
+	if err != nil && err.Error() == utils.ErrNotFound("media").Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "media not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling serve media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusInternalServerError, "serve media failed", err.Error())
+		return
+	}
+	defer obj.Close()
+
+	// Files never change once stored, a new upload gets a new id
+	w.Header().Set("Content-Type", v.MimeType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", fmt.Sprintf(`"media-%d-%s"`, id, v.Variant))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", modTime, obj)
+}
+
+func (h *MediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete media
+	err = h.Usecase.MediaUsecase.Delete(id)
+	if err != nil && err.Error() == utils.ErrNotFound("media").Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "media not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete media success", nil)
+}
+
+// Linked images for movies and cinemas
+func (h *MediaHandler) GetMovieMedia(w http.ResponseWriter, r *http.Request) {
+	h.getLinks(w, r, "movie")
+}
+
+func (h *MediaHandler) LinkMovieMedia(w http.ResponseWriter, r *http.Request) {
+	h.link(w, r, "movie")
+}
+
+func (h *MediaHandler) UnlinkMovieMedia(w http.ResponseWriter, r *http.Request) {
+	h.unlink(w, r, "movie")
+}
+
+func (h *MediaHandler) GetCinemaMedia(w http.ResponseWriter, r *http.Request) {
+	h.getLinks(w, r, "cinema")
+}
+
+func (h *MediaHandler) LinkCinemaMedia(w http.ResponseWriter, r *http.Request) {
+	h.link(w, r, "cinema")
+}
+
+func (h *MediaHandler) UnlinkCinemaMedia(w http.ResponseWriter, r *http.Request) {
+	h.unlink(w, r, "cinema")
+}
+
+func (h *MediaHandler) getLinks(w http.ResponseWriter, r *http.Request, entityType string) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get media links
+	result, err := h.Usecase.MediaUsecase.GetLinks(entityType, id)
+	if err != nil {
+		h.Logger.Error("Error handling get media links: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get media success", result)
+}
+
+func (h *MediaHandler) link(w http.ResponseWriter, r *http.Request, entityType string) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.MediaLinkRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto media link request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute link media
+	err = h.Usecase.MediaUsecase.Link(entityType, id, req)
+	if err != nil && (err.Error() == utils.ErrNotFound(entityType).Error() || err.Error() == utils.ErrNotFound("media").Error()) {
+		utils.ResponseFailed(w, http.StatusNotFound, entityType+" or media not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling link media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "link media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "link media success", nil)
+}
+
+func (h *MediaHandler) unlink(w http.ResponseWriter, r *http.Request, entityType string) {
+	// Retrieve ids
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+	mediaID, err := strconv.Atoi(r.PathValue("mediaId"))
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute unlink media
+	err = h.Usecase.MediaUsecase.Unlink(entityType, id, mediaID)
+	if err != nil && err.Error() == utils.ErrNotFound("media").Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "media not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling unlink media: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "unlink media failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "unlink media success", nil)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/internal/usecase"
@@ -38,6 +39,7 @@ func (h *MovieHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	query.Page = q.Page
 	query.Limit = q.Limit
 	query.All = q.All
+	query.Locale = q.Locale
 	query.Genre = genre
 
 	// Execute get movies
@@ -51,6 +53,63 @@ func (h *MovieHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	utils.ResponseWithPagination(w, http.StatusOK, "get movies success", result, pagination)
 }
 
+func (h *MovieHandler) Search(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	params := r.URL.Query()
+	var query dto.MovieSearchQuery
+	query.Page = q.Page
+	query.Limit = q.Limit
+	query.All = q.All
+	query.Locale = q.Locale
+	query.Keyword = strings.TrimSpace(params.Get("q"))
+	query.Genre = params.Get("genre")
+	query.Language = params.Get("language")
+	query.RatingAge = params.Get("ratingAge")
+	query.ReleasedFrom = params.Get("releasedFrom")
+	query.ReleasedTo = params.Get("releasedTo")
+	query.Date = params.Get("date")
+	query.Sort = params.Get("sort")
+	query.Order = strings.ToLower(params.Get("order"))
+
+	// Numeric filters
+	for key, target := range map[string]*int{
+		"minDuration": &query.MinDuration,
+		"maxDuration": &query.MaxDuration,
+		"cinemaId":    &query.CinemaID,
+	} {
+		value := params.Get(key)
+		if value == "" {
+			continue
+		}
+		*target, err = strconv.Atoi(value)
+		if err != nil {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+			return
+		}
+	}
+
+	if query.Order != "" && query.Order != "asc" && query.Order != "desc" {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "order must be asc or desc")
+		return
+	}
+
+	// Execute search movies
+	result, pagination, err := h.Usecase.MovieUsecase.Search(query)
+	if err != nil {
+		h.Logger.Error("Error handling search movies: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "search movies failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "search movies success", result, pagination)
+}
+
 func (h *MovieHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Retrieve id
 	idStr := r.PathValue("id")
@@ -62,7 +121,7 @@ func (h *MovieHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute get movies
-	result, err := h.Usecase.MovieUsecase.GetByID(id)
+	result, err := h.Usecase.MovieUsecase.GetByID(id, utils.GetLocale(r, h.Config))
 	if err != nil && err.Error() == utils.ErrNotFound("movie").Error() {
 		h.Logger.Error("Error movie not found: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusNotFound, "movie not found", err.Error())
@@ -170,4 +229,45 @@ func (h *MovieHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "delete movie success", nil)
-}
\ No newline at end of file
+}
+func (h *MovieHandler) SetCredits(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.MovieCreditsRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto movie credits request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute set movie credits
+	result, err := h.Usecase.MovieUsecase.SetCredits(id, req)
+	if err != nil && (err.Error() == utils.ErrNotFound("movie").Error() || err.Error() == utils.ErrNotFound("person").Error()) {
+		h.Logger.Error("Error movie or person not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie or person not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling set movie credits: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "set movie credits failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "set movie credits success", result)
+}
@@ -0,0 +1,196 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type MovieRunHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewMovieRunHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) MovieRunHandler {
+	return MovieRunHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *MovieRunHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.MovieRunRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto movie run request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute create movie run
+	result, err := h.Usecase.MovieRunUsecase.Create(req)
+	if err != nil && (err.Error() == utils.ErrNotFound("movie").Error() || err.Error() == utils.ErrNotFound("cinema").Error()) {
+		h.Logger.Error("Error movie or cinema not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie or cinema not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling create movie run: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "create movie run failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "create movie run success", result)
+}
+
+func (h *MovieRunHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.MovieRunRequest
+
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto movie run request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute update movie run
+	err = h.Usecase.MovieRunUsecase.Update(id, req)
+	if err != nil && (err.Error() == utils.ErrNotFound("movie run").Error() ||
+		err.Error() == utils.ErrNotFound("movie").Error() || err.Error() == utils.ErrNotFound("cinema").Error()) {
+		h.Logger.Error("Error movie run not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie run not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update movie run: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update movie run failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update movie run success", nil)
+}
+
+func (h *MovieRunHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete movie run
+	err = h.Usecase.MovieRunUsecase.Delete(id)
+	if err != nil && err.Error() == utils.ErrNotFound("movie run").Error() {
+		h.Logger.Error("Error movie run not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie run not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete movie run: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete movie run failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete movie run success", nil)
+}
+
+func (h *MovieRunHandler) GetByMovie(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get movie runs
+	result, err := h.Usecase.MovieRunUsecase.GetByMovie(id)
+	if err != nil {
+		h.Logger.Error("Error handling get movie runs: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get movie runs failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get movie runs success", result)
+}
+
+func (h *MovieRunHandler) NowShowing(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query param
+	cinemaID, err := cinemaIDParam(r)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	// Execute get now showing movies
+	result, err := h.Usecase.MovieRunUsecase.NowShowing(cinemaID, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling get now showing movies: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get now showing movies failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get now showing movies success", result)
+}
+
+func (h *MovieRunHandler) ComingSoon(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query param
+	cinemaID, err := cinemaIDParam(r)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	// Execute get coming soon movies
+	result, err := h.Usecase.MovieRunUsecase.ComingSoon(cinemaID, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling get coming soon movies: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get coming soon movies failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get coming soon movies success", result)
+}
+
+// cinemaIDParam reads the optional cinemaId query param, 0 means all cinemas
+func cinemaIDParam(r *http.Request) (int, error) {
+	cinemaIDStr := r.URL.Query().Get("cinemaId")
+	if cinemaIDStr == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(cinemaIDStr)
+}
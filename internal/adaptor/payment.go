@@ -52,6 +52,12 @@ func (h *PaymentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Restrict api keys to their cinemas
+	if err := allowBookingForKey(h.Usecase, r, req.BookingID); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
 	// Execute create payment
 	result, err := h.Usecase.PaymentUsecase.Create(req)
 	if err != nil {
@@ -0,0 +1,194 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type PersonHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewPersonHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) PersonHandler {
+	return PersonHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *PersonHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+	name := r.URL.Query().Get("name")
+
+	// Execute get people
+	result, pagination, err := h.Usecase.PersonUsecase.GetAll(q, name)
+	if err != nil {
+		h.Logger.Error("Error handling get people: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get people failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "get people success", result, pagination)
+}
+
+func (h *PersonHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get person
+	result, err := h.Usecase.PersonUsecase.GetByID(id)
+	if err != nil && err.Error() == utils.ErrNotFound("person").Error() {
+		h.Logger.Error("Error person not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "person not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get person by id: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get person failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get person success", result)
+}
+
+func (h *PersonHandler) GetMovies(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get movies featuring person
+	result, err := h.Usecase.PersonUsecase.GetMovies(id, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("person").Error() {
+		h.Logger.Error("Error person not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "person not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get person movies: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get person movies failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get person movies success", result)
+}
+
+func (h *PersonHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.PersonRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto person request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute create person
+	result, err := h.Usecase.PersonUsecase.Create(req)
+	if err != nil {
+		h.Logger.Error("Error handling create person: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "create person failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "create person success", result)
+}
+
+func (h *PersonHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.PersonRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto person request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute update person
+	err = h.Usecase.PersonUsecase.Update(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("person").Error() {
+		h.Logger.Error("Error person not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "person not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update person: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update person failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update person success", nil)
+}
+
+func (h *PersonHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete person
+	err = h.Usecase.PersonUsecase.Delete(id)
+	if err != nil && err.Error() == utils.ErrNotFound("person").Error() {
+		h.Logger.Error("Error person not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "person not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete person: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete person failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete person success", nil)
+}
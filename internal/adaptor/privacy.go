@@ -0,0 +1,177 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type PrivacyHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewPrivacyHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) PrivacyHandler {
+	return PrivacyHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *PrivacyHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute request export
+	result, err := h.Usecase.PrivacyUsecase.RequestExport(user.ID)
+	if err != nil {
+		h.Logger.Error("Error handling request data export: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "request data export failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusAccepted, "data export is being prepared", result)
+}
+
+func (h *PrivacyHandler) GetExports(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get exports
+	result, err := h.Usecase.PrivacyUsecase.GetExports(user.ID)
+	if err != nil {
+		h.Logger.Error("Error handling get data exports: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get data exports failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get data exports success", result)
+}
+
+func (h *PrivacyHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get export
+	result, err := h.Usecase.PrivacyUsecase.GetExport(user.ID, id)
+	if err != nil && err.Error() == utils.ErrNotFound("data export").Error() {
+		h.Logger.Error("Error data export not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "data export not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get data export: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get data export failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get data export success", result)
+}
+
+func (h *PrivacyHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get export file
+	path, err := h.Usecase.PrivacyUsecase.GetExportFile(user.ID, id)
+	if err != nil && err.Error() == utils.ErrNotFound("data export").Error() {
+		h.Logger.Error("Error data export not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "data export not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling download data export: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "download data export failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filepath.Base(path)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeFile(w, r, path)
+}
+
+func (h *PrivacyHandler) EraseAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteAccountRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto delete account request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute erase account
+	err = h.Usecase.PrivacyUsecase.EraseAccount(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling erase account: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "erase account failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "erase account success", nil)
+}
+
+func (h *PrivacyHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute erase user
+	err = h.Usecase.PrivacyUsecase.Erase(id)
+	if err != nil && err.Error() == utils.ErrNotFound("user").Error() {
+		h.Logger.Error("Error user not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "user not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling erase user: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "erase user failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "erase user success", nil)
+}
@@ -0,0 +1,284 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type ReviewHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewReviewHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) ReviewHandler {
+	return ReviewHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	// Retrieve movie id
+	idStr := r.PathValue("id")
+	movieID, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.ReviewRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto review request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute create review
+	result, err := h.Usecase.ReviewUsecase.Create(user.ID, movieID, req)
+	if err == usecase.ErrReviewNotAllowed {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+	if err != nil && err.Error() == utils.ErrNotFound("movie").Error() {
+		h.Logger.Error("Error movie not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling create review: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "create review failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "create review success, it will be published after moderation", result)
+}
+
+func (h *ReviewHandler) GetByMovie(w http.ResponseWriter, r *http.Request) {
+	// Retrieve movie id
+	idStr := r.PathValue("id")
+	movieID, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	// Execute get movie reviews
+	result, pagination, err := h.Usecase.ReviewUsecase.GetByMovie(movieID, q)
+	if err != nil {
+		h.Logger.Error("Error handling get movie reviews: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get reviews failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "get reviews success", result, pagination)
+}
+
+func (h *ReviewHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get user reviews
+	result, err := h.Usecase.ReviewUsecase.GetByUser(user.ID)
+	if err != nil {
+		h.Logger.Error("Error handling get user reviews: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get reviews failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get reviews success", result)
+}
+
+func (h *ReviewHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+	status := r.URL.Query().Get("status")
+
+	// Execute get reviews
+	result, pagination, err := h.Usecase.ReviewUsecase.GetAll(q, status)
+	if err != nil {
+		h.Logger.Error("Error handling get reviews: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get reviews failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "get reviews success", result, pagination)
+}
+
+func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.ReviewRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto review request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute update review
+	err = h.Usecase.ReviewUsecase.Update(id, user.ID, req)
+	if err != nil && err.Error() == utils.ErrNotFound("review").Error() {
+		h.Logger.Error("Error review not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "review not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update review: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update review failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update review success, it will be published after moderation", nil)
+}
+
+func (h *ReviewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute delete review
+	err = h.Usecase.ReviewUsecase.Delete(id, user.ID)
+	if err != nil && err.Error() == utils.ErrNotFound("review").Error() {
+		h.Logger.Error("Error review not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "review not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete review: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete review failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete review success", nil)
+}
+
+func (h *ReviewHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute remove review
+	err = h.Usecase.ReviewUsecase.Remove(id)
+	if err != nil && err.Error() == utils.ErrNotFound("review").Error() {
+		h.Logger.Error("Error review not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "review not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling remove review: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "remove review failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "remove review success", nil)
+}
+
+func (h *ReviewHandler) Moderate(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto moderate review request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute moderate review
+	err = h.Usecase.ReviewUsecase.Moderate(id, user.ID, req)
+	if err != nil && err.Error() == utils.ErrNotFound("review").Error() {
+		h.Logger.Error("Error review not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "review not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling moderate review: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "moderate review failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "moderate review success", nil)
+}
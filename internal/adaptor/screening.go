@@ -2,8 +2,10 @@ package adaptor
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/internal/usecase"
@@ -37,6 +39,7 @@ func (h *ScreeningHandler) GetByCinema(w http.ResponseWriter, r *http.Request) {
 	query.Page = q.Page
 	query.Limit = q.Limit
 	query.All = q.All
+	query.Locale = q.Locale
 	query.Date = r.URL.Query().Get("date")
 
 	cinemaIDStr := r.URL.Query().Get("cinemaId")
@@ -57,6 +60,13 @@ func (h *ScreeningHandler) GetByCinema(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Screening attribute filters
+	query.Format = strings.ToUpper(r.URL.Query().Get("format"))
+	query.AudioLanguage = r.URL.Query().Get("audio")
+	query.SubtitleLanguage = r.URL.Query().Get("subtitle")
+	query.AudioDescription = r.URL.Query().Get("audioDescription") == "true"
+	query.ClosedCaptions = r.URL.Query().Get("closedCaptions") == "true"
+
 	// Execute get screenings
 	result, pagination, err := h.Usecase.ScreeningUsecase.GetByCinema(query)
 	if err != nil {
@@ -68,6 +78,74 @@ func (h *ScreeningHandler) GetByCinema(w http.ResponseWriter, r *http.Request) {
 	utils.ResponseWithPagination(w, http.StatusOK, "get screenings success", result, pagination)
 }
 
+func (h *ScreeningHandler) SearchShowtimes(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	var query dto.ScreeningQuery
+	var err error
+	query.Locale = utils.GetLocale(r, h.Config)
+	query.Date = r.URL.Query().Get("date")
+	query.DateTo = r.URL.Query().Get("dateTo")
+	query.City = strings.TrimSpace(r.URL.Query().Get("city"))
+
+	query.MovieID, err = strconv.Atoi(r.URL.Query().Get("movieId"))
+	if err != nil || query.MovieID <= 0 {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "movieId is required")
+		return
+	}
+
+	// Near me needs both coordinates, radius is optional
+	latStr := r.URL.Query().Get("lat")
+	lngStr := r.URL.Query().Get("lng")
+	if latStr != "" || lngStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "lat must be between -90 and 90")
+			return
+		}
+		lng, err := strconv.ParseFloat(lngStr, 64)
+		if err != nil || math.IsNaN(lng) || lng < -180 || lng > 180 {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "lng must be between -180 and 180")
+			return
+		}
+		query.Latitude = &lat
+		query.Longitude = &lng
+	}
+	radiusStr := r.URL.Query().Get("radiusKm")
+	if radiusStr != "" {
+		if query.Latitude == nil {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "radiusKm needs lat and lng")
+			return
+		}
+		query.RadiusKm, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil || math.IsNaN(query.RadiusKm) || query.RadiusKm <= 0 {
+			utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "radiusKm must be a positive number")
+			return
+		}
+	}
+
+	// Screening attribute filters
+	query.Format = strings.ToUpper(r.URL.Query().Get("format"))
+	query.AudioLanguage = r.URL.Query().Get("audio")
+	query.SubtitleLanguage = r.URL.Query().Get("subtitle")
+	query.AudioDescription = r.URL.Query().Get("audioDescription") == "true"
+	query.ClosedCaptions = r.URL.Query().Get("closedCaptions") == "true"
+
+	// Execute search showtimes
+	result, err := h.Usecase.ScreeningUsecase.SearchShowtimes(query)
+	if err != nil && err.Error() == utils.ErrNotFound("movie").Error() {
+		h.Logger.Error("Error movie not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "movie not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling search showtimes: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "search showtimes failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "search showtimes success", result)
+}
+
 func (h *ScreeningHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	// Retrieve id
 	idStr := r.PathValue("id")
@@ -79,7 +157,7 @@ func (h *ScreeningHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute get screenings
-	result, err := h.Usecase.ScreeningUsecase.GetByID(id)
+	result, err := h.Usecase.ScreeningUsecase.GetByID(id, utils.GetLocale(r, h.Config))
 	if err != nil && err.Error() == utils.ErrNotFound("screening").Error() {
 		h.Logger.Error("Error screening not found: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusNotFound, "screening not found", err.Error())
@@ -187,4 +265,52 @@ func (h *ScreeningHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "delete screening success", nil)
-}
\ No newline at end of file
+}
+
+func (h *ScreeningHandler) GetFormats(w http.ResponseWriter, r *http.Request) {
+	// Execute get screening formats
+	result, err := h.Usecase.ScreeningUsecase.GetFormats()
+	if err != nil {
+		h.Logger.Error("Error handling get screening formats: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get screening formats failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get screening formats success", result)
+}
+
+func (h *ScreeningHandler) UpdateFormat(w http.ResponseWriter, r *http.Request) {
+	// Retrieve code
+	code := r.PathValue("code")
+
+	var req dto.ScreeningFormatRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto screening format request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute update screening format
+	result, err := h.Usecase.ScreeningUsecase.UpdateFormat(code, req)
+	if err != nil && err.Error() == utils.ErrNotFound("screening format").Error() {
+		h.Logger.Error("Error screening format not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "screening format not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update screening format: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update screening format failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update screening format success", result)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/internal/usecase"
 	"github.com/project-app-bioskop-golang/pkg/utils"
 	"go.uber.org/zap"
@@ -37,6 +38,12 @@ func (h *SeatHandler) GetSeatsByScreening(w http.ResponseWriter, r *http.Request
 		}
 	} 
 
+	// Restrict api keys to their cinemas
+	if err := allowScreeningForKey(h.Usecase, r, screeningID); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
 	// Execute get available seats
 	result, err := h.Usecase.SeatUsecase.GetSeats(screeningID)
 	if err != nil {
@@ -46,4 +53,39 @@ func (h *SeatHandler) GetSeatsByScreening(w http.ResponseWriter, r *http.Request
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "get available seats success", result)
-}
\ No newline at end of file
+}
+
+func (h *SeatHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	var q dto.SeatSuggestionQuery
+	var err error
+	q.ScreeningID, err = strconv.Atoi(r.URL.Query().Get("screeningId"))
+	if err != nil {
+		h.Logger.Error("Error retrieve screening ID query param: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "screeningId is required")
+		return
+	}
+	q.PartySize, err = strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || q.PartySize < 1 || q.PartySize > 10 {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", "size must be between 1 and 10")
+		return
+	}
+	q.Aisle = r.URL.Query().Get("aisle") == "true"
+	q.Accessible = r.URL.Query().Get("accessible") == "true"
+
+	// Restrict api keys to their cinemas
+	if err := allowScreeningForKey(h.Usecase, r, q.ScreeningID); err != nil {
+		utils.ResponseFailed(w, http.StatusForbidden, "access denied", err.Error())
+		return
+	}
+
+	// Execute suggest seats
+	result, err := h.Usecase.SeatUsecase.Suggest(q)
+	if err != nil {
+		h.Logger.Error("Error handling suggest seats: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "suggest seats failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "suggest seats success", result)
+}
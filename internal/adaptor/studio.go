@@ -55,7 +55,7 @@ func (h *StudioHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute get studios
-	result, err := h.Usecase.StudioUsecase.GetByID(id)
+	result, err := h.Usecase.StudioUsecase.GetByID(id, utils.GetLocale(r, h.Config))
 	if err != nil && err.Error() == utils.ErrNotFound("studio").Error() {
 		h.Logger.Error("Error studio not found: ", zap.Error(err))
 		utils.ResponseFailed(w, http.StatusNotFound, "studio not found", err.Error())
@@ -191,4 +191,46 @@ func (h *StudioHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	utils.ResponseSuccess(w, http.StatusOK, "delete studio success", nil)
-}
\ No newline at end of file
+}
+
+func (h *StudioHandler) SetAccessibleSeats(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.AccessibleSeatsRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto accessible seats request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute set accessible seats
+	err = h.Usecase.StudioUsecase.SetAccessibleSeats(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("studio").Error() {
+		h.Logger.Error("Error studio not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "studio not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling set accessible seats: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "set accessible seats failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "set accessible seats success", nil)
+}
@@ -0,0 +1,187 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TicketHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewTicketHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) TicketHandler {
+	return TicketHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *TicketHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get user tickets
+	result, err := h.Usecase.TicketUsecase.GetByHolder(user.ID, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling get user tickets: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get tickets failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get tickets success", result)
+}
+
+func (h *TicketHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	var req dto.CheckInRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto check in request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute check in
+	result, err := h.Usecase.TicketUsecase.CheckIn(req, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("ticket").Error() {
+		h.Logger.Error("Error ticket not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "ticket not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling check in: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "check in failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "check in success", result)
+}
+
+func (h *TicketHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	// Retrieve ticket id
+	idStr := r.PathValue("id")
+	ticketID, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.TransferRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto transfer request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute transfer ticket
+	result, err := h.Usecase.TicketUsecase.Transfer(r.Context(), ticketID, req, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("ticket").Error() {
+		h.Logger.Error("Error ticket not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "ticket not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling transfer ticket: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "transfer ticket failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "transfer ticket success, waiting for the recipient to accept", result)
+}
+
+func (h *TicketHandler) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get user transfers
+	result, err := h.Usecase.TicketUsecase.GetTransfers(user.ID, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling get ticket transfers: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get transfers failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get transfers success", result)
+}
+
+func (h *TicketHandler) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute accept transfer
+	result, err := h.Usecase.TicketUsecase.AcceptTransfer(r.Context(), id, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("transfer").Error() {
+		h.Logger.Error("Error transfer not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "transfer not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling accept transfer: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "accept transfer failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "accept transfer success, the ticket was sent to your email", result)
+}
+
+func (h *TicketHandler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute cancel transfer
+	err = h.Usecase.TicketUsecase.CancelTransfer(id, user.ID)
+	if err != nil && err.Error() == utils.ErrNotFound("transfer").Error() {
+		h.Logger.Error("Error transfer not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "transfer not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling cancel transfer: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "cancel transfer failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "cancel transfer success", nil)
+}
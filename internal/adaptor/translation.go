@@ -0,0 +1,122 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TranslationHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewTranslationHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) TranslationHandler {
+	return TranslationHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *TranslationHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Retrieve kind and id
+	kind := r.PathValue("kind")
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get translations
+	result, err := h.Usecase.TranslationUsecase.GetAll(kind, id)
+	if err != nil && err.Error() == utils.ErrNotFound(usecase.TranslationRecord(kind)).Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "record not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get translations: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get translations failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get translations success", result)
+}
+
+func (h *TranslationHandler) Save(w http.ResponseWriter, r *http.Request) {
+	// Retrieve kind, id and locale
+	kind := r.PathValue("kind")
+	locale := r.PathValue("locale")
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.TranslationRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto translation request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute save translation
+	result, err := h.Usecase.TranslationUsecase.Save(kind, id, locale, req.Fields)
+	if err != nil && err.Error() == utils.ErrNotFound(usecase.TranslationRecord(kind)).Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "record not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling save translation: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "save translation failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "save translation success", result)
+}
+
+func (h *TranslationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve kind, id and locale
+	kind := r.PathValue("kind")
+	locale := r.PathValue("locale")
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete translation
+	err = h.Usecase.TranslationUsecase.Delete(kind, id, locale)
+	if err != nil && err.Error() == utils.ErrNotFound("translation").Error() {
+		utils.ResponseFailed(w, http.StatusNotFound, "translation not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete translation: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete translation failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete translation success", nil)
+}
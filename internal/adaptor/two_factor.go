@@ -0,0 +1,149 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TwoFactorHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewTwoFactorHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) TwoFactorHandler {
+	return TwoFactorHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *TwoFactorHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get two factor status
+	result, err := h.Usecase.TwoFactorUsecase.GetStatus(user)
+	if err != nil {
+		h.Logger.Error("Error handling get two factor status: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get two factor status failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get two factor status success", result)
+}
+
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute setup two factor
+	result, err := h.Usecase.TwoFactorUsecase.Setup(user)
+	if err != nil {
+		h.Logger.Error("Error handling setup two factor: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "setup two factor failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "scan the QR code and confirm with a code", result)
+}
+
+func (h *TwoFactorHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorCodeRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto two factor code request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute enable two factor
+	result, err := h.Usecase.TwoFactorUsecase.Enable(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling enable two factor: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "enable two factor failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "two factor enabled, store your recovery codes safely", result)
+}
+
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorDisableRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto two factor disable request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute disable two factor
+	err = h.Usecase.TwoFactorUsecase.Disable(user, req)
+	if err != nil {
+		h.Logger.Error("Error handling disable two factor: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "disable two factor failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "two factor disabled", nil)
+}
+
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorCodeRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto two factor code request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute regenerate recovery codes
+	result, err := h.Usecase.TwoFactorUsecase.RegenerateRecoveryCodes(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling regenerate recovery codes: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "regenerate recovery codes failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "regenerate recovery codes success", result)
+}
@@ -0,0 +1,312 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type UserHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewUserHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) UserHandler {
+	return UserHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get profile
+	result, err := h.Usecase.UserUsecase.GetProfile(user.ID)
+	if err != nil {
+		h.Logger.Error("Error handling get profile: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get profile failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get profile success", result)
+}
+
+func (h *UserHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateProfileRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto update profile request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute update profile
+	result, err := h.Usecase.UserUsecase.UpdateProfile(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling update profile: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update profile failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update profile success", result)
+}
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChangePasswordRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto change password request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info and current session
+	user := r.Context().Value("user").(entity.User)
+	auth := r.Header.Get("Authorization")
+	token := strings.TrimSpace(strings.Replace(auth, "Bearer", "", 1))
+
+	// Execute change password
+	err = h.Usecase.UserUsecase.ChangePassword(user.ID, token, req)
+	if err != nil {
+		h.Logger.Error("Error handling change password: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "change password failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "change password success", nil)
+}
+
+func (h *UserHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChangeEmailRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto change email request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute request email change
+	err = h.Usecase.UserUsecase.RequestEmailChange(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling request email change: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "request email change failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "OTP sent to new email", nil)
+}
+
+func (h *UserHandler) VerifyEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req dto.OTPRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto OTP request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute verify email change
+	result, err := h.Usecase.UserUsecase.VerifyEmailChange(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling verify email change: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "verify email change failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "email changed successfully", result)
+}
+
+func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteAccountRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto delete account request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute delete account
+	err = h.Usecase.UserUsecase.DeleteAccount(user.ID, req)
+	if err != nil {
+		h.Logger.Error("Error handling delete account: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete account failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete account success", nil)
+}
+
+func (h *UserHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	// Retrieve query
+	q, err := utils.GetPaginationQuery(r, h.Logger, h.Config)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, "invalid query param", err.Error())
+		return
+	}
+
+	// Execute get users
+	result, pagination, err := h.Usecase.UserUsecase.GetAll(q)
+	if err != nil {
+		h.Logger.Error("Error handling get users: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get users failed", err.Error())
+		return
+	}
+
+	utils.ResponseWithPagination(w, http.StatusOK, "get users success", result, pagination)
+}
+
+func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute get user
+	result, err := h.Usecase.UserUsecase.GetProfile(id)
+	if err != nil && err.Error() == utils.ErrNotFound("user").Error() {
+		h.Logger.Error("Error user not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "user not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling get user by id: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get user failed", err.Error())
+		return
+	}
+	utils.ResponseSuccess(w, http.StatusOK, "get user success", result)
+}
+
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.UserRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto user request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Execute update user
+	err = h.Usecase.UserUsecase.Update(id, req)
+	if err != nil && err.Error() == utils.ErrNotFound("user").Error() {
+		h.Logger.Error("Error user not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "user not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling update user: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "update user failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "update user success", nil)
+}
+
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Execute delete user
+	err = h.Usecase.UserUsecase.Delete(id)
+	if err != nil && err.Error() == utils.ErrNotFound("user").Error() {
+		h.Logger.Error("Error user not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "user not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling delete user: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "delete user failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "delete user success", nil)
+}
@@ -0,0 +1,116 @@
+package adaptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/internal/usecase"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type WaitlistHandler struct {
+	Usecase usecase.Usecase
+	Logger  *zap.Logger
+	Config  utils.Configuration
+}
+
+func NewWaitlistHandler(usecase usecase.Usecase, log *zap.Logger, config utils.Configuration) WaitlistHandler {
+	return WaitlistHandler{
+		Usecase: usecase,
+		Logger:  log,
+		Config:  config,
+	}
+}
+
+func (h *WaitlistHandler) Join(w http.ResponseWriter, r *http.Request) {
+	// Retrieve screening id
+	idStr := r.PathValue("id")
+	screeningID, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	var req dto.WaitlistRequest
+
+	// Decode request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Logger.Error("Error decode request body to dto waitlist request: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Validation
+	messages, err := utils.ValidateErrors(req)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusBadRequest, err.Error(), messages)
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute join waitlist
+	result, err := h.Usecase.WaitlistUsecase.Join(screeningID, user.ID, req, utils.GetLocale(r, h.Config))
+	if err != nil && err.Error() == utils.ErrNotFound("screening").Error() {
+		h.Logger.Error("Error screening not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "screening not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling join waitlist: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "join waitlist failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusCreated, "join waitlist success, you will get an email when seats are free", result)
+}
+
+func (h *WaitlistHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute get user waitlist
+	result, err := h.Usecase.WaitlistUsecase.GetByUser(user.ID, utils.GetLocale(r, h.Config))
+	if err != nil {
+		h.Logger.Error("Error handling get user waitlist: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "get waitlist failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "get waitlist success", result)
+}
+
+func (h *WaitlistHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	// Retrieve id
+	idStr := r.PathValue("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.Logger.Error("Error convert string to int: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "error data", err.Error())
+		return
+	}
+
+	// Retrieve user info
+	user := r.Context().Value("user").(entity.User)
+
+	// Execute leave waitlist
+	err = h.Usecase.WaitlistUsecase.Leave(id, user.ID)
+	if err != nil && err.Error() == utils.ErrNotFound("waitlist entry").Error() {
+		h.Logger.Error("Error waitlist entry not found: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusNotFound, "waitlist entry not found", err.Error())
+		return
+	}
+	if err != nil {
+		h.Logger.Error("Error handling leave waitlist: ", zap.Error(err))
+		utils.ResponseFailed(w, http.StatusBadRequest, "leave waitlist failed", err.Error())
+		return
+	}
+
+	utils.ResponseSuccess(w, http.StatusOK, "leave waitlist success", nil)
+}
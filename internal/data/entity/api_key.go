@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type APIKey struct {
+	Model
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	UserID     int        `json:"user_id"`
+	Scopes     []string   `json:"scopes"`
+	CinemaIDs  []int      `json:"cinema_ids"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy  *int       `json:"created_by,omitempty"`
+}
@@ -4,9 +4,33 @@ import "time"
 
 type Booking struct {
 	Model
-	UserID      int
-	ScreeningID int
-	Seats       []Seat
-	Status      string
-	ExpiredAt   time.Time
-}
\ No newline at end of file
+	UserID          int
+	ScreeningID     int
+	Seats           []Seat
+	Status          string
+	ExpiredAt       time.Time
+	ExchangedFromID *int
+	ExchangedAt     *time.Time
+}
+
+// SeatSwap is the outcome of changing seats on a booking
+type SeatSwap struct {
+	BookingID       int
+	ScreeningID     int
+	Released        []int
+	Held            []int
+	PriceDifference float64
+	Adjustment      *Payment
+}
+
+// BookingExchange is the outcome of moving a booking to another screening
+type BookingExchange struct {
+	BookingID           int
+	ExchangedFromID     int
+	ScreeningID         int
+	PreviousScreeningID int
+	PriceDifference     float64
+	Fee                 float64
+	Completed           bool
+	Adjustment          *Payment
+}
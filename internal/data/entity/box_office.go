@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// CashierShift is a cashier's drawer from opening to closing with the
+// totals of the sales taken during it
+type CashierShift struct {
+	ID           int        `json:"id"`
+	CashierID    int        `json:"cashier_id"`
+	CashierName  string     `json:"cashier_name"`
+	CinemaID     *int       `json:"cinema_id"`
+	CinemaName   *string    `json:"cinema_name"`
+	OpeningFloat float64    `json:"opening_float"`
+	CountedCash  *float64   `json:"counted_cash"`
+	Note         *string    `json:"note"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	Bookings     int        `json:"bookings"`
+	Tickets      int        `json:"tickets"`
+	CashSales    float64    `json:"cash_sales"`
+	EDCSales     float64    `json:"edc_sales"`
+}
+
+// BoxOfficeSale is a walk-in booking with the payment taken for it
+type BoxOfficeSale struct {
+	BookingID     int       `json:"booking_id"`
+	ScreeningID   int       `json:"screening_id"`
+	MovieID       int       `json:"movie_id"`
+	MovieTitle    string    `json:"movie_title"`
+	CinemaName    string    `json:"cinema_name"`
+	StudioName    string    `json:"studio_name"`
+	Format        string    `json:"format"`
+	StartTime     time.Time `json:"start_time"`
+	Status        string    `json:"status"`
+	CustomerPhone *string   `json:"customer_phone"`
+	CashierID     int       `json:"cashier_id"`
+	CashierName   string    `json:"cashier_name"`
+	ShiftID       int       `json:"shift_id"`
+	PaymentID     int       `json:"payment_id"`
+	PaymentMethod string    `json:"payment_method"`
+	PaymentKind   string    `json:"payment_kind"`
+	Price         float64   `json:"price"`
+	Amount        float64   `json:"amount"`
+	Tendered      *float64  `json:"tendered"`
+	ApprovalCode  *string   `json:"approval_code"`
+	Tickets       []Ticket  `json:"tickets"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -1,7 +1,36 @@
 package entity
 
+import "time"
+
 type Cinema struct {
 	Model
-	Name     string `json:"name"`
-	Location string `json:"location"`
-}
\ No newline at end of file
+	Name         string        `json:"name"`
+	Location     string        `json:"location"`
+	Address      *string       `json:"address"`
+	City         *string       `json:"city"`
+	Region       *string       `json:"region"`
+	Latitude     *float64      `json:"latitude"`
+	Longitude    *float64      `json:"longitude"`
+	OpeningHours []OpeningHour `json:"opening_hours,omitempty"`
+	Amenities    []string      `json:"amenities"`
+}
+
+// Times are local "15.04", a close time at or before the open time is after midnight
+type OpeningHour struct {
+	Weekday   int    `json:"weekday"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+}
+
+type CinemaClosure struct {
+	ID        int       `json:"id"`
+	CinemaID  int       `json:"cinema_id"`
+	Date      time.Time `json:"date"`
+	Reason    *string   `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Amenity struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type DataExport struct {
+	Model
+	UserID      int        `json:"user_id"`
+	Status      string     `json:"status"`
+	FilePath    *string    `json:"-"`
+	Error       *string    `json:"error,omitempty"`
+	ExpiredAt   *time.Time `json:"expired_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
@@ -2,5 +2,8 @@ package entity
 
 type Genre struct {
 	Model
-	Name string `json:"name"`
-}
\ No newline at end of file
+	Name     string            `json:"name"`
+	ParentID *int              `json:"parent_id"`
+	Names    map[string]string `json:"names"`
+	Children []Genre           `json:"children,omitempty"`
+}
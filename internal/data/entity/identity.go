@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type UserIdentity struct {
+	Model
+	UserID   int     `json:"user_id"`
+	Provider string  `json:"provider"`
+	Subject  string  `json:"subject"`
+	Email    *string `json:"email,omitempty"`
+}
+
+type OIDCState struct {
+	State        string    `json:"state"`
+	Nonce        string    `json:"-"`
+	CodeVerifier string    `json:"-"`
+	ExpiredAt    time.Time `json:"expired_at"`
+}
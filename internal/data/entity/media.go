@@ -0,0 +1,31 @@
+package entity
+
+type Media struct {
+	Model
+	OriginalName string         `json:"original_name"`
+	MimeType     string         `json:"mime_type"`
+	Size         int64          `json:"size_bytes"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	UploadedBy   *int           `json:"uploaded_by"`
+	Variants     []MediaVariant `json:"variants"`
+}
+
+type MediaVariant struct {
+	Variant    string `json:"variant"`
+	StorageKey string `json:"storage_key"`
+	MimeType   string `json:"mime_type"`
+	Size       int64  `json:"size_bytes"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+}
+
+type MediaLink struct {
+	ID         int    `json:"id"`
+	MediaID    int    `json:"media_id"`
+	EntityType string `json:"entity_type"`
+	EntityID   int    `json:"entity_id"`
+	Kind       string `json:"kind"`
+	Position   int    `json:"position"`
+	Media      *Media `json:"media,omitempty"`
+}
@@ -13,4 +13,6 @@ type Movie struct {
 	ReleaseDate time.Time `json:"release_date"`
 	Language    string    `json:"language"`
 	RatingAge   string    `json:"rating_age"`
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int     `json:"rating_count"`
 }
\ No newline at end of file
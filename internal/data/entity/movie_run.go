@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type MovieRun struct {
+	Model
+	MovieID        int        `json:"movie_id"`
+	CinemaID       int        `json:"cinema_id"`
+	CinemaName     string     `json:"cinema_name,omitempty"`
+	PresaleStartAt *time.Time `json:"presale_start_at,omitempty"`
+	StartDate      time.Time  `json:"start_date"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+	Status         string     `json:"status"`
+	Movie          *Movie     `json:"movie,omitempty"`
+}
@@ -4,6 +4,7 @@ import "time"
 
 type OTP struct {
 	Model
+	UserID    *int `json:"user_id,omitempty"`
 	Email     string `json:"email"`
 	OTPHash   string `json:"otp_hash"`
 	ExpiredAt time.Time `json:"expired_at"`
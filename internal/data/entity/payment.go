@@ -6,6 +6,7 @@ type Payment struct {
 	PaymentMethod string  `json:"payment_method"`
 	Amount        string  `json:"amount"`
 	Status        string  `json:"status"`
+	Type          string  `json:"type"`
 	TransactionID *string `json:"transaction_id"`
 }
 
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type Person struct {
+	Model
+	Name      string     `json:"name"`
+	Biography *string    `json:"biography"`
+	PhotoURL  *string    `json:"photo_url"`
+	BirthDate *time.Time `json:"birth_date"`
+}
+
+type MovieCredit struct {
+	ID            int     `json:"id"`
+	MovieID       int     `json:"movie_id"`
+	PersonID      int     `json:"person_id"`
+	PersonName    string  `json:"person_name"`
+	PhotoURL      *string `json:"photo_url"`
+	Role          string  `json:"role"`
+	CharacterName *string `json:"character_name"`
+	Order         int     `json:"billing_order"`
+	Movie         *Movie  `json:"movie,omitempty"`
+}
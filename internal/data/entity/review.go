@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Review struct {
+	Model
+	MovieID        int        `json:"movie_id"`
+	MovieTitle     string     `json:"movie_title"`
+	UserID         int        `json:"user_id"`
+	UserName       string     `json:"user_name"`
+	Rating         int        `json:"rating"`
+	Content        string     `json:"content"`
+	Status         string     `json:"status"`
+	ModerationNote *string    `json:"moderation_note"`
+	ModeratedBy    *int       `json:"moderated_by"`
+	ModeratedAt    *time.Time `json:"moderated_at"`
+}
@@ -4,8 +4,33 @@ import "time"
 
 type Screening struct {
 	Model
-	StudioID  int       `json:"studio_id"`
-	MovieID   int       `json:"movie_id"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-}
\ No newline at end of file
+	StudioID         int       `json:"studio_id"`
+	MovieID          int       `json:"movie_id"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	Format           string    `json:"format"`
+	AudioLanguage    *string   `json:"audio_language"`
+	SubtitleLanguage *string   `json:"subtitle_language"`
+	AudioDescription bool      `json:"audio_description"`
+	ClosedCaptions   bool      `json:"closed_captions"`
+	Surcharge        float64   `json:"surcharge"`
+	Price            float64   `json:"price"`
+	SeatsTotal       int       `json:"seats_total"`
+	SeatsAvailable   int       `json:"seats_available"`
+}
+
+type ScreeningFormat struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Surcharge float64   `json:"surcharge"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Showtime is a screening with its cinema and seat counts, for searches across cinemas
+type Showtime struct {
+	Screening
+	Cinema     Cinema
+	StudioName string
+	DistanceKm *float64
+}
@@ -4,8 +4,37 @@ import "time"
 
 type Ticket struct {
 	Model
-	BookingID int `json:"booking_id"`
-	SeatID    int `json:"seat_id"`
-	QRToken   int `json:"qr_token"`
-	IssuedAt  *time.Time `json:"issued_at,omitempty"`
-}
\ No newline at end of file
+	BookingID    int        `json:"booking_id"`
+	SeatID       int        `json:"seat_id"`
+	SeatCode     string     `json:"seat_code"`
+	QRToken      string     `json:"qr_token"`
+	IssuedAt     *time.Time `json:"issued_at,omitempty"`
+	OwnerID      int        `json:"owner_id"`
+	HolderUserID *int       `json:"holder_user_id,omitempty"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+	ScreeningID  int        `json:"screening_id"`
+	MovieID      int        `json:"movie_id"`
+	MovieTitle   string     `json:"movie_title"`
+	CinemaName   string     `json:"cinema_name"`
+	StudioName   string     `json:"studio_name"`
+	StartTime    time.Time  `json:"start_time"`
+}
+
+// TicketTransfer is a ticket offered by its holder to another user
+type TicketTransfer struct {
+	Model
+	TicketID    int       `json:"ticket_id"`
+	BookingID   int       `json:"booking_id"`
+	ScreeningID int       `json:"screening_id"`
+	MovieID     int       `json:"movie_id"`
+	MovieTitle  string    `json:"movie_title"`
+	CinemaName  string    `json:"cinema_name"`
+	SeatCode    string    `json:"seat_code"`
+	StartTime   time.Time `json:"start_time"`
+	FromUserID  int       `json:"from_user_id"`
+	FromName    string    `json:"from_name"`
+	ToUserID    int       `json:"to_user_id"`
+	ToEmail     string    `json:"to_email"`
+	Status      string    `json:"status"`
+	NewTicketID *int      `json:"new_ticket_id,omitempty"`
+}
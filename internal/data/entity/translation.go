@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// Translation holds the translated fields of one record in one locale
+type Translation struct {
+	Locale    string            `json:"locale"`
+	Fields    map[string]string `json:"fields"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTOTP struct {
+	UserID       int        `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep *int64     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type RecoveryCode struct {
+	ID       int        `json:"id"`
+	UserID   int        `json:"user_id"`
+	CodeHash string     `json:"-"`
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+type TwoFactorChallenge struct {
+	ID        int       `json:"id"`
+	Token     uuid.UUID `json:"token"`
+	UserID    int       `json:"user_id"`
+	Attempts  int       `json:"attempts"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
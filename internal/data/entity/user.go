@@ -6,4 +6,6 @@ type User struct {
 	Email    string  `json:"email"`
 	Password *string `json:"password,omitempty"`
 	Role     string  `json:"role"`
+	Locale   string  `json:"locale,omitempty"`
+	CinemaID *int    `json:"cinema_id,omitempty"`
 }
\ No newline at end of file
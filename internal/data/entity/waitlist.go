@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type WaitlistEntry struct {
+	Model
+	ScreeningID    int        `json:"screening_id"`
+	MovieID        int        `json:"movie_id"`
+	MovieTitle     string     `json:"movie_title"`
+	CinemaName     string     `json:"cinema_name"`
+	StartTime      time.Time  `json:"start_time"`
+	UserID         int        `json:"user_id"`
+	SeatCount      int        `json:"seat_count"`
+	Status         string     `json:"status"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at"`
+	Position       *int       `json:"position"`
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type APIKeyRepository interface {
+	Create(k entity.APIKey) (*entity.APIKey, error)
+	GetAll() ([]entity.APIKey, error)
+	GetByID(id int) (*entity.APIKey, error)
+	GetByPrefix(prefix string) (*entity.APIKey, error)
+	Rotate(id int, prefix string, keyHash string) error
+	Revoke(id int) error
+	UpdateLastUsed(id int) error
+	GetCinemaByScreening(screeningID int) (int, error)
+	GetCinemaByBooking(bookingID int) (int, error)
+}
+
+type apiKeyRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewAPIKeyRepository(db database.PgxIface, log *zap.Logger) APIKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+const apiKeyColumns = `id, name, prefix, key_hash, user_id, scopes, cinema_ids, allowed_ips,
+	expired_at, last_used_at, revoked_at, created_by, created_at, updated_at`
+
+func scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
+	var k entity.APIKey
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, &k.UserID, &k.Scopes, &k.CinemaIDs, &k.AllowedIPs,
+		&k.ExpiredAt, &k.LastUsedAt, &k.RevokedAt, &k.CreatedBy, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepository) Create(k entity.APIKey) (*entity.APIKey, error) {
+	query := `INSERT INTO api_keys (name, prefix, key_hash, user_id, scopes, cinema_ids, allowed_ips,
+	expired_at, created_by, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+	RETURNING ` + apiKeyColumns
+	key, err := scanAPIKey(r.db.QueryRow(context.Background(), query, k.Name, k.Prefix, k.KeyHash, k.UserID,
+		k.Scopes, k.CinemaIDs, k.AllowedIPs, k.ExpiredAt, k.CreatedBy))
+	if err != nil {
+		r.Logger.Error("Error query create api key: ", zap.Error(err))
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetAll() ([]entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC`
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		r.Logger.Error("Error query get api keys: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			r.Logger.Error("Error scan api key: ", zap.Error(err))
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) GetByID(id int) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+	k, err := scanAPIKey(r.db.QueryRow(context.Background(), query, id))
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found api key: ", zap.Error(err))
+		return nil, utils.ErrNotFound("api key")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get api key by id: ", zap.Error(err))
+		return nil, err
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(prefix string) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1 AND revoked_at IS NULL`
+	k, err := scanAPIKey(r.db.QueryRow(context.Background(), query, prefix))
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("api key")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get api key by prefix: ", zap.Error(err))
+		return nil, err
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepository) Rotate(id int, prefix string, keyHash string) error {
+	// Old secret stops working immediately
+	query := `UPDATE api_keys SET prefix = $1, key_hash = $2, updated_at = NOW()
+	WHERE id = $3 AND revoked_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, prefix, keyHash, id)
+	if err != nil {
+		r.Logger.Error("Error query rotate api key: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("api key")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) Revoke(id int) error {
+	query := `UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query revoke api key: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("api key")
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(id int) error {
+	// Coarse tracking, avoid a write on every request
+	query := `UPDATE api_keys SET last_used_at = NOW()
+	WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - interval '1 minute')`
+	_, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query update api key last used: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) GetCinemaByScreening(screeningID int) (int, error) {
+	var cinemaID int
+	query := `SELECT st.cinema_id FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	WHERE s.id = $1`
+	err := r.db.QueryRow(context.Background(), query, screeningID).Scan(&cinemaID)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("screening")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get cinema by screening: ", zap.Error(err))
+		return 0, err
+	}
+	return cinemaID, nil
+}
+
+func (r *apiKeyRepository) GetCinemaByBooking(bookingID int) (int, error) {
+	var cinemaID int
+	query := `SELECT st.cinema_id FROM bookings b
+	JOIN screenings s ON s.id = b.screening_id
+	JOIN studios st ON st.id = s.studio_id
+	WHERE b.id = $1`
+	err := r.db.QueryRow(context.Background(), query, bookingID).Scan(&cinemaID)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("booking")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get cinema by booking: ", zap.Error(err))
+		return 0, err
+	}
+	return cinemaID, nil
+}
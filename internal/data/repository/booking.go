@@ -3,18 +3,31 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
 	"go.uber.org/zap"
 )
 
+// Bookings a user made or holds transferred tickets of, needs bookings b
+const historyFilter = `(b.user_id = $1 OR EXISTS (
+		SELECT 1 FROM tickets t WHERE t.booking_id = b.id AND t.holder_user_id = $1 AND t.revoked_at IS NULL
+	))`
+
 type BookingRepository interface{
 	Create(b dto.BookingRequest) (*entity.Booking, error)
 	GetByID(id int) (*entity.Booking, error)
+	GetSeats(id int) ([]entity.Seat, error)
+	Cancel(id int, userID int) (int, error)
+	SwapSeats(id int, userID int, release []int, hold []int) (*entity.SeatSwap, error)
+	Exchange(id int, userID int, screeningID int, seats []int, fee float64) (*entity.BookingExchange, error)
 	GetBookingHistory(ctx context.Context, q dto.PaginationQuery) ([]dto.BookingHistory, int, error)
 }
 
@@ -50,6 +63,7 @@ JOIN movies m ON m.id = s.movie_id
 WHERE s.id = $1
   AND s.deleted_at IS NULL
   AND NOW() < s.start_time + (m.duration_minute * INTERVAL '1 minute')
+FOR UPDATE OF s
 `
 	err = tx.QueryRow(context.Background(), query, b.ScreeningID).Scan(&screeningID)
 	if err != nil {
@@ -57,6 +71,31 @@ WHERE s.id = $1
 		return nil, errors.New("booking is closed for this screening")
 	}
 
+	// Validate pre-sale window
+	err = checkPresale(tx, b.ScreeningID)
+	if err != nil {
+		r.Logger.Error("Booking is not open for this screening: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Release expired holds so their seats can be booked again
+	err = releaseExpiredHolds(tx, b.ScreeningID)
+	if err != nil {
+		r.Logger.Error("Error release expired holds: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Seats offered to waitlisted users are kept for them
+	_, free, offered, err := seatCounts(tx, b.ScreeningID, b.UserID)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return nil, err
+	}
+	if len(b.Seats) > free-offered && len(b.Seats) <= free {
+		err = errors.New("seats are held for waitlisted customers")
+		return nil, err
+	}
+
 	// Create booking
 	var booking entity.Booking
 	query = `INSERT INTO bookings (user_id, screening_id, status, expired_at, created_at, updated_at)
@@ -72,7 +111,7 @@ WHERE s.id = $1
 		query := `INSERT INTO booking_seats (booking_id, screening_id, seat_id, booking_status, created_at)
 		VALUES ($1, $2, $3, 'pending', NOW())`
 
-		_, err := tx.Exec(context.Background(), query, booking.ID, b.ScreeningID, seatID)
+		_, err = tx.Exec(context.Background(), query, booking.ID, b.ScreeningID, seatID)
 		if err != nil {
 			r.Logger.Error("Error query create booking_seats: ", zap.Error(err))
 			return nil, errors.New("one or more seats already booked")
@@ -90,6 +129,15 @@ WHERE s.id = $1
 		booking.Seats = append(booking.Seats, seat)
 	}
 
+	// A waitlist offer is used up by booking
+	query = `UPDATE waitlist_entries SET status = 'booked', updated_at = NOW()
+	WHERE screening_id = $1 AND user_id = $2 AND status = 'offered'`
+	_, err = tx.Exec(context.Background(), query, b.ScreeningID, b.UserID)
+	if err != nil {
+		r.Logger.Error("Error query use waitlist offer: ", zap.Error(err))
+		return nil, err
+	}
+
 	err = tx.Commit(context.Background())
 	if err != nil {
 		return nil, err
@@ -100,10 +148,13 @@ WHERE s.id = $1
 
 func (r *bookingRepository) GetByID(id int) (*entity.Booking, error) {
 	var b entity.Booking
-	query := `SELECT id, user_id, screening_id, status, expired_at, created_at, updated_at
+	query := `SELECT id, COALESCE(user_id, 0), screening_id, status, expired_at, exchanged_from_id, exchanged_at, created_at, updated_at
 	FROM bookings WHERE id = $1
 	`
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&b.ID, &b.UserID, &b.ScreeningID, &b.Status, &b.ExpiredAt, &b.CreatedAt, &b.UpdatedAt)
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&b.ID, &b.UserID, &b.ScreeningID, &b.Status, &b.ExpiredAt, &b.ExchangedFromID, &b.ExchangedAt, &b.CreatedAt, &b.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("booking")
+	}
 	if err != nil {
 		r.Logger.Error("Error query get booking by id: ", zap.Error(err))
 		return nil, err
@@ -112,6 +163,32 @@ func (r *bookingRepository) GetByID(id int) (*entity.Booking, error) {
 	return &b, nil
 }
 
+// GetSeats returns the seats a booking paid for
+func (r *bookingRepository) GetSeats(id int) ([]entity.Seat, error) {
+	query := `SELECT se.id, se.seat_code
+	FROM booking_seats bs
+	JOIN seats se ON se.id = bs.seat_id
+	WHERE bs.booking_id = $1 AND bs.booking_status = 'paid'
+	ORDER BY se.seat_code`
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get booking seats: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []entity.Seat
+	for rows.Next() {
+		var s entity.Seat
+		if err := rows.Scan(&s.ID, &s.SeatCode); err != nil {
+			r.Logger.Error("Error scan booking seat: ", zap.Error(err))
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, rows.Err()
+}
+
 func (r *bookingRepository) GetBookingHistory(ctx context.Context, q dto.PaginationQuery) ([]dto.BookingHistory, int, error) {
 	var offset int
 	offset = (q.Page - 1) * q.Limit
@@ -120,7 +197,7 @@ func (r *bookingRepository) GetBookingHistory(ctx context.Context, q dto.Paginat
 	user := ctx.Value("user").(entity.User)
 	var total int
 	countQuery := `
-	SELECT id FROM bookings WHERE user_id = $1`
+	SELECT COUNT(*) FROM bookings b WHERE ` + historyFilter
 	err := r.db.QueryRow(context.Background(), countQuery, user.ID).Scan(&total)
 	if err != nil {
 		r.Logger.Error("Error query count booking history: ", zap.Error(err))
@@ -138,9 +215,13 @@ func (r *bookingRepository) GetBookingHistory(ctx context.Context, q dto.Paginat
 	LEFT JOIN studios st ON st.id = sc.studio_id
 	LEFT JOIN cinemas c ON c.id = st.cinema_id
 	LEFT JOIN movies m ON m.id = sc.movie_id
-	LEFT JOIN booking_seats bs ON bs.booking_id = b.id
+	LEFT JOIN booking_seats bs ON bs.booking_id = b.id AND (bs.booking_status <> 'cancelled' OR b.status = 'cancelled')
+		AND (b.user_id = $1 OR EXISTS (
+			SELECT 1 FROM tickets t
+			WHERE t.booking_id = b.id AND t.seat_id = bs.seat_id AND t.holder_user_id = $1 AND t.revoked_at IS NULL
+		))
 	LEFT JOIN seats s ON s.id = bs.seat_id
-	WHERE user_id = $1
+	WHERE ` + historyFilter + `
 	GROUP BY b.id, m.title, c.name, m.poster_url, b.status, sc.start_time
 	ORDER BY sc.start_time DESC
 	`
@@ -175,4 +256,647 @@ func (r *bookingRepository) GetBookingHistory(ctx context.Context, q dto.Paginat
 	}
 
 	return bookings, total, nil
-}
\ No newline at end of file
+}
+
+// Cancel releases the seats of an unpaid booking and returns its screening
+func (r *bookingRepository) Cancel(id int, userID int) (int, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var screeningID int
+	var status string
+	query := `SELECT screening_id, status FROM bookings WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, id, userID).Scan(&screeningID, &status)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("booking")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get booking: ", zap.Error(err))
+		return 0, err
+	}
+	if status != "pending" {
+		err = errors.New("only unpaid bookings can be cancelled")
+		return 0, err
+	}
+
+	query = `UPDATE payments SET status = 'expired', updated_at = NOW() WHERE booking_id = $1 AND status = 'pending'`
+	_, err = tx.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query cancel payments: ", zap.Error(err))
+		return 0, err
+	}
+
+	query = `UPDATE booking_seats SET booking_status = 'cancelled' WHERE booking_id = $1`
+	_, err = tx.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query cancel booking_seats: ", zap.Error(err))
+		return 0, err
+	}
+
+	query = `UPDATE bookings SET status = 'cancelled', updated_at = NOW() WHERE id = $1`
+	_, err = tx.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query cancel booking: ", zap.Error(err))
+		return 0, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return screeningID, nil
+}
+
+// SwapSeats gives up some seats of a booking and holds others in the same
+// screening in one transaction. On a paid booking each released seat is
+// exchanged for a new one with a fresh ticket, extra seats are held until
+// their charge is paid and fewer seats are refunded.
+func (r *bookingRepository) SwapSeats(id int, userID int, release []int, hold []int) (*entity.SeatSwap, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	swap := entity.SeatSwap{BookingID: id, Released: release, Held: hold}
+
+	// Validate booking
+	var status string
+	var active bool
+	query := `SELECT screening_id, status, expired_at > NOW() FROM bookings WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, id, userID).Scan(&swap.ScreeningID, &status, &active)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("booking")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get booking: ", zap.Error(err))
+		return nil, err
+	}
+	if status != "paid" && !(status == "pending" && active) {
+		err = errors.New("only pending or paid bookings can change seats")
+		return nil, err
+	}
+
+	// Validate screening, locked like new bookings for it
+	var open bool
+	var studioID int
+	var price float64
+	query = `SELECT s.start_time > NOW(), s.studio_id, st.price + f.surcharge
+	FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	JOIN screening_formats f ON f.code = s.format
+	WHERE s.id = $1 AND s.deleted_at IS NULL
+	FOR UPDATE OF s`
+	err = tx.QueryRow(context.Background(), query, swap.ScreeningID).Scan(&open, &studioID, &price)
+	if err != nil {
+		r.Logger.Error("Error query get screening: ", zap.Error(err))
+		return nil, err
+	}
+	if !open {
+		err = errors.New("seats cannot be changed after the screening has started")
+		return nil, err
+	}
+
+	err = releaseExpiredHolds(tx, swap.ScreeningID)
+	if err != nil {
+		r.Logger.Error("Error release expired holds: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Released seats must be in the booking, held seats must not
+	current := map[int]bool{}
+	query = `SELECT seat_id FROM booking_seats WHERE booking_id = $1 AND booking_status <> 'cancelled'`
+	rows, err := tx.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get booking seats: ", zap.Error(err))
+		return nil, err
+	}
+	for rows.Next() {
+		var seatID int
+		if err = rows.Scan(&seatID); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan booking seat: ", zap.Error(err))
+			return nil, err
+		}
+		current[seatID] = true
+	}
+	rows.Close()
+
+	for _, seatID := range release {
+		if !current[seatID] {
+			err = fmt.Errorf("seat %d is not part of this booking", seatID)
+			return nil, err
+		}
+	}
+	for _, seatID := range hold {
+		if current[seatID] {
+			err = fmt.Errorf("seat %d is already part of this booking", seatID)
+			return nil, err
+		}
+	}
+	if len(current)-len(release)+len(hold) == 0 {
+		err = errors.New("a booking needs at least one seat, cancel it instead")
+		return nil, err
+	}
+
+	// Tickets given to other users are theirs to keep
+	var transferred bool
+	query = `SELECT EXISTS (
+		SELECT 1 FROM tickets
+		WHERE booking_id = $1 AND seat_id = ANY($2::int[]) AND revoked_at IS NULL
+			AND holder_user_id IS NOT NULL AND holder_user_id <> $3
+	)`
+	err = tx.QueryRow(context.Background(), query, id, release, userID).Scan(&transferred)
+	if err != nil {
+		r.Logger.Error("Error query check transferred tickets: ", zap.Error(err))
+		return nil, err
+	}
+	if transferred {
+		err = errors.New("one or more seats have been transferred to another user")
+		return nil, err
+	}
+
+	var inStudio int
+	query = `SELECT COUNT(*) FROM seats WHERE id = ANY($1::int[]) AND studio_id = $2 AND deleted_at IS NULL`
+	err = tx.QueryRow(context.Background(), query, hold, studioID).Scan(&inStudio)
+	if err != nil {
+		r.Logger.Error("Error query check seats: ", zap.Error(err))
+		return nil, err
+	}
+	if inStudio != len(hold) {
+		err = errors.New("one or more seats are not in this studio")
+		return nil, err
+	}
+
+	// Release seats and revoke their tickets
+	query = `UPDATE booking_seats SET booking_status = 'cancelled'
+	WHERE booking_id = $1 AND seat_id = ANY($2::int[]) AND booking_status <> 'cancelled'`
+	_, err = tx.Exec(context.Background(), query, id, release)
+	if err != nil {
+		r.Logger.Error("Error query release booking_seats: ", zap.Error(err))
+		return nil, err
+	}
+
+	query = `UPDATE tickets SET revoked_at = NOW() WHERE booking_id = $1 AND seat_id = ANY($2::int[]) AND revoked_at IS NULL`
+	_, err = tx.Exec(context.Background(), query, id, release)
+	if err != nil {
+		r.Logger.Error("Error query revoke tickets: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = cancelStaleTransfers(tx, id)
+	if err != nil {
+		r.Logger.Error("Error query cancel ticket transfers: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Seats offered to waitlisted users are kept for them
+	_, free, offered, err := seatCounts(tx, swap.ScreeningID, userID)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return nil, err
+	}
+	if len(hold) > free-offered && len(hold) <= free {
+		err = errors.New("seats are held for waitlisted customers")
+		return nil, err
+	}
+
+	// A paid booking exchanges seats one for one, extra seats wait for their charge
+	exchanged := hold
+	var extra []int
+	if status == "paid" && len(hold) > len(release) {
+		exchanged = hold[:len(release)]
+		extra = hold[len(release):]
+	}
+
+	seatStatus := status
+	for _, seatID := range exchanged {
+		query = `INSERT INTO booking_seats (booking_id, screening_id, seat_id, booking_status, created_at)
+		VALUES ($1, $2, $3, $4, NOW())`
+		_, err = tx.Exec(context.Background(), query, id, swap.ScreeningID, seatID, seatStatus)
+		if err != nil {
+			r.Logger.Error("Error query create booking_seats: ", zap.Error(err))
+			err = errors.New("one or more seats already booked")
+			return nil, err
+		}
+	}
+	for _, seatID := range extra {
+		query = `INSERT INTO booking_seats (booking_id, screening_id, seat_id, booking_status, created_at)
+		VALUES ($1, $2, $3, 'pending', NOW())`
+		_, err = tx.Exec(context.Background(), query, id, swap.ScreeningID, seatID)
+		if err != nil {
+			r.Logger.Error("Error query create booking_seats: ", zap.Error(err))
+			err = errors.New("one or more seats already booked")
+			return nil, err
+		}
+	}
+
+	if status == "paid" {
+		err = issueTickets(tx, id, exchanged)
+		if err != nil {
+			r.Logger.Error("Error query create ticket: ", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	swap.PriceDifference = float64(len(hold)-len(release)) * price
+
+	// An unpaid booking is paid in full later, a stale payment would charge the old amount
+	if status == "pending" {
+		query = `UPDATE payments SET status = 'expired', updated_at = NOW()
+		WHERE booking_id = $1 AND status = 'pending' AND type = 'payment'`
+		_, err = tx.Exec(context.Background(), query, id)
+		if err != nil {
+			r.Logger.Error("Error query expire payments: ", zap.Error(err))
+			return nil, err
+		}
+
+		err = tx.Commit(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return &swap, nil
+	}
+
+	if swap.PriceDifference != 0 {
+		amount := math.Abs(swap.PriceDifference)
+		adjustment := entity.Payment{BookingID: id, Amount: strconv.FormatFloat(amount, 'f', 2, 64), Status: "pending", Type: "refund"}
+		if swap.PriceDifference > 0 {
+			adjustment.Type = "charge"
+
+			// The extra seats are held as long as a new booking
+			query = `UPDATE bookings SET expired_at = NOW() + interval '10 minute', updated_at = NOW() WHERE id = $1`
+			_, err = tx.Exec(context.Background(), query, id)
+			if err != nil {
+				r.Logger.Error("Error query extend booking hold: ", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		adjustment.ID, err = createAdjustment(tx, id, adjustment.Type, amount)
+		if err != nil {
+			r.Logger.Error("Error query create payment adjustment: ", zap.Error(err))
+			return nil, err
+		}
+		swap.Adjustment = &adjustment
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &swap, nil
+}
+
+// Exchange moves a paid booking to another screening as a new booking linked
+// to it. When nothing is left to pay the old booking is retired at once,
+// otherwise it stays valid until the charge for the new one is paid.
+func (r *bookingRepository) Exchange(id int, userID int, screeningID int, seats []int, fee float64) (*entity.BookingExchange, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	exchange := entity.BookingExchange{ExchangedFromID: id, ScreeningID: screeningID, Fee: fee}
+
+	// Validate booking
+	var status string
+	query := `SELECT screening_id, status FROM bookings WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, id, userID).Scan(&exchange.PreviousScreeningID, &status)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("booking")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get booking: ", zap.Error(err))
+		return nil, err
+	}
+	if status != "paid" {
+		err = errors.New("only paid bookings can be exchanged")
+		return nil, err
+	}
+	if exchange.PreviousScreeningID == screeningID {
+		err = errors.New("booking is already for this screening, change seats instead")
+		return nil, err
+	}
+
+	var waiting bool
+	query = `SELECT EXISTS (SELECT 1 FROM bookings WHERE exchanged_from_id = $1 AND status = 'pending' AND expired_at > NOW())`
+	err = tx.QueryRow(context.Background(), query, id).Scan(&waiting)
+	if err != nil {
+		r.Logger.Error("Error query check pending exchange: ", zap.Error(err))
+		return nil, err
+	}
+	if waiting {
+		err = errors.New("booking already has an exchange waiting for payment")
+		return nil, err
+	}
+
+	// Validate screenings, locked in id order like new bookings for them
+	type screeningInfo struct {
+		movieID  int
+		studioID int
+		open     bool
+		price    float64
+	}
+	screenings := map[int]screeningInfo{}
+	query = `SELECT s.id, s.movie_id, s.studio_id, s.start_time > NOW(), st.price + f.surcharge
+	FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	JOIN screening_formats f ON f.code = s.format
+	WHERE s.id = ANY($1::int[]) AND s.deleted_at IS NULL
+	ORDER BY s.id
+	FOR UPDATE OF s`
+	rows, err := tx.Query(context.Background(), query, []int{exchange.PreviousScreeningID, screeningID})
+	if err != nil {
+		r.Logger.Error("Error query get screenings: ", zap.Error(err))
+		return nil, err
+	}
+	for rows.Next() {
+		var sid int
+		var info screeningInfo
+		if err = rows.Scan(&sid, &info.movieID, &info.studioID, &info.open, &info.price); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan screening: ", zap.Error(err))
+			return nil, err
+		}
+		screenings[sid] = info
+	}
+	rows.Close()
+
+	previous, ok := screenings[exchange.PreviousScreeningID]
+	if !ok || !previous.open {
+		err = errors.New("bookings cannot be exchanged after the screening has started")
+		return nil, err
+	}
+	target, ok := screenings[screeningID]
+	if !ok {
+		err = utils.ErrNotFound("screening")
+		return nil, err
+	}
+	if !target.open {
+		err = errors.New("booking is closed for this screening")
+		return nil, err
+	}
+	if target.movieID != previous.movieID && target.price != previous.price {
+		err = errors.New("bookings can only be exchanged to the same movie or an equally priced screening")
+		return nil, err
+	}
+
+	// Validate pre-sale window
+	err = checkPresale(tx, screeningID)
+	if err != nil {
+		r.Logger.Error("Booking is not open for this screening: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = releaseExpiredHolds(tx, screeningID)
+	if err != nil {
+		r.Logger.Error("Error release expired holds: ", zap.Error(err))
+		return nil, err
+	}
+
+	// The new booking keeps the number of seats paid for
+	var paidSeats, heldSeats int
+	query = `SELECT
+		COUNT(*) FILTER (WHERE bs.booking_status = 'paid'),
+		COUNT(*) FILTER (WHERE bs.booking_status = 'pending' AND b.expired_at > NOW())
+	FROM booking_seats bs
+	JOIN bookings b ON b.id = bs.booking_id
+	WHERE bs.booking_id = $1`
+	err = tx.QueryRow(context.Background(), query, id).Scan(&paidSeats, &heldSeats)
+	if err != nil {
+		r.Logger.Error("Error query count booking seats: ", zap.Error(err))
+		return nil, err
+	}
+	if heldSeats > 0 {
+		err = errors.New("booking has extra seats waiting for payment")
+		return nil, err
+	}
+
+	var transferred bool
+	query = `SELECT EXISTS (
+		SELECT 1 FROM tickets
+		WHERE booking_id = $1 AND revoked_at IS NULL AND holder_user_id IS NOT NULL AND holder_user_id <> $2
+	)`
+	err = tx.QueryRow(context.Background(), query, id, userID).Scan(&transferred)
+	if err != nil {
+		r.Logger.Error("Error query check transferred tickets: ", zap.Error(err))
+		return nil, err
+	}
+	if transferred {
+		err = errors.New("bookings with transferred tickets cannot be exchanged")
+		return nil, err
+	}
+	if len(seats) != paidSeats {
+		err = fmt.Errorf("exchange needs exactly %d seats", paidSeats)
+		return nil, err
+	}
+
+	var inStudio int
+	query = `SELECT COUNT(*) FROM seats WHERE id = ANY($1::int[]) AND studio_id = $2 AND deleted_at IS NULL`
+	err = tx.QueryRow(context.Background(), query, seats, target.studioID).Scan(&inStudio)
+	if err != nil {
+		r.Logger.Error("Error query check seats: ", zap.Error(err))
+		return nil, err
+	}
+	if inStudio != len(seats) {
+		err = errors.New("one or more seats are not in this studio")
+		return nil, err
+	}
+
+	// Seats offered to waitlisted users are kept for them
+	_, free, offered, err := seatCounts(tx, screeningID, userID)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return nil, err
+	}
+	if len(seats) > free-offered && len(seats) <= free {
+		err = errors.New("seats are held for waitlisted customers")
+		return nil, err
+	}
+
+	exchange.PriceDifference = (target.price - previous.price) * float64(len(seats))
+	due := exchange.PriceDifference + fee
+	exchange.Completed = due <= 0
+
+	// Anything left to pay keeps the new booking pending, the old one stays valid until then
+	status = "pending"
+	if exchange.Completed {
+		status = "paid"
+	}
+	query = `INSERT INTO bookings (user_id, screening_id, status, expired_at, exchanged_from_id, created_at, updated_at)
+	VALUES ($1, $2, $3, NOW() + interval '10 minute', $4, NOW(), NOW()) RETURNING id`
+	err = tx.QueryRow(context.Background(), query, userID, screeningID, status, id).Scan(&exchange.BookingID)
+	if err != nil {
+		r.Logger.Error("Error query create bookings: ", zap.Error(err))
+		return nil, err
+	}
+
+	for _, seatID := range seats {
+		query = `INSERT INTO booking_seats (booking_id, screening_id, seat_id, booking_status, created_at)
+		VALUES ($1, $2, $3, $4, NOW())`
+		_, err = tx.Exec(context.Background(), query, exchange.BookingID, screeningID, seatID, status)
+		if err != nil {
+			r.Logger.Error("Error query create booking_seats: ", zap.Error(err))
+			err = errors.New("one or more seats already booked")
+			return nil, err
+		}
+	}
+
+	if exchange.Completed {
+		err = issueTickets(tx, exchange.BookingID, seats)
+		if err != nil {
+			r.Logger.Error("Error query create ticket: ", zap.Error(err))
+			return nil, err
+		}
+
+		err = retireExchangedBooking(tx, exchange.BookingID)
+		if err != nil {
+			r.Logger.Error("Error query retire exchanged booking: ", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if due != 0 {
+		amount := math.Abs(due)
+		adjustment := entity.Payment{BookingID: exchange.BookingID, Amount: strconv.FormatFloat(amount, 'f', 2, 64), Status: "pending", Type: "refund"}
+		if due > 0 {
+			adjustment.Type = "charge"
+		}
+
+		adjustment.ID, err = createAdjustment(tx, exchange.BookingID, adjustment.Type, amount)
+		if err != nil {
+			r.Logger.Error("Error query create payment adjustment: ", zap.Error(err))
+			return nil, err
+		}
+		exchange.Adjustment = &adjustment
+	}
+
+	// A waitlist offer is used up by booking
+	query = `UPDATE waitlist_entries SET status = 'booked', updated_at = NOW()
+	WHERE screening_id = $1 AND user_id = $2 AND status = 'offered'`
+	_, err = tx.Exec(context.Background(), query, screeningID, userID)
+	if err != nil {
+		r.Logger.Error("Error query use waitlist offer: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &exchange, nil
+}
+
+// releaseExpiredHolds cancels pending bookings of a screening whose hold has
+// expired, freeing their seats from the unique booked seat index. Extra seats
+// added to a paid booking are released the same way when their charge is not paid.
+func releaseExpiredHolds(tx pgx.Tx, screeningID int) error {
+	query := `UPDATE booking_seats bs
+	SET booking_status = 'cancelled'
+	FROM bookings b
+	WHERE b.id = bs.booking_id
+		AND bs.screening_id = $1
+		AND bs.booking_status = 'pending'
+		AND b.expired_at <= NOW()`
+	_, err := tx.Exec(context.Background(), query, screeningID)
+	if err != nil {
+		return err
+	}
+
+	// A late gateway callback must not revive the booking
+	query = `UPDATE payments p
+	SET status = 'expired', updated_at = NOW()
+	FROM bookings b
+	WHERE b.id = p.booking_id
+		AND b.screening_id = $1
+		AND b.expired_at <= NOW()
+		AND p.status = 'pending'
+		AND p.type <> 'refund'`
+	_, err = tx.Exec(context.Background(), query, screeningID)
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE bookings SET status = 'cancelled', updated_at = NOW()
+	WHERE screening_id = $1 AND status = 'pending' AND expired_at <= NOW()`
+	_, err = tx.Exec(context.Background(), query, screeningID)
+	return err
+}
+
+// createAdjustment records a pending charge or refund on a booking, settled
+// through the method the booking, or the one it was exchanged from, was paid with
+func createAdjustment(tx pgx.Tx, bookingID int, paymentType string, amount float64) (int, error) {
+	var id int
+	query := `WITH RECURSIVE chain AS (
+		SELECT id, exchanged_from_id FROM bookings WHERE id = $1
+		UNION ALL
+		SELECT b.id, b.exchanged_from_id FROM bookings b JOIN chain c ON b.id = c.exchanged_from_id
+	)
+	INSERT INTO payments (booking_id, payment_method_id, amount, status, type, created_at, updated_at)
+	SELECT $1, p.payment_method_id, $2, 'pending', $3, NOW(), NOW()
+	FROM payments p
+	JOIN chain c ON c.id = p.booking_id
+	WHERE p.status IN ('success', 'settlement')
+	ORDER BY p.id DESC
+	LIMIT 1
+	RETURNING id`
+	err := tx.QueryRow(context.Background(), query, bookingID, amount, paymentType).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, errors.New("booking has no settled payment to adjust")
+	}
+	return id, err
+}
+
+// retireExchangedBooking cancels the booking a newly paid booking was
+// exchanged from and revokes its tickets. Other bookings are left alone.
+func retireExchangedBooking(tx pgx.Tx, bookingID int) error {
+	var oldID *int
+	query := `SELECT exchanged_from_id FROM bookings WHERE id = $1`
+	err := tx.QueryRow(context.Background(), query, bookingID).Scan(&oldID)
+	if err != nil || oldID == nil {
+		return err
+	}
+
+	query = `UPDATE bookings SET status = 'cancelled', exchanged_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND status = 'paid'`
+	result, err := tx.Exec(context.Background(), query, *oldID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return errors.New("the exchanged booking is no longer valid")
+	}
+
+	query = `UPDATE booking_seats SET booking_status = 'cancelled' WHERE booking_id = $1 AND booking_status <> 'cancelled'`
+	_, err = tx.Exec(context.Background(), query, *oldID)
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE tickets SET revoked_at = NOW() WHERE booking_id = $1 AND revoked_at IS NULL`
+	_, err = tx.Exec(context.Background(), query, *oldID)
+	if err != nil {
+		return err
+	}
+	return cancelStaleTransfers(tx, *oldID)
+}
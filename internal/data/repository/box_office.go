@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Columns of a shift with the totals of its settled sales, needs
+// cashier_shifts cs, users u and cinemas c
+const shiftColumns = `cs.id, cs.cashier_id, u.name, cs.cinema_id, c.name, cs.opening_float, cs.counted_cash, cs.note,
+	cs.opened_at, cs.closed_at,
+	(SELECT COUNT(*) FROM payments p WHERE p.shift_id = cs.id AND p.status = 'success'),
+	(SELECT COUNT(*) FROM payments p
+		JOIN booking_seats bs ON bs.booking_id = p.booking_id AND bs.booking_status = 'paid'
+		WHERE p.shift_id = cs.id AND p.status = 'success'),
+	(SELECT COALESCE(SUM(p.amount), 0) FROM payments p
+		JOIN payment_methods pm ON pm.id = p.payment_method_id
+		WHERE p.shift_id = cs.id AND p.status = 'success' AND pm.kind = 'cash'),
+	(SELECT COALESCE(SUM(p.amount), 0) FROM payments p
+		JOIN payment_methods pm ON pm.id = p.payment_method_id
+		WHERE p.shift_id = cs.id AND p.status = 'success' AND pm.kind = 'edc')`
+
+const shiftJoins = `FROM cashier_shifts cs
+	JOIN users u ON u.id = cs.cashier_id
+	LEFT JOIN cinemas c ON c.id = cs.cinema_id`
+
+type BoxOfficeRepository interface {
+	OpenShift(cashierID int, cinemaID *int, openingFloat float64) (int, error)
+	GetOpenShift(cashierID int) (*entity.CashierShift, error)
+	CloseShift(cashierID int, countedCash float64, note string) (int, error)
+	GetShift(id int) (*entity.CashierShift, error)
+	GetShifts(from time.Time, to time.Time, cashierID int) ([]entity.CashierShift, error)
+	Sell(cashier entity.User, data dto.BoxOfficeRequest) (int, error)
+	GetSale(bookingID int) (*entity.BoxOfficeSale, error)
+}
+
+type boxOfficeRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewBoxOfficeRepository(db database.PgxIface, log *zap.Logger) BoxOfficeRepository {
+	return &boxOfficeRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *boxOfficeRepository) OpenShift(cashierID int, cinemaID *int, openingFloat float64) (int, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM cashier_shifts WHERE cashier_id = $1 AND closed_at IS NULL)`
+	err := r.db.QueryRow(context.Background(), query, cashierID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check open shift: ", zap.Error(err))
+		return 0, err
+	}
+	if exists {
+		return 0, errors.New("a shift is already open, close it first")
+	}
+
+	var id int
+	query = `INSERT INTO cashier_shifts (cashier_id, cinema_id, opening_float, opened_at)
+	VALUES ($1, $2, $3, NOW()) RETURNING id`
+	err = r.db.QueryRow(context.Background(), query, cashierID, cinemaID, openingFloat).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query open shift: ", zap.Error(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *boxOfficeRepository) GetOpenShift(cashierID int) (*entity.CashierShift, error) {
+	query := `SELECT ` + shiftColumns + `
+	` + shiftJoins + `
+	WHERE cs.cashier_id = $1 AND cs.closed_at IS NULL`
+	rows, err := r.db.Query(context.Background(), query, cashierID)
+	if err != nil {
+		r.Logger.Error("Error query get open shift: ", zap.Error(err))
+		return nil, err
+	}
+	shifts, err := r.scanShifts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(shifts) == 0 {
+		return nil, utils.ErrNotFound("shift")
+	}
+	return &shifts[0], nil
+}
+
+// CloseShift records the cash counted in the drawer and closes the open shift
+func (r *boxOfficeRepository) CloseShift(cashierID int, countedCash float64, note string) (int, error) {
+	var id int
+	query := `UPDATE cashier_shifts SET counted_cash = $2, note = NULLIF($3, ''), closed_at = NOW()
+	WHERE cashier_id = $1 AND closed_at IS NULL
+	RETURNING id`
+	err := r.db.QueryRow(context.Background(), query, cashierID, countedCash, note).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("shift")
+	}
+	if err != nil {
+		r.Logger.Error("Error query close shift: ", zap.Error(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *boxOfficeRepository) GetShift(id int) (*entity.CashierShift, error) {
+	query := `SELECT ` + shiftColumns + `
+	` + shiftJoins + `
+	WHERE cs.id = $1`
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get shift: ", zap.Error(err))
+		return nil, err
+	}
+	shifts, err := r.scanShifts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(shifts) == 0 {
+		return nil, utils.ErrNotFound("shift")
+	}
+	return &shifts[0], nil
+}
+
+// GetShifts lists shifts opened between from and to, cashierID 0 means all cashiers
+func (r *boxOfficeRepository) GetShifts(from time.Time, to time.Time, cashierID int) ([]entity.CashierShift, error) {
+	query := `SELECT ` + shiftColumns + `
+	` + shiftJoins + `
+	WHERE cs.opened_at >= $1 AND cs.opened_at < $2 AND ($3 = 0 OR cs.cashier_id = $3)
+	ORDER BY u.name, cs.opened_at`
+	rows, err := r.db.Query(context.Background(), query, from, to, cashierID)
+	if err != nil {
+		r.Logger.Error("Error query get shifts: ", zap.Error(err))
+		return nil, err
+	}
+	return r.scanShifts(rows)
+}
+
+// Sell books seats for a walk-in customer and records the payment taken at
+// the counter as settled, so the tickets are issued at once
+func (r *boxOfficeRepository) Sell(cashier entity.User, data dto.BoxOfficeRequest) (int, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Sales go into the cashier's open shift
+	var shiftID int
+	query := `SELECT id FROM cashier_shifts WHERE cashier_id = $1 AND closed_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, cashier.ID).Scan(&shiftID)
+	if err == pgx.ErrNoRows {
+		err = errors.New("open a shift before selling")
+		return 0, err
+	}
+	if err != nil {
+		r.Logger.Error("Error query get open shift: ", zap.Error(err))
+		return 0, err
+	}
+
+	// Validate screening, locked like online bookings for it
+	var cinemaID, studioID int
+	var price float64
+	query = `SELECT st.cinema_id, s.studio_id, st.price + f.surcharge
+	FROM screenings s
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN screening_formats f ON f.code = s.format
+	WHERE s.id = $1
+		AND s.deleted_at IS NULL
+		AND NOW() < s.start_time + (m.duration_minute * INTERVAL '1 minute')
+	FOR UPDATE OF s`
+	err = tx.QueryRow(context.Background(), query, data.ScreeningID).Scan(&cinemaID, &studioID, &price)
+	if err != nil {
+		r.Logger.Error("Booking is closed for this screening: ", zap.Error(err))
+		err = errors.New("booking is closed for this screening")
+		return 0, err
+	}
+	if cashier.CinemaID != nil && *cashier.CinemaID != cinemaID {
+		err = errors.New("screening is not at your cinema")
+		return 0, err
+	}
+
+	// Validate pre-sale window
+	err = checkPresale(tx, data.ScreeningID)
+	if err != nil {
+		r.Logger.Error("Booking is not open for this screening: ", zap.Error(err))
+		return 0, err
+	}
+
+	err = releaseExpiredHolds(tx, data.ScreeningID)
+	if err != nil {
+		r.Logger.Error("Error release expired holds: ", zap.Error(err))
+		return 0, err
+	}
+
+	var inStudio int
+	query = `SELECT COUNT(*) FROM seats WHERE id = ANY($1::int[]) AND studio_id = $2 AND deleted_at IS NULL`
+	err = tx.QueryRow(context.Background(), query, data.Seats, studioID).Scan(&inStudio)
+	if err != nil {
+		r.Logger.Error("Error query check seats: ", zap.Error(err))
+		return 0, err
+	}
+	if inStudio != len(data.Seats) {
+		err = errors.New("one or more seats are not in this studio")
+		return 0, err
+	}
+
+	// Seats offered to waitlisted users are kept for them
+	_, free, offered, err := seatCounts(tx, data.ScreeningID, 0)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return 0, err
+	}
+	if len(data.Seats) > free-offered && len(data.Seats) <= free {
+		err = errors.New("seats are held for waitlisted customers")
+		return 0, err
+	}
+
+	amount := price * float64(len(data.Seats))
+	var tendered *float64
+	var approvalCode *string
+	if data.PaymentMethod == "cash" {
+		if data.Tendered < amount {
+			err = errors.New("cash received is less than the total")
+			return 0, err
+		}
+		tendered = &data.Tendered
+	} else {
+		approvalCode = &data.ApprovalCode
+	}
+
+	var methodID int
+	query = `SELECT id FROM payment_methods WHERE kind = $1 ORDER BY id LIMIT 1`
+	err = tx.QueryRow(context.Background(), query, data.PaymentMethod).Scan(&methodID)
+	if err != nil {
+		r.Logger.Error("Error query get payment method: ", zap.Error(err))
+		return 0, err
+	}
+
+	// Create booking
+	var bookingID int
+	query = `INSERT INTO bookings (user_id, screening_id, status, expired_at, cashier_id, customer_phone, channel, created_at, updated_at)
+	VALUES (NULL, $1, 'paid', NOW(), $2, NULLIF($3, ''), 'box_office', NOW(), NOW()) RETURNING id`
+	err = tx.QueryRow(context.Background(), query, data.ScreeningID, cashier.ID, data.CustomerPhone).Scan(&bookingID)
+	if err != nil {
+		r.Logger.Error("Error query create bookings: ", zap.Error(err))
+		return 0, err
+	}
+
+	for _, seatID := range data.Seats {
+		query = `INSERT INTO booking_seats (booking_id, screening_id, seat_id, booking_status, created_at)
+		VALUES ($1, $2, $3, 'paid', NOW())`
+		_, err = tx.Exec(context.Background(), query, bookingID, data.ScreeningID, seatID)
+		if err != nil {
+			r.Logger.Error("Error query create booking_seats: ", zap.Error(err))
+			err = errors.New("one or more seats already booked")
+			return 0, err
+		}
+	}
+
+	err = issueTickets(tx, bookingID, data.Seats)
+	if err != nil {
+		r.Logger.Error("Error query create ticket: ", zap.Error(err))
+		return 0, err
+	}
+
+	// Payment taken at the counter is settled on the spot
+	query = `INSERT INTO payments (booking_id, payment_method_id, amount, status, transaction_id, type, shift_id, tendered, created_at, updated_at)
+	VALUES ($1, $2, $3, 'success', $4, 'payment', $5, $6, NOW(), NOW())`
+	_, err = tx.Exec(context.Background(), query, bookingID, methodID, amount, approvalCode, shiftID, tendered)
+	if err != nil {
+		r.Logger.Error("Error query create payment: ", zap.Error(err))
+		return 0, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return bookingID, nil
+}
+
+// GetSale returns a box office booking with its payment and valid tickets
+func (r *boxOfficeRepository) GetSale(bookingID int) (*entity.BoxOfficeSale, error) {
+	var sale entity.BoxOfficeSale
+	query := `SELECT b.id, s.id, m.id, m.title, c.name, st.name, s.format, s.start_time, b.status, b.customer_phone,
+		b.cashier_id, u.name, p.shift_id, p.id, pm.name, pm.kind, st.price + f.surcharge, p.amount, p.tendered,
+		p.transaction_id, b.created_at
+	FROM bookings b
+	JOIN screenings s ON s.id = b.screening_id
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id
+	JOIN screening_formats f ON f.code = s.format
+	JOIN users u ON u.id = b.cashier_id
+	JOIN payments p ON p.booking_id = b.id AND p.type = 'payment' AND p.status = 'success'
+	JOIN payment_methods pm ON pm.id = p.payment_method_id
+	WHERE b.id = $1 AND b.channel = 'box_office'`
+	err := r.db.QueryRow(context.Background(), query, bookingID).Scan(&sale.BookingID, &sale.ScreeningID, &sale.MovieID,
+		&sale.MovieTitle, &sale.CinemaName, &sale.StudioName, &sale.Format, &sale.StartTime, &sale.Status, &sale.CustomerPhone,
+		&sale.CashierID, &sale.CashierName, &sale.ShiftID, &sale.PaymentID, &sale.PaymentMethod, &sale.PaymentKind, &sale.Price,
+		&sale.Amount, &sale.Tendered, &sale.ApprovalCode, &sale.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("sale")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get box office sale: ", zap.Error(err))
+		return nil, err
+	}
+
+	query = `SELECT ` + ticketColumns + `
+	` + ticketJoins + `
+	WHERE t.booking_id = $1 AND t.revoked_at IS NULL
+	ORDER BY se.seat_code`
+	rows, err := r.db.Query(context.Background(), query, bookingID)
+	if err != nil {
+		r.Logger.Error("Error query get sale tickets: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			r.Logger.Error("Error scan ticket: ", zap.Error(err))
+			return nil, err
+		}
+		sale.Tickets = append(sale.Tickets, *t)
+	}
+	return &sale, rows.Err()
+}
+
+func (r *boxOfficeRepository) scanShifts(rows pgx.Rows) ([]entity.CashierShift, error) {
+	defer rows.Close()
+
+	var shifts []entity.CashierShift
+	for rows.Next() {
+		var s entity.CashierShift
+		err := rows.Scan(&s.ID, &s.CashierID, &s.CashierName, &s.CinemaID, &s.CinemaName, &s.OpeningFloat,
+			&s.CountedCash, &s.Note, &s.OpenedAt, &s.ClosedAt, &s.Bookings, &s.Tickets, &s.CashSales, &s.EDCSales)
+		if err != nil {
+			r.Logger.Error("Error scan shift: ", zap.Error(err))
+			return nil, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"go.uber.org/zap"
+)
+
+// errRowSkipped marks a row that already exists and is left untouched
+var errRowSkipped = errors.New("row already exists")
+
+// rowError is a problem with the content of an import row
+type rowError struct {
+	Field   string
+	Message string
+}
+
+func (e rowError) Error() string {
+	return e.Message
+}
+
+type CatalogRepository interface {
+	ImportMovies(rows []dto.MovieImportRow, skip map[int]bool, commit bool) (*dto.ImportReport, error)
+	ImportScreenings(rows []dto.ScreeningImportRow, skip map[int]bool, commit bool) (*dto.ImportReport, error)
+	ExportMovies() ([]dto.MovieImportRow, error)
+	ExportScreenings(from time.Time, to time.Time) ([]dto.ScreeningImportRow, error)
+}
+
+type catalogRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewCatalogRepository(db database.PgxIface, log *zap.Logger) CatalogRepository {
+	return &catalogRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *catalogRepository) ImportMovies(rows []dto.MovieImportRow, skip map[int]bool, commit bool) (*dto.ImportReport, error) {
+	return r.importRows(len(rows), skip, commit, func(tx pgx.Tx, i int) error {
+		return importMovie(tx, rows[i])
+	})
+}
+
+func (r *catalogRepository) ImportScreenings(rows []dto.ScreeningImportRow, skip map[int]bool, commit bool) (*dto.ImportReport, error) {
+	return r.importRows(len(rows), skip, commit, func(tx pgx.Tx, i int) error {
+		return importScreening(tx, rows[i])
+	})
+}
+
+// importRows applies every row in one transaction with a savepoint per row, so
+// a failing row is reported without hiding problems in the rows after it. The
+// transaction is only committed when asked and no row failed.
+func (r *catalogRepository) importRows(total int, skip map[int]bool, commit bool, apply func(tx pgx.Tx, i int) error) (*dto.ImportReport, error) {
+	report := dto.ImportReport{Total: total}
+
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback(context.Background())
+	}()
+
+	for i := 0; i < total; i++ {
+		if skip[i] {
+			continue
+		}
+
+		sp, err := tx.Begin(context.Background())
+		if err != nil {
+			r.Logger.Error("Error create savepoint: ", zap.Error(err))
+			return nil, err
+		}
+
+		err = apply(sp, i)
+		var re rowError
+		switch {
+		case err == nil:
+			report.Created++
+		case errors.Is(err, errRowSkipped):
+			report.Skipped++
+		case errors.As(err, &re):
+			report.Errors = append(report.Errors, dto.ImportRowError{Row: i + 1, Field: re.Field, Message: re.Message})
+		default:
+			// Database errors are reported on the row, details stay in the log
+			r.Logger.Error("Error query import row: ", zap.Int("row", i+1), zap.Error(err))
+			report.Errors = append(report.Errors, dto.ImportRowError{Row: i + 1, Message: "row could not be saved"})
+		}
+
+		if err != nil && !errors.Is(err, errRowSkipped) {
+			err = sp.Rollback(context.Background())
+		} else {
+			err = sp.Commit(context.Background())
+		}
+		if err != nil {
+			r.Logger.Error("Error release savepoint: ", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if commit && len(report.Errors) == 0 && len(skip) == 0 {
+		err = tx.Commit(context.Background())
+		if err != nil {
+			r.Logger.Error("Error commit import: ", zap.Error(err))
+			return nil, err
+		}
+		report.Applied = true
+	}
+	return &report, nil
+}
+
+func importMovie(tx pgx.Tx, row dto.MovieImportRow) error {
+	releaseDate, err := time.Parse("2006-01-02", row.ReleaseDate)
+	if err != nil {
+		return rowError{Field: "release_date", Message: "release_date must use YYYY-MM-DD"}
+	}
+
+	// Same title and release date is the same movie
+	var exists bool
+	query := `SELECT EXISTS (
+		SELECT 1 FROM movies WHERE LOWER(title) = LOWER($1) AND release_date = $2 AND deleted_at IS NULL
+	)`
+	err = tx.QueryRow(context.Background(), query, row.Title, releaseDate).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errRowSkipped
+	}
+
+	// Resolve genres by name, unknown ones are created
+	var genreIDs []int
+	for _, name := range row.Genres {
+		var id int
+		query = `SELECT id FROM genres WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL ORDER BY id LIMIT 1`
+		err = tx.QueryRow(context.Background(), query, name).Scan(&id)
+		if err == pgx.ErrNoRows {
+			query = `INSERT INTO genres (name, created_at, updated_at) VALUES ($1, NOW(), NOW()) RETURNING id`
+			err = tx.QueryRow(context.Background(), query, name).Scan(&id)
+		}
+		if err != nil {
+			return err
+		}
+		genreIDs = append(genreIDs, id)
+	}
+
+	var movieID int
+	query = `INSERT INTO movies (title, synopsis, poster_url, trailer_url, duration_minute, release_date, language,
+	rating_age, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+	RETURNING id`
+	err = tx.QueryRow(context.Background(), query, row.Title, row.Synopsis, row.PosterURL, row.TrailerURL,
+		row.Duration, releaseDate, row.Language, row.RatingAge).Scan(&movieID)
+	if err != nil {
+		return err
+	}
+
+	for _, genreID := range genreIDs {
+		_, err = tx.Exec(context.Background(), `INSERT INTO genre_movies (genre_id, movie_id) VALUES ($1, $2)`, genreID, movieID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importScreening(tx pgx.Tx, row dto.ScreeningImportRow) error {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	startTime, err := time.ParseInLocation("02-01-2006 15.04", row.StartTime, loc)
+	if err != nil {
+		return rowError{Field: "start_time", Message: "start_time must use DD-MM-YYYY HH.MM"}
+	}
+	startTime = startTime.UTC()
+
+	// Resolve studio by cinema and studio name
+	query := `SELECT st.id
+	FROM studios st
+	JOIN cinemas c ON c.id = st.cinema_id
+	WHERE LOWER(c.name) = LOWER($1) AND LOWER(st.name) = LOWER($2)
+		AND c.deleted_at IS NULL AND st.deleted_at IS NULL
+	LIMIT 2`
+	studioID, err := resolveOne(tx, query, row.Cinema, row.Studio)
+	if err != nil {
+		return rowError{Field: "studio", Message: fmt.Sprintf("studio %q in cinema %q %s", row.Studio, row.Cinema, err.Error())}
+	}
+
+	// Resolve movie by title
+	query = `SELECT id FROM movies WHERE LOWER(title) = LOWER($1) AND deleted_at IS NULL LIMIT 2`
+	movieID, err := resolveOne(tx, query, row.Movie)
+	if err != nil {
+		return rowError{Field: "movie", Message: fmt.Sprintf("movie %q %s", row.Movie, err.Error())}
+	}
+
+	// Empty format means 2D
+	format := strings.ToUpper(strings.TrimSpace(row.Format))
+	if format == "" {
+		format = "2D"
+	}
+	var known bool
+	query = `SELECT EXISTS (SELECT 1 FROM screening_formats WHERE code = $1)`
+	err = tx.QueryRow(context.Background(), query, format).Scan(&known)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return rowError{Field: "format", Message: fmt.Sprintf("unknown screening format %q", row.Format)}
+	}
+
+	// Same studio, movie and time is the same screening
+	var exists bool
+	query = `SELECT EXISTS (
+		SELECT 1 FROM screenings WHERE studio_id = $1 AND movie_id = $2 AND start_time = $3 AND deleted_at IS NULL
+	)`
+	err = tx.QueryRow(context.Background(), query, studioID, movieID, startTime).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errRowSkipped
+	}
+
+	// Reject screenings running into another one in the same studio,
+	// including rows imported earlier in this file
+	var overlap bool
+	query = `SELECT EXISTS (
+		SELECT 1
+		FROM screenings s
+		JOIN movies m ON m.id = s.movie_id
+		WHERE s.studio_id = $1
+			AND s.deleted_at IS NULL
+			AND s.start_time < $2::timestamptz + ((SELECT duration_minute FROM movies WHERE id = $3) * INTERVAL '1 minute')
+			AND s.start_time + (m.duration_minute * INTERVAL '1 minute') > $2::timestamptz
+	)`
+	err = tx.QueryRow(context.Background(), query, studioID, startTime, movieID).Scan(&overlap)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return rowError{Field: "start_time", Message: "overlaps another screening in this studio"}
+	}
+
+	reason, err := screeningOutsideHours(tx, studioID, movieID, startTime)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		return rowError{Field: "start_time", Message: reason}
+	}
+
+	query = `INSERT INTO screenings (studio_id, movie_id, start_time, format, audio_language, subtitle_language, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())`
+	_, err = tx.Exec(context.Background(), query, studioID, movieID, startTime, format,
+		nullString(strings.TrimSpace(row.AudioLanguage)), nullString(strings.TrimSpace(row.SubtitleLanguage)))
+	return err
+}
+
+// resolveOne runs a lookup by name that must match exactly one row
+func resolveOne(tx pgx.Tx, query string, args ...any) (int, error) {
+	rows, err := tx.Query(context.Background(), query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	switch len(ids) {
+	case 0:
+		return 0, errors.New("not found")
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, errors.New("is ambiguous")
+	}
+}
+
+func (r *catalogRepository) ExportMovies() ([]dto.MovieImportRow, error) {
+	query := `SELECT m.title, COALESCE(m.synopsis, ''),
+	ARRAY(
+		SELECT g.name FROM genre_movies gm JOIN genres g ON g.id = gm.genre_id
+		WHERE gm.movie_id = m.id AND g.deleted_at IS NULL ORDER BY g.name
+	) AS genres,
+	COALESCE(m.poster_url, ''), COALESCE(m.trailer_url, ''), m.duration_minute, m.release_date,
+	COALESCE(m.language, ''), COALESCE(m.rating_age, '')
+	FROM movies m
+	WHERE m.deleted_at IS NULL
+	ORDER BY m.release_date ASC, m.title ASC`
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		r.Logger.Error("Error query export movies: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movies []dto.MovieImportRow
+	for rows.Next() {
+		var m dto.MovieImportRow
+		var releaseDate *time.Time
+		err := rows.Scan(&m.Title, &m.Synopsis, &m.Genres, &m.PosterURL, &m.TrailerURL, &m.Duration,
+			&releaseDate, &m.Language, &m.RatingAge)
+		if err != nil {
+			r.Logger.Error("Error scan export movie: ", zap.Error(err))
+			return nil, err
+		}
+		if releaseDate != nil {
+			m.ReleaseDate = releaseDate.UTC().Format("2006-01-02")
+		}
+		movies = append(movies, m)
+	}
+	return movies, nil
+}
+
+func (r *catalogRepository) ExportScreenings(from time.Time, to time.Time) ([]dto.ScreeningImportRow, error) {
+	query := `SELECT c.name, st.name, m.title, s.start_time, s.format,
+		COALESCE(s.audio_language, ''), COALESCE(s.subtitle_language, '')
+	FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id
+	JOIN movies m ON m.id = s.movie_id
+	WHERE s.deleted_at IS NULL
+		AND s.start_time >= $1 AND s.start_time < $2
+	ORDER BY s.start_time ASC, c.name ASC, st.name ASC`
+	rows, err := r.db.Query(context.Background(), query, from, to)
+	if err != nil {
+		r.Logger.Error("Error query export screenings: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	var screenings []dto.ScreeningImportRow
+	for rows.Next() {
+		var s dto.ScreeningImportRow
+		var startTime time.Time
+		err := rows.Scan(&s.Cinema, &s.Studio, &s.Movie, &startTime, &s.Format, &s.AudioLanguage, &s.SubtitleLanguage)
+		if err != nil {
+			r.Logger.Error("Error scan export screening: ", zap.Error(err))
+			return nil, err
+		}
+		s.StartTime = startTime.In(loc).Format("02-01-2006 15.04")
+		screenings = append(screenings, s)
+	}
+	return screenings, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -13,11 +14,16 @@ import (
 )
 
 type CinemaRepository interface{
-	GetAll(q dto.PaginationQuery) ([]entity.Cinema, int, error)
+	GetAll(q dto.CinemaQuery) ([]entity.Cinema, int, error)
+	GetNearest(q dto.NearestCinemaQuery) ([]dto.CinemaResponse, int, error)
 	GetByID(id int) (*dto.CinemaResponse, error)
 	Create(cinema entity.Cinema) (*entity.Cinema, error)
 	Update(id int, w *entity.Cinema) error
 	Delete(id int) error
+	GetAmenities() ([]entity.Amenity, error)
+	GetClosures(cinemaID int, from time.Time) ([]entity.CinemaClosure, error)
+	CreateClosure(closure entity.CinemaClosure) (*entity.CinemaClosure, error)
+	DeleteClosure(cinemaID int, id int) error
 }
 
 type cinemaRepository struct {
@@ -25,6 +31,30 @@ type cinemaRepository struct {
 	Logger *zap.Logger
 }
 
+// cinemaDistance is the great-circle distance in km from the point in the
+// given parameters using the haversine formula, LEAST guards asin against
+// rounding just above 1
+func cinemaDistance(lat string, lng string) string {
+	return `6371 * 2 * asin(LEAST(1, sqrt(
+	power(sin(radians(latitude - ` + lat + `) / 2), 2) +
+	cos(radians(` + lat + `)) * cos(radians(latitude)) * power(sin(radians(longitude - ` + lng + `) / 2), 2)
+)))`
+}
+
+// cinemaAmenities lists the amenity codes of the cinema table or alias given
+func cinemaAmenities(cinema string) string {
+	return `COALESCE((SELECT array_agg(ca.amenity_code ORDER BY ca.amenity_code)
+	FROM cinema_amenities ca WHERE ca.cinema_id = ` + cinema + `.id), '{}')`
+}
+
+// amenityFilter keeps cinemas having every amenity in the given parameter
+func amenityFilter(param string) string {
+	return ` AND (` + param + `::text[] IS NULL OR NOT EXISTS (
+		SELECT 1 FROM unnest(` + param + `::text[]) a(code)
+		WHERE NOT EXISTS (SELECT 1 FROM cinema_amenities ca WHERE ca.cinema_id = cinemas.id AND ca.amenity_code = a.code)
+	))`
+}
+
 func NewCinemaRepository(db database.PgxIface, log *zap.Logger) CinemaRepository {
 	return &cinemaRepository{
 		db:     db,
@@ -32,14 +62,16 @@ func NewCinemaRepository(db database.PgxIface, log *zap.Logger) CinemaRepository
 	}
 }
 
-func (r *cinemaRepository) GetAll(q dto.PaginationQuery) ([]entity.Cinema, int, error) {
+func (r *cinemaRepository) GetAll(q dto.CinemaQuery) ([]entity.Cinema, int, error) {
 	var offset int
 	offset = (q.Page - 1) * q.Limit
+	filter := ` AND ($1::text IS NULL OR lower(city) = lower($1))` + amenityFilter("$2")
+	city := nullString(q.City)
 	
 	// Get total data for pagination
 	var total int
-	countQuery := `SELECT COUNT(*) FROM cinemas WHERE deleted_at IS NULL`
-	err := r.db.QueryRow(context.Background(), countQuery).Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM cinemas WHERE deleted_at IS NULL` + filter
+	err := r.db.QueryRow(context.Background(), countQuery, city, q.Amenities).Scan(&total)
 	if err != nil {
 		r.Logger.Error("Error query count cinemas: ", zap.Error(err))
 		return nil, 0, err
@@ -49,13 +81,14 @@ func (r *cinemaRepository) GetAll(q dto.PaginationQuery) ([]entity.Cinema, int,
 	var rows pgx.Rows
 	
 	// Conditional query based on page, limit, and all param
-	query := `SELECT id, name, location, created_at, updated_at FROM cinemas WHERE deleted_at IS NULL`
+	query := `SELECT id, name, location, address, city, region, latitude, longitude, ` + cinemaAmenities("cinemas") + `, created_at, updated_at
+	FROM cinemas WHERE deleted_at IS NULL` + filter + ` ORDER BY id ASC`
 
 	if !q.All && q.Limit > 0 {
-		query += ` LIMIT $1 OFFSET $2`
-		rows, err = r.db.Query(context.Background(), query, q.Limit, offset)
+		query += ` LIMIT $3 OFFSET $4`
+		rows, err = r.db.Query(context.Background(), query, city, q.Amenities, q.Limit, offset)
 	} else {
-		rows, err = r.db.Query(context.Background(), query)
+		rows, err = r.db.Query(context.Background(), query, city, q.Amenities)
 	}
 	
 	if err != nil {
@@ -67,7 +100,7 @@ func (r *cinemaRepository) GetAll(q dto.PaginationQuery) ([]entity.Cinema, int,
 	var cinemas []entity.Cinema
 	for rows.Next() {
 		var c entity.Cinema
-		err := rows.Scan(&c.ID, &c.Name, &c.Location, &c.CreatedAt, &c.UpdatedAt)
+		err := rows.Scan(&c.ID, &c.Name, &c.Location, &c.Address, &c.City, &c.Region, &c.Latitude, &c.Longitude, &c.Amenities, &c.CreatedAt, &c.UpdatedAt)
 		if err != nil {
 			r.Logger.Error("Error scan cinema: ", zap.Error(err))
 			return nil, 0, err
@@ -77,11 +110,70 @@ func (r *cinemaRepository) GetAll(q dto.PaginationQuery) ([]entity.Cinema, int,
 	return cinemas, total, nil
 }
 
+// GetNearest lists cinemas with coordinates by distance from the given point
+func (r *cinemaRepository) GetNearest(q dto.NearestCinemaQuery) ([]dto.CinemaResponse, int, error) {
+	var offset int
+	offset = (q.Page - 1) * q.Limit
+
+	// Radius 0 means no limit
+	var radius *float64
+	if q.RadiusKm > 0 {
+		radius = &q.RadiusKm
+	}
+
+	nearby := `SELECT id, name, location, address, city, region, latitude, longitude, ` + cinemaAmenities("cinemas") + ` AS amenities,
+	` + cinemaDistance("$1", "$2") + ` AS distance
+	FROM cinemas
+	WHERE deleted_at IS NULL AND latitude IS NOT NULL AND longitude IS NOT NULL
+	AND ($3::text IS NULL OR lower(city) = lower($3))` + amenityFilter("$5")
+	filter := ` WHERE ($4::float8 IS NULL OR distance <= $4)`
+	args := []any{q.Latitude, q.Longitude, nullString(q.City), radius, q.Amenities}
+
+	// Get total data for pagination
+	var total int
+	countQuery := `SELECT COUNT(*) FROM (` + nearby + `) c` + filter
+	err := r.db.QueryRow(context.Background(), countQuery, args...).Scan(&total)
+	if err != nil {
+		r.Logger.Error("Error query count nearest cinemas: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `SELECT id, name, location, address, city, region, latitude, longitude, amenities, distance
+	FROM (` + nearby + `) c` + filter + ` ORDER BY distance ASC, id ASC`
+	if !q.All && q.Limit > 0 {
+		query += ` LIMIT $6 OFFSET $7`
+		args = append(args, q.Limit, offset)
+	}
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.Logger.Error("Error query get nearest cinemas: ", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var cinemas []dto.CinemaResponse
+	for rows.Next() {
+		var c dto.CinemaResponse
+		var distance float64
+		err := rows.Scan(&c.CinemaID, &c.Name, &c.Location, &c.Address, &c.City, &c.Region, &c.Latitude, &c.Longitude, &c.Amenities, &distance)
+		if err != nil {
+			r.Logger.Error("Error scan nearest cinema: ", zap.Error(err))
+			return nil, 0, err
+		}
+		c.DistanceKm = &distance
+		cinemas = append(cinemas, c)
+	}
+	return cinemas, total, nil
+}
+
 func (r *cinemaRepository) GetByID(id int) (*dto.CinemaResponse, error) {
 	var cinema dto.CinemaResponse
-	query := "SELECT id, name, location FROM cinemas WHERE id = $1 AND deleted_at IS NULL"
+	query := `SELECT id, name, location, address, city, region, latitude, longitude, ` + cinemaAmenities("cinemas") + `
+	FROM cinemas WHERE id = $1 AND deleted_at IS NULL`
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&cinema.CinemaID, &cinema.Name, &cinema.Location)
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&cinema.CinemaID, &cinema.Name, &cinema.Location,
+		&cinema.Address, &cinema.City, &cinema.Region, &cinema.Latitude, &cinema.Longitude, &cinema.Amenities)
 
 	if err == pgx.ErrNoRows {
 		r.Logger.Error("Error not found cinema: ", zap.Error(err))
@@ -93,51 +185,128 @@ func (r *cinemaRepository) GetByID(id int) (*dto.CinemaResponse, error) {
 		return nil, err
 	}
 
+	hours, err := getOpeningHours(r.db, id)
+	if err != nil {
+		r.Logger.Error("Error query get opening hours: ", zap.Error(err))
+		return nil, err
+	}
+	for _, h := range hours {
+		cinema.OpeningHours = append(cinema.OpeningHours, dto.OpeningHourResponse{
+			Weekday:   h.Weekday,
+			Day:       time.Weekday(h.Weekday).String(),
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		})
+	}
+
 	return &cinema, nil
 }
 
 func (r *cinemaRepository) Create(cinema entity.Cinema) (*entity.Cinema, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
 	query := `
-		INSERT INTO cinemas (name, location, created_at, updated_at)
-		VALUES ($1, $2, NOW(), NOW())
+		INSERT INTO cinemas (name, location, address, city, region, latitude, longitude, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
 		RETURNING id
 	`
-	err := r.db.QueryRow(context.Background(), query, cinema.Name, cinema.Location).Scan(&cinema.ID)
+	err = tx.QueryRow(context.Background(), query, cinema.Name, cinema.Location,
+		cinema.Address, cinema.City, cinema.Region, cinema.Latitude, cinema.Longitude,
+	).Scan(&cinema.ID)
 	if err != nil {
 		r.Logger.Error("Error query create cinema: ", zap.Error(err))
 		return nil, err
 	}
 
+	err = saveOpeningHours(tx, cinema.ID, cinema.OpeningHours)
+	if err != nil {
+		r.Logger.Error("Error query save opening hours: ", zap.Error(err))
+		return nil, err
+	}
+	err = saveAmenities(tx, cinema.ID, cinema.Amenities)
+	if err != nil {
+		r.Logger.Error("Error query save cinema amenities: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
 	cinema.CreatedAt = time.Now()
 	cinema.UpdatedAt = time.Now()
 	return &cinema, nil
 }
 
 func (r *cinemaRepository) Update(id int, c *entity.Cinema) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
 	query := `
 		UPDATE cinemas
 		SET name = COALESCE($1, name),
 		location = COALESCE($2, location),
+		address = COALESCE($3, address),
+		city = COALESCE($4, city),
+		region = COALESCE($5, region),
+		latitude = COALESCE($6, latitude),
+		longitude = COALESCE($7, longitude),
 		updated_at = NOW()
-		WHERE id = $3 AND deleted_at IS NULL
+		WHERE id = $8 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.Exec(context.Background(), query,
-		&c.Name, &c.Location, id,
+	result, err := tx.Exec(context.Background(), query,
+		&c.Name, &c.Location, c.Address, c.City, c.Region, c.Latitude, c.Longitude, id,
 	)
+	if err != nil {
+		r.Logger.Error("Error query update cinema: ", zap.Error(err))
+		return err
+	}
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
+		err = utils.ErrNotFound("cinema")
 		r.Logger.Error("Error not found cinema: ", zap.Error(err))
-		return utils.ErrNotFound("cinema")
+		return err
 	}
 
-	if err != nil {
-		r.Logger.Error("Error query update cinema: ", zap.Error(err))
-		return err
+	// Hours and amenities are replaced only when given
+	if c.OpeningHours != nil {
+		err = saveOpeningHours(tx, id, c.OpeningHours)
+		if err != nil {
+			r.Logger.Error("Error query save opening hours: ", zap.Error(err))
+			return err
+		}
+	}
+	if c.Amenities != nil {
+		err = saveAmenities(tx, id, c.Amenities)
+		if err != nil {
+			r.Logger.Error("Error query save cinema amenities: ", zap.Error(err))
+			return err
+		}
 	}
 
-	return nil
+	return tx.Commit(context.Background())
 }
 
 func (r *cinemaRepository) Delete(id int) error {
@@ -161,4 +330,162 @@ func (r *cinemaRepository) Delete(id int) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *cinemaRepository) GetAmenities() ([]entity.Amenity, error) {
+	query := `SELECT code, name FROM amenities ORDER BY name ASC`
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		r.Logger.Error("Error query get amenities: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	amenities := []entity.Amenity{}
+	for rows.Next() {
+		var a entity.Amenity
+		err := rows.Scan(&a.Code, &a.Name)
+		if err != nil {
+			r.Logger.Error("Error scan amenity: ", zap.Error(err))
+			return nil, err
+		}
+		amenities = append(amenities, a)
+	}
+	return amenities, nil
+}
+
+// GetClosures lists closures on or after the given local date
+func (r *cinemaRepository) GetClosures(cinemaID int, from time.Time) ([]entity.CinemaClosure, error) {
+	query := `SELECT cl.id, cl.cinema_id, cl.date, cl.reason, cl.created_at
+	FROM cinema_closures cl
+	JOIN cinemas c ON c.id = cl.cinema_id AND c.deleted_at IS NULL
+	WHERE cl.cinema_id = $1 AND cl.date >= $2::date
+	ORDER BY cl.date ASC`
+	rows, err := r.db.Query(context.Background(), query, cinemaID, from.Format("2006-01-02"))
+	if err != nil {
+		r.Logger.Error("Error query get cinema closures: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	closures := []entity.CinemaClosure{}
+	for rows.Next() {
+		var cl entity.CinemaClosure
+		err := rows.Scan(&cl.ID, &cl.CinemaID, &cl.Date, &cl.Reason, &cl.CreatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan cinema closure: ", zap.Error(err))
+			return nil, err
+		}
+		closures = append(closures, cl)
+	}
+	return closures, nil
+}
+
+func (r *cinemaRepository) CreateClosure(closure entity.CinemaClosure) (*entity.CinemaClosure, error) {
+	// Check cinema
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM cinemas WHERE id = $1 AND deleted_at IS NULL)`
+	err := r.db.QueryRow(context.Background(), query, closure.CinemaID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check cinema: ", zap.Error(err))
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrNotFound("cinema")
+	}
+
+	// Screenings already sold for that day must be moved first
+	date := closure.Date.Format("2006-01-02")
+	var screenings int
+	query = `SELECT COUNT(*)
+	FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	WHERE st.cinema_id = $1 AND s.deleted_at IS NULL
+		AND (s.start_time AT TIME ZONE 'Asia/Jakarta')::date = $2::date`
+	err = r.db.QueryRow(context.Background(), query, closure.CinemaID, date).Scan(&screenings)
+	if err != nil {
+		r.Logger.Error("Error query count screenings on closure date: ", zap.Error(err))
+		return nil, err
+	}
+	if screenings > 0 {
+		return nil, fmt.Errorf("cinema has %d screenings on %s", screenings, closure.Date.Format("02-01-2006"))
+	}
+
+	query = `INSERT INTO cinema_closures (cinema_id, date, reason, created_at, updated_at)
+	VALUES ($1, $2::date, $3, NOW(), NOW())
+	ON CONFLICT (cinema_id, date) DO UPDATE SET reason = EXCLUDED.reason, updated_at = NOW()
+	RETURNING id, created_at`
+	err = r.db.QueryRow(context.Background(), query, closure.CinemaID, date, closure.Reason).Scan(&closure.ID, &closure.CreatedAt)
+	if err != nil {
+		r.Logger.Error("Error query create cinema closure: ", zap.Error(err))
+		return nil, err
+	}
+	return &closure, nil
+}
+
+func (r *cinemaRepository) DeleteClosure(cinemaID int, id int) error {
+	query := `DELETE FROM cinema_closures WHERE id = $1 AND cinema_id = $2`
+	result, err := r.db.Exec(context.Background(), query, id, cinemaID)
+	if err != nil {
+		r.Logger.Error("Error query delete cinema closure: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("closure")
+	}
+	return nil
+}
+
+type rowsQuerier interface {
+	Query(ctx context.Context, query string, args ...any) (pgx.Rows, error)
+}
+
+func getOpeningHours(db rowsQuerier, cinemaID int) ([]entity.OpeningHour, error) {
+	query := `SELECT weekday, to_char(open_time, 'HH24.MI'), to_char(close_time, 'HH24.MI')
+	FROM cinema_opening_hours WHERE cinema_id = $1 ORDER BY weekday ASC`
+	rows, err := db.Query(context.Background(), query, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hours []entity.OpeningHour
+	for rows.Next() {
+		var h entity.OpeningHour
+		if err := rows.Scan(&h.Weekday, &h.OpenTime, &h.CloseTime); err != nil {
+			return nil, err
+		}
+		hours = append(hours, h)
+	}
+	return hours, rows.Err()
+}
+
+func saveOpeningHours(tx pgx.Tx, cinemaID int, hours []entity.OpeningHour) error {
+	_, err := tx.Exec(context.Background(), `DELETE FROM cinema_opening_hours WHERE cinema_id = $1`, cinemaID)
+	if err != nil {
+		return err
+	}
+	for _, h := range hours {
+		query := `INSERT INTO cinema_opening_hours (cinema_id, weekday, open_time, close_time, created_at, updated_at)
+		VALUES ($1, $2, replace($3, '.', ':')::time, replace($4, '.', ':')::time, NOW(), NOW())`
+		_, err := tx.Exec(context.Background(), query, cinemaID, h.Weekday, h.OpenTime, h.CloseTime)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveAmenities(tx pgx.Tx, cinemaID int, codes []string) error {
+	_, err := tx.Exec(context.Background(), `DELETE FROM cinema_amenities WHERE cinema_id = $1`, cinemaID)
+	if err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	query := `INSERT INTO cinema_amenities (cinema_id, amenity_code)
+	SELECT $1, code FROM unnest($2::text[]) code ON CONFLICT DO NOTHING`
+	_, err = tx.Exec(context.Background(), query, cinemaID, codes)
+	return err
+}
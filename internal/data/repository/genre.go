@@ -2,7 +2,8 @@ package repository
 
 import (
 	"context"
-	"time"
+	"errors"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/project-app-bioskop-golang/internal/data/entity"
@@ -15,8 +16,23 @@ import (
 type GenreRepository interface{
 	GetAll(q dto.PaginationQuery) ([]entity.Genre, int, error)
 	GetByID(id int) (*entity.Genre, error)
-	Create(genre entity.Genre) (*entity.Genre, error)
+	Create(req dto.GenreRequest) (*entity.Genre, error)
+	Update(id int, req dto.GenreRequest) (*entity.Genre, error)
 	Delete(id int) error
+	Merge(id int, sourceIDs []int) (*entity.Genre, error)
+}
+
+// Display names of a genre g keyed by locale
+const genreNames = `COALESCE((SELECT jsonb_object_agg(gt.locale, gt.name) FROM genre_translations gt WHERE gt.genre_id = g.id), '{}'::jsonb)`
+
+// genreSubtree selects the ids of genres matching the condition and all of their sub-genres
+func genreSubtree(match string) string {
+	return `WITH RECURSIVE genre_tree AS (
+		SELECT id FROM genres WHERE deleted_at IS NULL AND ` + match + `
+		UNION
+		SELECT c.id FROM genres c JOIN genre_tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+	)
+	SELECT id FROM genre_tree`
 }
 
 type genreRepository struct {
@@ -48,7 +64,8 @@ func (r *genreRepository) GetAll(q dto.PaginationQuery) ([]entity.Genre, int, er
 	var rows pgx.Rows
 	
 	// Conditional query based on page, limit, and all param
-	query := `SELECT id, name, created_at, updated_at FROM genres WHERE deleted_at IS NULL`
+	query := `SELECT g.id, g.name, g.parent_id, ` + genreNames + `, g.created_at, g.updated_at
+	FROM genres g WHERE g.deleted_at IS NULL ORDER BY g.name ASC`
 
 	if !q.All && q.Limit > 0 {
 		query += ` LIMIT $1 OFFSET $2`
@@ -66,7 +83,7 @@ func (r *genreRepository) GetAll(q dto.PaginationQuery) ([]entity.Genre, int, er
 	var genres []entity.Genre
 	for rows.Next() {
 		var g entity.Genre
-		err := rows.Scan(&g.ID, &g.Name, &g.CreatedAt, &g.UpdatedAt)
+		err := rows.Scan(&g.ID, &g.Name, &g.ParentID, &g.Names, &g.CreatedAt, &g.UpdatedAt)
 		if err != nil {
 			r.Logger.Error("Error scan genre: ", zap.Error(err))
 			return nil, 0, err
@@ -78,9 +95,10 @@ func (r *genreRepository) GetAll(q dto.PaginationQuery) ([]entity.Genre, int, er
 
 func (r *genreRepository) GetByID(id int) (*entity.Genre, error) {
 	var genre entity.Genre
-	query := "SELECT id, name, created_at, updated_at FROM genres WHERE id = $1 AND deleted_at IS NULL"
+	query := `SELECT g.id, g.name, g.parent_id, ` + genreNames + `, g.created_at, g.updated_at
+	FROM genres g WHERE g.id = $1 AND g.deleted_at IS NULL`
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&genre.ID, &genre.Name, &genre.CreatedAt, &genre.UpdatedAt)
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&genre.ID, &genre.Name, &genre.ParentID, &genre.Names, &genre.CreatedAt, &genre.UpdatedAt)
 
 	if err == pgx.ErrNoRows {
 		r.Logger.Error("Error not found genre: ", zap.Error(err))
@@ -92,45 +110,333 @@ func (r *genreRepository) GetByID(id int) (*entity.Genre, error) {
 		return nil, err
 	}
 
+	// Direct sub-genres
+	query = `SELECT g.id, g.name, g.parent_id, ` + genreNames + `, g.created_at, g.updated_at
+	FROM genres g WHERE g.parent_id = $1 AND g.deleted_at IS NULL ORDER BY g.name ASC`
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get sub-genres: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var g entity.Genre
+		err := rows.Scan(&g.ID, &g.Name, &g.ParentID, &g.Names, &g.CreatedAt, &g.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan genre: ", zap.Error(err))
+			return nil, err
+		}
+		genre.Children = append(genre.Children, g)
+	}
+
 	return &genre, nil
 }
 
-func (r *genreRepository) Create(genre entity.Genre) (*entity.Genre, error) {
+func (r *genreRepository) Create(req dto.GenreRequest) (*entity.Genre, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	err = checkGenreParent(tx, 0, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
 	query := `
-		INSERT INTO genres (name, created_at, updated_at)
-		VALUES ($1, NOW(), NOW())
+		INSERT INTO genres (name, parent_id, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
 		RETURNING id
 	`
-	err := r.db.QueryRow(context.Background(), query, genre.Name).Scan(&genre.ID)
+	err = tx.QueryRow(context.Background(), query, req.Name, req.ParentID).Scan(&id)
 	if err != nil {
 		r.Logger.Error("Error query create genre: ", zap.Error(err))
 		return nil, err
 	}
 
-	genre.CreatedAt = time.Now()
-	genre.UpdatedAt = time.Now()
-	return &genre, nil
+	err = saveGenreNames(tx, id, req.Names)
+	if err != nil {
+		r.Logger.Error("Error query save genre names: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *genreRepository) Update(id int, req dto.GenreRequest) (*entity.Genre, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	err = checkGenreParent(tx, id, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE genres
+		SET name = $1, parent_id = $2, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	result, err := tx.Exec(context.Background(), query, req.Name, req.ParentID, id)
+	if err != nil {
+		r.Logger.Error("Error query update genre: ", zap.Error(err))
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		err = utils.ErrNotFound("genre")
+		r.Logger.Error("Error not found genre: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Names are replaced only when given
+	if req.Names != nil {
+		_, err = tx.Exec(context.Background(), `DELETE FROM genre_translations WHERE genre_id = $1`, id)
+		if err != nil {
+			r.Logger.Error("Error query delete genre names: ", zap.Error(err))
+			return nil, err
+		}
+		err = saveGenreNames(tx, id, req.Names)
+		if err != nil {
+			r.Logger.Error("Error query save genre names: ", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
 }
 
 func (r *genreRepository) Delete(id int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var parentID *int
 	query := `
 		UPDATE genres
 		SET deleted_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING parent_id
 	`
-
-	result, err := r.db.Exec(context.Background(), query, id)
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
+	err = tx.QueryRow(context.Background(), query, id).Scan(&parentID)
+	if err == pgx.ErrNoRows {
 		r.Logger.Error("Error not found genre: ", zap.Error(err))
 		return utils.ErrNotFound("genre")
 	}
-
 	if err != nil {
 		r.Logger.Error("Error query delete genre: ", zap.Error(err))
 		return err
 	}
 
+	// Sub-genres move up one level
+	query = `UPDATE genres SET parent_id = $1, updated_at = NOW() WHERE parent_id = $2 AND deleted_at IS NULL`
+	_, err = tx.Exec(context.Background(), query, parentID, id)
+	if err != nil {
+		r.Logger.Error("Error query reparent sub-genres: ", zap.Error(err))
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM genre_movies WHERE genre_id = $1`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete genre movies: ", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *genreRepository) Merge(id int, sourceIDs []int) (*entity.Genre, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Lock target and sources so nothing is merged twice
+	query := `SELECT id FROM genres WHERE id = ANY($1) AND deleted_at IS NULL FOR UPDATE`
+	rows, err := tx.Query(context.Background(), query, append([]int{id}, sourceIDs...))
+	if err != nil {
+		r.Logger.Error("Error query lock genres: ", zap.Error(err))
+		return nil, err
+	}
+	found := map[int]bool{}
+	for rows.Next() {
+		var gid int
+		if err = rows.Scan(&gid); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan genre: ", zap.Error(err))
+			return nil, err
+		}
+		found[gid] = true
+	}
+	rows.Close()
+	if !found[id] {
+		err = utils.ErrNotFound("genre")
+		return nil, err
+	}
+	for _, sid := range sourceIDs {
+		if !found[sid] {
+			err = utils.ErrNotFound("genre")
+			return nil, err
+		}
+	}
+
+	// Movies of the sources now belong to the target, once
+	query = `INSERT INTO genre_movies (genre_id, movie_id)
+	SELECT DISTINCT $1::int, gm.movie_id
+	FROM genre_movies gm
+	WHERE gm.genre_id = ANY($2)
+		AND NOT EXISTS (SELECT 1 FROM genre_movies x WHERE x.genre_id = $1 AND x.movie_id = gm.movie_id)`
+	_, err = tx.Exec(context.Background(), query, id, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query repoint genre movies: ", zap.Error(err))
+		return nil, err
+	}
+	_, err = tx.Exec(context.Background(), `DELETE FROM genre_movies WHERE genre_id = ANY($1)`, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query delete genre movies: ", zap.Error(err))
+		return nil, err
+	}
+
+	// A target nested under a source takes the place of the topmost source above it
+	query = `WITH RECURSIVE ancestors AS (
+		SELECT g.parent_id AS id, 1 AS depth FROM genres g WHERE g.id = $1 AND g.parent_id IS NOT NULL
+		UNION ALL
+		SELECT g.parent_id, a.depth + 1 FROM genres g JOIN ancestors a ON g.id = a.id WHERE g.parent_id IS NOT NULL
+	), topmost AS (
+		SELECT MAX(depth) AS depth FROM ancestors WHERE id = ANY($2)
+	)
+	UPDATE genres SET parent_id = (
+		SELECT a.id FROM ancestors a, topmost t WHERE a.depth = t.depth + 1
+	), updated_at = NOW()
+	WHERE id = $1 AND EXISTS (SELECT 1 FROM topmost WHERE depth IS NOT NULL)`
+	_, err = tx.Exec(context.Background(), query, id, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query reparent merged genre: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Sub-genres of the sources move under the target
+	query = `UPDATE genres SET parent_id = $1, updated_at = NOW()
+	WHERE parent_id = ANY($2) AND id <> $1 AND NOT (id = ANY($2)) AND deleted_at IS NULL`
+	_, err = tx.Exec(context.Background(), query, id, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query reparent sub-genres: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Keep display names the target does not have yet
+	query = `INSERT INTO genre_translations (genre_id, locale, name, created_at, updated_at)
+	SELECT DISTINCT ON (locale) $1::int, locale, name, NOW(), NOW()
+	FROM genre_translations
+	WHERE genre_id = ANY($2)
+	ORDER BY locale, updated_at DESC
+	ON CONFLICT (genre_id, locale) DO NOTHING`
+	_, err = tx.Exec(context.Background(), query, id, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query merge genre names: ", zap.Error(err))
+		return nil, err
+	}
+
+	query = `UPDATE genres SET parent_id = NULL, deleted_at = NOW() WHERE id = ANY($1)`
+	_, err = tx.Exec(context.Background(), query, sourceIDs)
+	if err != nil {
+		r.Logger.Error("Error query delete merged genres: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+// checkGenreParent rejects a parent that is missing, the genre itself or one of its sub-genres
+func checkGenreParent(tx pgx.Tx, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return errors.New("genre cannot be its own parent")
+	}
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM genres WHERE id = $1 AND deleted_at IS NULL)`
+	err := tx.QueryRow(context.Background(), query, *parentID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return utils.ErrNotFound("parent genre")
+	}
+	if id == 0 {
+		return nil
+	}
+
+	var cycle bool
+	query = `SELECT EXISTS (` + genreSubtree("id = $1") + ` WHERE id = $2)`
+	err = tx.QueryRow(context.Background(), query, id, *parentID).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return errors.New("parent genre cannot be one of its sub-genres")
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func saveGenreNames(tx pgx.Tx, id int, names map[string]string) error {
+	for locale, name := range names {
+		query := `INSERT INTO genre_translations (genre_id, locale, name, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (genre_id, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW()`
+		_, err := tx.Exec(context.Background(), query, id, strings.ToLower(locale), strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type IdentityRepository interface {
+	CreateState(s entity.OIDCState) error
+	ConsumeState(state string) (*entity.OIDCState, error)
+	FindUser(provider string, subject string) (*entity.User, error)
+	Link(userID int, provider string, subject string, email string) error
+	CreateUser(user entity.User, provider string, subject string) (*entity.User, error)
+	IsEmailVerified(email string) (bool, error)
+}
+
+type identityRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewIdentityRepository(db database.PgxIface, log *zap.Logger) IdentityRepository {
+	return &identityRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *identityRepository) CreateState(s entity.OIDCState) error {
+	// Clean up abandoned requests on the way
+	_, err := r.db.Exec(context.Background(), `DELETE FROM oidc_states WHERE expired_at < NOW()`)
+	if err != nil {
+		r.Logger.Error("Error query delete expired oidc states: ", zap.Error(err))
+		return err
+	}
+
+	query := `INSERT INTO oidc_states (state, nonce, code_verifier, expired_at, created_at)
+	VALUES ($1, $2, $3, $4, NOW())`
+	_, err = r.db.Exec(context.Background(), query, s.State, s.Nonce, s.CodeVerifier, s.ExpiredAt)
+	if err != nil {
+		r.Logger.Error("Error query create oidc state: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) ConsumeState(state string) (*entity.OIDCState, error) {
+	// State can only be used once
+	var s entity.OIDCState
+	query := `DELETE FROM oidc_states WHERE state = $1 AND expired_at > NOW()
+	RETURNING state, nonce, code_verifier, expired_at`
+	err := r.db.QueryRow(context.Background(), query, state).Scan(&s.State, &s.Nonce, &s.CodeVerifier, &s.ExpiredAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("state")
+	}
+	if err != nil {
+		r.Logger.Error("Error query consume oidc state: ", zap.Error(err))
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *identityRepository) FindUser(provider string, subject string) (*entity.User, error) {
+	var user entity.User
+	query := `SELECT u.id, u.name, u.email, u.role, u.created_at, u.updated_at
+	FROM user_identities i
+	JOIN users u ON u.id = i.user_id
+	WHERE i.provider = $1 AND i.subject = $2 AND u.deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, provider, subject).Scan(&user.ID, &user.Name,
+		&user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("user")
+	}
+	if err != nil {
+		r.Logger.Error("Error query find user by identity: ", zap.Error(err))
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *identityRepository) Link(userID int, provider string, subject string, email string) error {
+	query := `INSERT INTO user_identities (user_id, provider, subject, email, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, NOW(), NOW())`
+	_, err := r.db.Exec(context.Background(), query, userID, provider, subject, email)
+	if err != nil {
+		r.Logger.Error("Error query link identity: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) CreateUser(user entity.User, provider string, subject string) (*entity.User, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := `INSERT INTO users (name, email, password, role, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, NOW(), NOW())
+	RETURNING id, created_at, updated_at`
+	err = tx.QueryRow(context.Background(), query, user.Name, user.Email, user.Password, user.Role).Scan(&user.ID,
+		&user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		r.Logger.Error("Error query create user: ", zap.Error(err))
+		return nil, err
+	}
+
+	query = `INSERT INTO user_identities (user_id, provider, subject, email, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, NOW(), NOW())`
+	_, err = tx.Exec(context.Background(), query, user.ID, provider, subject, user.Email)
+	if err != nil {
+		r.Logger.Error("Error query link identity: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = nil
+	return &user, nil
+}
+
+func (r *identityRepository) IsEmailVerified(email string) (bool, error) {
+	// Local accounts are verified by a used registration OTP
+	var verified bool
+	query := `SELECT EXISTS (SELECT 1 FROM otp_codes WHERE email = $1 AND used_at IS NOT NULL)`
+	err := r.db.QueryRow(context.Background(), query, email).Scan(&verified)
+	if err != nil {
+		r.Logger.Error("Error query check email verified: ", zap.Error(err))
+		return false, err
+	}
+	return verified, nil
+}
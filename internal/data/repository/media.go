@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Tables media can be linked to
+var mediaEntityTables = map[string]string{
+	"movie":  "movies",
+	"cinema": "cinemas",
+}
+
+type MediaRepository interface {
+	Create(m entity.Media) (int, error)
+	GetByID(id int) (*entity.Media, error)
+	GetVariant(id int, variant string) (*entity.MediaVariant, error)
+	Delete(id int) ([]string, error)
+	Link(l entity.MediaLink, posterURL string) error
+	Unlink(entityType string, entityID int, mediaID int) error
+	GetLinks(entityType string, entityID int) ([]entity.MediaLink, error)
+}
+
+type mediaRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewMediaRepository(db database.PgxIface, log *zap.Logger) MediaRepository {
+	return &mediaRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *mediaRepository) Create(m entity.Media) (int, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var id int
+	query := `INSERT INTO media (original_name, mime_type, size_bytes, width, height, uploaded_by, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+	RETURNING id`
+	err = tx.QueryRow(context.Background(), query, m.OriginalName, m.MimeType, m.Size, m.Width, m.Height,
+		m.UploadedBy).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create media: ", zap.Error(err))
+		return 0, err
+	}
+
+	query = `INSERT INTO media_variants (media_id, variant, storage_key, mime_type, size_bytes, width, height)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	for _, v := range m.Variants {
+		_, err = tx.Exec(context.Background(), query, id, v.Variant, v.StorageKey, v.MimeType, v.Size, v.Width, v.Height)
+		if err != nil {
+			r.Logger.Error("Error query create media variant: ", zap.Error(err))
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *mediaRepository) GetByID(id int) (*entity.Media, error) {
+	var m entity.Media
+	query := `SELECT id, original_name, mime_type, size_bytes, width, height, uploaded_by, created_at, updated_at
+	FROM media WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&m.ID, &m.OriginalName, &m.MimeType, &m.Size,
+		&m.Width, &m.Height, &m.UploadedBy, &m.CreatedAt, &m.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found media: ", zap.Error(err))
+		return nil, utils.ErrNotFound("media")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get media by id: ", zap.Error(err))
+		return nil, err
+	}
+
+	m.Variants, err = r.getVariants(id)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mediaRepository) GetVariant(id int, variant string) (*entity.MediaVariant, error) {
+	var v entity.MediaVariant
+	query := `SELECT v.variant, v.storage_key, v.mime_type, v.size_bytes, v.width, v.height
+	FROM media_variants v
+	JOIN media m ON m.id = v.media_id
+	WHERE v.media_id = $1 AND v.variant = $2 AND m.deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, id, variant).Scan(&v.Variant, &v.StorageKey, &v.MimeType,
+		&v.Size, &v.Width, &v.Height)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("media")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get media variant: ", zap.Error(err))
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *mediaRepository) Delete(id int) ([]string, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	result, err := tx.Exec(context.Background(), `UPDATE media SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete media: ", zap.Error(err))
+		return nil, err
+	}
+	if result.RowsAffected() == 0 {
+		err = utils.ErrNotFound("media")
+		return nil, err
+	}
+
+	// Posters pointing at this media are cleared with the link
+	query := `UPDATE movies SET poster_url = '', updated_at = NOW()
+	WHERE id IN (SELECT entity_id FROM media_links WHERE media_id = $1 AND entity_type = 'movie' AND kind = 'poster')`
+	_, err = tx.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query clear movie poster: ", zap.Error(err))
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM media_links WHERE media_id = $1`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete media links: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Files are removed by the caller after commit
+	rows, err := tx.Query(context.Background(), `SELECT storage_key FROM media_variants WHERE media_id = $1`, id)
+	if err != nil {
+		r.Logger.Error("Error query get media variants: ", zap.Error(err))
+		return nil, err
+	}
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan media variant: ", zap.Error(err))
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	rows.Close()
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *mediaRepository) Link(l entity.MediaLink, posterURL string) error {
+	table, ok := mediaEntityTables[l.EntityType]
+	if !ok {
+		return errors.New("invalid media entity type")
+	}
+
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Check linked entity and media
+	var exists bool
+	err = tx.QueryRow(context.Background(), `SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`,
+		l.EntityID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check media entity: ", zap.Error(err))
+		return err
+	}
+	if !exists {
+		err = utils.ErrNotFound(l.EntityType)
+		return err
+	}
+
+	err = tx.QueryRow(context.Background(), `SELECT EXISTS (SELECT 1 FROM media WHERE id = $1 AND deleted_at IS NULL)`,
+		l.MediaID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check media: ", zap.Error(err))
+		return err
+	}
+	if !exists {
+		err = utils.ErrNotFound("media")
+		return err
+	}
+
+	// Single slot kinds replace the previous image
+	if l.Kind == "poster" || l.Kind == "logo" {
+		query := `DELETE FROM media_links WHERE entity_type = $1 AND entity_id = $2 AND kind = $3`
+		_, err = tx.Exec(context.Background(), query, l.EntityType, l.EntityID, l.Kind)
+		if err != nil {
+			r.Logger.Error("Error query replace media link: ", zap.Error(err))
+			return err
+		}
+	}
+
+	query := `INSERT INTO media_links (media_id, entity_type, entity_id, kind, position, created_at)
+	VALUES ($1, $2, $3, $4, $5, NOW())
+	ON CONFLICT (media_id, entity_type, entity_id) DO UPDATE SET kind = EXCLUDED.kind, position = EXCLUDED.position`
+	_, err = tx.Exec(context.Background(), query, l.MediaID, l.EntityType, l.EntityID, l.Kind, l.Position)
+	if err != nil {
+		r.Logger.Error("Error query create media link: ", zap.Error(err))
+		return err
+	}
+
+	// Keep the plain poster url in sync for existing clients
+	if l.EntityType == "movie" && l.Kind == "poster" {
+		_, err = tx.Exec(context.Background(), `UPDATE movies SET poster_url = $1, updated_at = NOW() WHERE id = $2`,
+			posterURL, l.EntityID)
+		if err != nil {
+			r.Logger.Error("Error query update movie poster: ", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *mediaRepository) Unlink(entityType string, entityID int, mediaID int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var kind string
+	query := `DELETE FROM media_links WHERE entity_type = $1 AND entity_id = $2 AND media_id = $3 RETURNING kind`
+	err = tx.QueryRow(context.Background(), query, entityType, entityID, mediaID).Scan(&kind)
+	if err == pgx.ErrNoRows {
+		err = utils.ErrNotFound("media")
+		return err
+	}
+	if err != nil {
+		r.Logger.Error("Error query delete media link: ", zap.Error(err))
+		return err
+	}
+
+	if entityType == "movie" && kind == "poster" {
+		_, err = tx.Exec(context.Background(), `UPDATE movies SET poster_url = '', updated_at = NOW() WHERE id = $1`, entityID)
+		if err != nil {
+			r.Logger.Error("Error query clear movie poster: ", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *mediaRepository) GetLinks(entityType string, entityID int) ([]entity.MediaLink, error) {
+	query := `SELECT l.id, l.media_id, l.entity_type, l.entity_id, l.kind, l.position,
+	m.original_name, m.mime_type, m.size_bytes, m.width, m.height, m.created_at, m.updated_at
+	FROM media_links l
+	JOIN media m ON m.id = l.media_id AND m.deleted_at IS NULL
+	WHERE l.entity_type = $1 AND l.entity_id = $2
+	ORDER BY l.kind ASC, l.position ASC, l.id ASC`
+	rows, err := r.db.Query(context.Background(), query, entityType, entityID)
+	if err != nil {
+		r.Logger.Error("Error query get media links: ", zap.Error(err))
+		return nil, err
+	}
+
+	var links []entity.MediaLink
+	for rows.Next() {
+		var l entity.MediaLink
+		var m entity.Media
+		err := rows.Scan(&l.ID, &l.MediaID, &l.EntityType, &l.EntityID, &l.Kind, &l.Position,
+			&m.OriginalName, &m.MimeType, &m.Size, &m.Width, &m.Height, &m.CreatedAt, &m.UpdatedAt)
+		if err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan media link: ", zap.Error(err))
+			return nil, err
+		}
+		m.ID = l.MediaID
+		l.Media = &m
+		links = append(links, l)
+	}
+	rows.Close()
+
+	for i := range links {
+		links[i].Media.Variants, err = r.getVariants(links[i].MediaID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+func (r *mediaRepository) getVariants(mediaID int) ([]entity.MediaVariant, error) {
+	query := `SELECT variant, storage_key, mime_type, size_bytes, width, height
+	FROM media_variants WHERE media_id = $1 ORDER BY width DESC`
+	rows, err := r.db.Query(context.Background(), query, mediaID)
+	if err != nil {
+		r.Logger.Error("Error query get media variants: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []entity.MediaVariant
+	for rows.Next() {
+		var v entity.MediaVariant
+		err := rows.Scan(&v.Variant, &v.StorageKey, &v.MimeType, &v.Size, &v.Width, &v.Height)
+		if err != nil {
+			r.Logger.Error("Error scan media variant: ", zap.Error(err))
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
@@ -15,6 +15,7 @@ import (
 
 type MovieRepository interface{
 	GetAll(q dto.MovieQuery) ([]entity.Movie, int, error)
+	Search(q dto.MovieSearchQuery) ([]entity.Movie, int, error)
 	GetByID(id int) (*dto.MovieResponse, error)
 	Create(data dto.MovieRequest) (int, error)
 	Update(id int, data dto.MovieRequest) error
@@ -48,9 +49,8 @@ func (r *movieRepository) GetAll(q dto.MovieQuery) ([]entity.Movie, int, error)
 		EXISTS (
 			SELECT 1
 			FROM genre_movies gm
-			JOIN genres g ON g.id = gm.genre_id
 			WHERE gm.movie_id = m.id
-				AND g.name = $1
+				AND gm.genre_id IN (` + genreSubtree("name = $1") + `)
 		)
 	)`
 	err := r.db.QueryRow(context.Background(), countQuery, q.Genre).Scan(&total)
@@ -65,7 +65,7 @@ func (r *movieRepository) GetAll(q dto.MovieQuery) ([]entity.Movie, int, error)
 	// Conditional query based on page, limit, and all param
 	query := `SELECT m.id, title, synopsis, poster_url, 
 	trailer_url, duration_minute, release_date, language, 
-	rating_age, ARRAY_AGG(g.name) AS genres, m.created_at, m.updated_at
+	rating_age, ` + movieRating + `, ARRAY_AGG(g.name) AS genres, m.created_at, m.updated_at
 	FROM movies m 
 	LEFT JOIN genre_movies gm ON gm.movie_id = m.id
 	LEFT JOIN genres g ON g.id = gm.genre_id 
@@ -75,9 +75,8 @@ func (r *movieRepository) GetAll(q dto.MovieQuery) ([]entity.Movie, int, error)
 		EXISTS (
 			SELECT 1
 			FROM genre_movies gm2
-			JOIN genres g2 ON g2.id = gm2.genre_id
 			WHERE gm2.movie_id = m.id
-				AND g2.name = $1
+				AND gm2.genre_id IN (` + genreSubtree("name = $1") + `)
 		)
 	)
 	GROUP BY m.id
@@ -102,7 +101,7 @@ func (r *movieRepository) GetAll(q dto.MovieQuery) ([]entity.Movie, int, error)
 		var m entity.Movie
 		err := rows.Scan(&m.ID, &m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL,
 			&m.Duration, &m.ReleaseDate, &m.Language, &m.RatingAge,
-			&m.Genres, &m.CreatedAt, &m.UpdatedAt)
+			&m.RatingAverage, &m.RatingCount, &m.Genres, &m.CreatedAt, &m.UpdatedAt)
 		if err != nil {
 			r.Logger.Error("Error scan movie: ", zap.Error(err))
 			return nil, 0, err
@@ -116,7 +115,7 @@ func (r *movieRepository) GetByID(id int) (*dto.MovieResponse, error) {
 	var m dto.MovieResponse
 	query := `SELECT m.id, title, synopsis, poster_url, 
 	trailer_url, duration_minute, release_date, language, 
-	rating_age, ARRAY_AGG(g.name) AS genres
+	rating_age, ` + movieRating + `, ARRAY_AGG(g.name) AS genres
 	FROM movies m 
 	LEFT JOIN genre_movies gm ON gm.movie_id = m.id
 	LEFT JOIN genres g ON g.id = gm.genre_id 
@@ -127,7 +126,7 @@ func (r *movieRepository) GetByID(id int) (*dto.MovieResponse, error) {
 	err := r.db.QueryRow(context.Background(), query, id).Scan(&m.MovieID, 
 		&m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL,
 		&m.Duration, &m.ReleaseDate, &m.Language,
-		&m.RatingAge, &m.Genres)
+		&m.RatingAge, &m.RatingAverage, &m.RatingCount, &m.Genres)
 
 	if err == pgx.ErrNoRows {
 		r.Logger.Error("Error not found movie: ", zap.Error(err))
@@ -289,4 +288,183 @@ func (r *movieRepository) Delete(id int) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+// Sort options for movie search, values are trusted SQL
+var movieSearchSorts = map[string]string{
+	"relevance":    "relevance",
+	"release_date": "m.release_date",
+	"title":        "LOWER(m.title)",
+	"popularity":   "popularity",
+	"rating":       "rating_average",
+}
+
+func (r *movieRepository) Search(q dto.MovieSearchQuery) ([]entity.Movie, int, error) {
+	var offset int
+	offset = (q.Page - 1) * q.Limit
+
+	// Dates are given in local cinema time
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	var releasedFrom, releasedTo, dayStart, dayEnd *time.Time
+	if q.ReleasedFrom != "" {
+		t, err := time.ParseInLocation("02-01-2006", q.ReleasedFrom, loc)
+		if err != nil {
+			r.Logger.Error("Invalid date format: ", zap.Error(err))
+			return nil, 0, err
+		}
+		releasedFrom = &t
+	}
+	if q.ReleasedTo != "" {
+		t, err := time.ParseInLocation("02-01-2006", q.ReleasedTo, loc)
+		if err != nil {
+			r.Logger.Error("Invalid date format: ", zap.Error(err))
+			return nil, 0, err
+		}
+		t = t.AddDate(0, 0, 1)
+		releasedTo = &t
+	}
+	if q.Date != "" {
+		t, err := time.ParseInLocation("02-01-2006", q.Date, loc)
+		if err != nil {
+			r.Logger.Error("Invalid date format: ", zap.Error(err))
+			return nil, 0, err
+		}
+		end := t.AddDate(0, 0, 1)
+		dayStart, dayEnd = &t, &end
+	} else if q.CinemaID != 0 {
+		// Cinema without a date means any upcoming screening
+		now := time.Now()
+		dayStart = &now
+	}
+
+	args := []any{
+		nullString(q.Keyword), nullString(q.Genre), nullString(q.Language), nullString(q.RatingAge),
+		releasedFrom, releasedTo, nullInt(q.MinDuration), nullInt(q.MaxDuration),
+		nullInt(q.CinemaID), dayStart, dayEnd,
+	}
+
+	where := `
+	WHERE m.deleted_at IS NULL
+	AND (
+		$1::text IS NULL
+		OR m.search_vector @@ websearch_to_tsquery('simple', $1)
+		OR m.title % $1
+		OR $1 <% m.title
+	)
+	AND (
+		$2::text IS NULL OR
+		EXISTS (
+			SELECT 1
+			FROM genre_movies gm2
+			WHERE gm2.movie_id = m.id
+				AND gm2.genre_id IN (` + genreSubtree("name ILIKE $2") + `)
+		)
+	)
+	AND ($3::text IS NULL OR m.language ILIKE $3)
+	AND ($4::text IS NULL OR m.rating_age = $4)
+	AND ($5::timestamptz IS NULL OR m.release_date >= $5)
+	AND ($6::timestamptz IS NULL OR m.release_date < $6)
+	AND ($7::int IS NULL OR m.duration_minute >= $7)
+	AND ($8::int IS NULL OR m.duration_minute <= $8)
+	AND (
+		($9::int IS NULL AND $10::timestamptz IS NULL) OR
+		EXISTS (
+			SELECT 1
+			FROM screenings s
+			JOIN studios st ON st.id = s.studio_id
+			WHERE s.movie_id = m.id
+				AND s.deleted_at IS NULL
+				AND ($9::int IS NULL OR st.cinema_id = $9)
+				AND ($10::timestamptz IS NULL OR s.start_time >= $10)
+				AND ($11::timestamptz IS NULL OR s.start_time < $11)
+		)
+	)`
+
+	// Get total data for pagination
+	var total int
+	countQuery := `SELECT COUNT(*) FROM movies m` + where
+	err := r.db.QueryRow(context.Background(), countQuery, args...).Scan(&total)
+	if err != nil {
+		r.Logger.Error("Error query count search movies: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	// Whitelisted ordering, relevance only makes sense with a keyword
+	sort := q.Sort
+	if sort == "" {
+		sort = "relevance"
+	}
+	if sort == "relevance" && q.Keyword == "" {
+		sort = "release_date"
+	}
+	orderBy, ok := movieSearchSorts[sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid sort %q", q.Sort)
+	}
+	direction := "DESC"
+	if q.Order == "asc" || (q.Order == "" && sort == "title") {
+		direction = "ASC"
+	}
+
+	query := `SELECT m.id, m.title, m.synopsis, m.poster_url,
+	m.trailer_url, m.duration_minute, m.release_date, m.language,
+	m.rating_age, ` + movieRating + `, ARRAY_AGG(g.name) AS genres, m.created_at, m.updated_at,
+	CASE WHEN $1::text IS NULL THEN 0
+		ELSE ts_rank(m.search_vector, websearch_to_tsquery('simple', $1)) + similarity(m.title, $1)
+	END AS relevance,
+	COALESCE(p.popularity, 0) AS popularity
+	FROM movies m
+	LEFT JOIN genre_movies gm ON gm.movie_id = m.id
+	LEFT JOIN genres g ON g.id = gm.genre_id
+	LEFT JOIN LATERAL (
+		SELECT COUNT(*) AS popularity
+		FROM booking_seats bs
+		JOIN screenings s ON s.id = bs.screening_id
+		WHERE s.movie_id = m.id
+			AND bs.booking_status = 'paid'
+			AND bs.created_at > NOW() - interval '30 day'
+	) p ON true` + where + `
+	GROUP BY m.id, p.popularity
+	ORDER BY ` + orderBy + ` ` + direction + ` NULLS LAST, m.id ASC`
+
+	if !q.All && q.Limit > 0 {
+		query += ` LIMIT $12 OFFSET $13`
+		args = append(args, q.Limit, offset)
+	}
+
+	rows, err := r.db.Query(context.Background(), query, args...)
+	if err != nil {
+		r.Logger.Error("Error query search movies: ", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var movies []entity.Movie
+	for rows.Next() {
+		var m entity.Movie
+		var relevance float64
+		var popularity int
+		err := rows.Scan(&m.ID, &m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL,
+			&m.Duration, &m.ReleaseDate, &m.Language, &m.RatingAge,
+			&m.RatingAverage, &m.RatingCount, &m.Genres, &m.CreatedAt, &m.UpdatedAt, &relevance, &popularity)
+		if err != nil {
+			r.Logger.Error("Error scan movie: ", zap.Error(err))
+			return nil, 0, err
+		}
+		movies = append(movies, m)
+	}
+	return movies, total, nil
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func nullInt(i int) *int {
+	if i == 0 {
+		return nil
+	}
+	return &i
+}
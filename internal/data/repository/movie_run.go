@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Run status derived from dates in cinema local time, expects movie_runs aliased as r
+const movieRunStatus = `CASE
+	WHEN r.end_date IS NOT NULL AND (NOW() AT TIME ZONE 'Asia/Jakarta')::date > r.end_date THEN 'ended'
+	WHEN (NOW() AT TIME ZONE 'Asia/Jakarta')::date >= r.start_date THEN 'now_showing'
+	WHEN r.presale_start_at IS NOT NULL AND NOW() >= r.presale_start_at THEN 'pre_sale'
+	ELSE 'announced'
+END`
+
+type MovieRunRepository interface {
+	Create(run entity.MovieRun) (int, error)
+	Update(id int, run entity.MovieRun) error
+	Delete(id int) error
+	GetByID(id int) (*entity.MovieRun, error)
+	GetByMovie(movieID int) ([]entity.MovieRun, error)
+	NowShowing(cinemaID int) ([]entity.Movie, error)
+	ComingSoon(cinemaID int) ([]entity.MovieRun, error)
+}
+
+type movieRunRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewMovieRunRepository(db database.PgxIface, log *zap.Logger) MovieRunRepository {
+	return &movieRunRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *movieRunRepository) Create(run entity.MovieRun) (int, error) {
+	var id int
+	query := `INSERT INTO movie_runs (movie_id, cinema_id, presale_start_at, start_date, end_date, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	RETURNING id`
+	err := r.db.QueryRow(context.Background(), query, run.MovieID, run.CinemaID, run.PresaleStartAt,
+		run.StartDate, run.EndDate).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create movie run: ", zap.Error(err))
+		if isUniqueViolation(err) {
+			return 0, errors.New("movie already has a run in this cinema")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *movieRunRepository) Update(id int, run entity.MovieRun) error {
+	query := `UPDATE movie_runs
+	SET movie_id = $1, cinema_id = $2, presale_start_at = $3, start_date = $4, end_date = $5, updated_at = NOW()
+	WHERE id = $6 AND deleted_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, run.MovieID, run.CinemaID, run.PresaleStartAt,
+		run.StartDate, run.EndDate, id)
+	if err != nil {
+		r.Logger.Error("Error query update movie run: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("movie run")
+	}
+	return nil
+}
+
+func (r *movieRunRepository) Delete(id int) error {
+	query := `UPDATE movie_runs SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query delete movie run: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("movie run")
+	}
+	return nil
+}
+
+func (r *movieRunRepository) GetByID(id int) (*entity.MovieRun, error) {
+	var run entity.MovieRun
+	query := `SELECT r.id, r.movie_id, r.cinema_id, c.name, r.presale_start_at, r.start_date, r.end_date,
+	` + movieRunStatus + `, r.created_at, r.updated_at
+	FROM movie_runs r
+	JOIN cinemas c ON c.id = r.cinema_id
+	WHERE r.id = $1 AND r.deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&run.ID, &run.MovieID, &run.CinemaID, &run.CinemaName,
+		&run.PresaleStartAt, &run.StartDate, &run.EndDate, &run.Status, &run.CreatedAt, &run.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found movie run: ", zap.Error(err))
+		return nil, utils.ErrNotFound("movie run")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get movie run by id: ", zap.Error(err))
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *movieRunRepository) GetByMovie(movieID int) ([]entity.MovieRun, error) {
+	query := `SELECT r.id, r.movie_id, r.cinema_id, c.name, r.presale_start_at, r.start_date, r.end_date,
+	` + movieRunStatus + `, r.created_at, r.updated_at
+	FROM movie_runs r
+	JOIN cinemas c ON c.id = r.cinema_id
+	WHERE r.movie_id = $1 AND r.deleted_at IS NULL
+	ORDER BY r.start_date ASC, c.name ASC`
+	rows, err := r.db.Query(context.Background(), query, movieID)
+	if err != nil {
+		r.Logger.Error("Error query get movie runs: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []entity.MovieRun
+	for rows.Next() {
+		var run entity.MovieRun
+		err := rows.Scan(&run.ID, &run.MovieID, &run.CinemaID, &run.CinemaName, &run.PresaleStartAt,
+			&run.StartDate, &run.EndDate, &run.Status, &run.CreatedAt, &run.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan movie run: ", zap.Error(err))
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+func (r *movieRunRepository) NowShowing(cinemaID int) ([]entity.Movie, error) {
+	// A movie is showing when it has upcoming screenings in a cinema where its
+	// run is now showing, or where no run was configured for it
+	query := `SELECT m.id, m.title, m.synopsis, m.poster_url,
+	m.trailer_url, m.duration_minute, m.release_date, m.language,
+	m.rating_age, ` + movieRating + `, ARRAY_AGG(g.name) AS genres, m.created_at, m.updated_at
+	FROM movies m
+	LEFT JOIN genre_movies gm ON gm.movie_id = m.id
+	LEFT JOIN genres g ON g.id = gm.genre_id
+	WHERE m.deleted_at IS NULL
+	AND EXISTS (
+		SELECT 1
+		FROM screenings s
+		JOIN studios st ON st.id = s.studio_id
+		LEFT JOIN movie_runs r ON r.movie_id = s.movie_id AND r.cinema_id = st.cinema_id AND r.deleted_at IS NULL
+		WHERE s.movie_id = m.id
+			AND s.deleted_at IS NULL
+			AND s.start_time + (m.duration_minute * INTERVAL '1 minute') > NOW()
+			AND ($1::int IS NULL OR st.cinema_id = $1)
+			AND (r.id IS NULL OR ` + movieRunStatus + ` = 'now_showing')
+	)
+	GROUP BY m.id
+	ORDER BY m.release_date DESC, m.id ASC`
+	rows, err := r.db.Query(context.Background(), query, nullInt(cinemaID))
+	if err != nil {
+		r.Logger.Error("Error query get now showing movies: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movies []entity.Movie
+	for rows.Next() {
+		var m entity.Movie
+		err := rows.Scan(&m.ID, &m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL,
+			&m.Duration, &m.ReleaseDate, &m.Language, &m.RatingAge,
+			&m.RatingAverage, &m.RatingCount, &m.Genres, &m.CreatedAt, &m.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan movie: ", zap.Error(err))
+			return nil, err
+		}
+		movies = append(movies, m)
+	}
+	return movies, nil
+}
+
+func (r *movieRunRepository) ComingSoon(cinemaID int) ([]entity.MovieRun, error) {
+	// One row per movie with its earliest opening across the selected cinemas
+	query := `WITH upcoming AS (
+		SELECT r.movie_id, r.presale_start_at, r.start_date, ` + movieRunStatus + ` AS status
+		FROM movie_runs r
+		WHERE r.deleted_at IS NULL
+			AND ($1::int IS NULL OR r.cinema_id = $1)
+	)
+	SELECT m.id, m.title, m.synopsis, m.poster_url,
+	m.trailer_url, m.duration_minute, m.release_date, m.language,
+	m.rating_age, ` + movieRating + `,
+	ARRAY(
+		SELECT g.name FROM genre_movies gm JOIN genres g ON g.id = gm.genre_id WHERE gm.movie_id = m.id
+	) AS genres,
+	MIN(u.presale_start_at) AS presale_start_at,
+	MIN(u.start_date) AS start_date,
+	CASE WHEN BOOL_OR(u.status = 'pre_sale') THEN 'pre_sale' ELSE 'announced' END AS status
+	FROM upcoming u
+	JOIN movies m ON m.id = u.movie_id
+	WHERE m.deleted_at IS NULL
+		AND u.status IN ('announced', 'pre_sale')
+	GROUP BY m.id
+	ORDER BY MIN(u.start_date) ASC, m.id ASC`
+	rows, err := r.db.Query(context.Background(), query, nullInt(cinemaID))
+	if err != nil {
+		r.Logger.Error("Error query get coming soon movies: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []entity.MovieRun
+	for rows.Next() {
+		var m entity.Movie
+		var run entity.MovieRun
+		err := rows.Scan(&m.ID, &m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL,
+			&m.Duration, &m.ReleaseDate, &m.Language, &m.RatingAge, &m.RatingAverage, &m.RatingCount, &m.Genres,
+			&run.PresaleStartAt, &run.StartDate, &run.Status)
+		if err != nil {
+			r.Logger.Error("Error scan coming soon movie: ", zap.Error(err))
+			return nil, err
+		}
+		run.MovieID = m.ID
+		run.Movie = &m
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// checkPresale rejects bookings for a screening whose movie run has not
+// opened for sale yet or has already ended in that cinema.
+func checkPresale(tx pgx.Tx, screeningID int) error {
+	var opensAt time.Time
+	var endDate *time.Time
+	var screeningDate time.Time
+	query := `SELECT COALESCE(r.presale_start_at, r.start_date::timestamp AT TIME ZONE 'Asia/Jakarta'),
+	r.end_date,
+	(s.start_time AT TIME ZONE 'Asia/Jakarta')::date
+	FROM screenings s
+	JOIN studios st ON st.id = s.studio_id
+	JOIN movie_runs r ON r.movie_id = s.movie_id AND r.cinema_id = st.cinema_id AND r.deleted_at IS NULL
+	WHERE s.id = $1`
+	err := tx.QueryRow(context.Background(), query, screeningID).Scan(&opensAt, &endDate, &screeningDate)
+	if err == pgx.ErrNoRows {
+		// No run configured, screenings are bookable as before
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if time.Now().Before(opensAt) {
+		loc, _ := time.LoadLocation("Asia/Jakarta")
+		return errors.New("booking opens on " + opensAt.In(loc).Format("02-01-2006 15.04"))
+	}
+	if endDate != nil && screeningDate.After(*endDate) {
+		return errors.New("movie run has ended in this cinema")
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/pkg/database"
@@ -12,6 +13,9 @@ type OTPRepository interface {
 	Create(o entity.OTP) error
 	Find(otp string) (*string, error)
 	Update(otp string) error
+	CreateForUser(o entity.OTP) error
+	FindForUser(userID int, email string) (*entity.OTP, error)
+	UseForUser(id int, userID int) error
 }
 
 type otpRepository struct {
@@ -45,7 +49,9 @@ func (r *otpRepository) Find(email string) (*string, error) {
 	query := `
 		SELECT otp_hash
 		FROM otp_codes
-		WHERE email = $1 AND expired_at > NOW()
+		WHERE email = $1 AND user_id IS NULL AND used_at IS NULL AND expired_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
 	`
 	var otp string
 	err := r.db.QueryRow(context.Background(), query, email).Scan(
@@ -63,13 +69,76 @@ func (r *otpRepository) Update(otp string) error {
 	query := `
 		UPDATE otp_codes
 		SET used_at = NOW()
-		WHERE otp_hash = $1
+		WHERE otp_hash = $1 AND used_at IS NULL
 	`
-	_, err := r.db.Exec(context.Background(), query, otp)
+	result, err := r.db.Exec(context.Background(), query, otp)
 	if err != nil {
 		r.Logger.Error("Error query create otp: ", zap.Error(err))
 		return err
 	}
 
+	// A concurrent request already used the code
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid OTP")
+	}
+
+	return nil
+}
+
+func (r *otpRepository) CreateForUser(o entity.OTP) error {
+	query := `
+		INSERT INTO otp_codes (user_id, email, otp_hash, expired_at, created_at)
+		VALUES ($1, $2, $3, NOW() + interval '5 minute', NOW())
+	`
+	_, err := r.db.Exec(context.Background(), query, o.UserID, o.Email, o.OTPHash)
+	if err != nil {
+		r.Logger.Error("Error query create user otp: ", zap.Error(err))
+		return err
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// FindForUser returns the latest unused code the user requested for the email
+func (r *otpRepository) FindForUser(userID int, email string) (*entity.OTP, error) {
+	query := `
+		SELECT id, user_id, email, otp_hash, expired_at
+		FROM otp_codes
+		WHERE user_id = $1 AND email = $2 AND used_at IS NULL AND expired_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var o entity.OTP
+	err := r.db.QueryRow(context.Background(), query, userID, email).Scan(
+		&o.ID,
+		&o.UserID,
+		&o.Email,
+		&o.OTPHash,
+		&o.ExpiredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// UseForUser marks the code used only if it still belongs to the user and is unused
+func (r *otpRepository) UseForUser(id int, userID int) error {
+	query := `
+		UPDATE otp_codes
+		SET used_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND used_at IS NULL AND expired_at > NOW()
+	`
+	result, err := r.db.Exec(context.Background(), query, id, userID)
+	if err != nil {
+		r.Logger.Error("Error query use user otp: ", zap.Error(err))
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return errors.New("invalid OTP")
+	}
+
+	return nil
+}
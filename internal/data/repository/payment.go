@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/database"
@@ -15,6 +16,8 @@ type PaymentRepository interface{
 	GetPaymentMethod() ([]entity.PaymentMethod, error)
 	Create(b dto.PaymentRequest) (*int, error)
 	GetPaymentByID(id int) (*entity.Payment, error)
+	GetSettledByBooking(bookingID int) (*entity.Payment, error)
+	GetSettledHistory(bookingID int) ([]entity.Payment, error)
 	Update(p dto.UpdatePayment) (*int, error)
 }
 
@@ -31,7 +34,8 @@ func NewPaymentRepository(db database.PgxIface, log *zap.Logger) PaymentReposito
 }
 
 func (r *paymentRepository) GetPaymentMethod() ([]entity.PaymentMethod, error) {
-	query := `SELECT id, name FROM payment_methods`
+	// Cash and EDC are only taken at the box office
+	query := `SELECT id, name FROM payment_methods WHERE kind = 'online'`
 	rows, err := r.db.Query(context.Background(), query)
 	if err != nil {
 		r.Logger.Error("Error query get payment method: ", zap.Error(err))
@@ -82,11 +86,27 @@ func (r *paymentRepository) Create(p dto.PaymentRequest) (*int, error) {
 		return nil, errors.New("booking is already cancelled")
 	}
 
-	// Idempotency: reuse existing pending payment
+	var online bool
+	query = `SELECT EXISTS (SELECT 1 FROM payment_methods WHERE id = $1 AND kind = 'online')`
+	err = tx.QueryRow(context.Background(), query, p.PaymentMethod).Scan(&online)
+	if err != nil {
+		r.Logger.Error("Error query get payment method: ", zap.Error(err))
+		return nil, err
+	}
+	if !online {
+		err = errors.New("payment method is not available online")
+		return nil, err
+	}
+
+	// Idempotency: reuse existing pending payment or seat change charge,
+	// pending refunds are paid out to the customer and never reused
 	var existingPaymentID int
 	query = `SELECT id
 		FROM payments
-		WHERE booking_id = $1 AND status = 'pending'`
+		WHERE booking_id = $1 AND status = 'pending' AND type IN ('payment', 'charge')
+		ORDER BY id DESC
+		LIMIT 1
+		FOR UPDATE`
 	err = tx.QueryRow(context.Background(), query, p.BookingID).Scan(&existingPaymentID)
 
 	if err == nil {
@@ -129,6 +149,69 @@ func (r *paymentRepository) GetPaymentByID(id int) (*entity.Payment, error) {
 	return &payment, nil
 }
 
+// GetSettledByBooking returns the settled payment for a booking, or for the
+// booking it was exchanged from when the exchange needed no payment
+func (r *paymentRepository) GetSettledByBooking(bookingID int) (*entity.Payment, error) {
+	query := `WITH RECURSIVE chain AS (
+		SELECT id, exchanged_from_id FROM bookings WHERE id = $1
+		UNION ALL
+		SELECT b.id, b.exchanged_from_id FROM bookings b JOIN chain c ON b.id = c.exchanged_from_id
+	)
+	SELECT p.id, p.booking_id, COALESCE(pm.name, ''), p.amount::text, p.status, p.type, p.transaction_id, p.created_at, p.updated_at
+	FROM payments p
+	JOIN chain c ON c.id = p.booking_id
+	LEFT JOIN payment_methods pm ON pm.id = p.payment_method_id
+	WHERE p.type = 'payment' AND p.status IN ('success', 'settlement')
+	ORDER BY p.booking_id = $1 DESC, p.id DESC
+	LIMIT 1`
+	var payment entity.Payment
+	err := r.db.QueryRow(context.Background(), query, bookingID).Scan(&payment.ID, &payment.BookingID, &payment.PaymentMethod,
+		&payment.Amount, &payment.Status, &payment.Type, &payment.TransactionID, &payment.CreatedAt, &payment.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("payment")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get settled payment: ", zap.Error(err))
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetSettledHistory returns every settled payment, charge and refund of a
+// booking and of the bookings it was exchanged from, oldest first
+func (r *paymentRepository) GetSettledHistory(bookingID int) ([]entity.Payment, error) {
+	query := `WITH RECURSIVE chain AS (
+		SELECT id, exchanged_from_id FROM bookings WHERE id = $1
+		UNION ALL
+		SELECT b.id, b.exchanged_from_id FROM bookings b JOIN chain c ON b.id = c.exchanged_from_id
+	)
+	SELECT p.id, p.booking_id, COALESCE(pm.name, ''), p.amount::text, p.status, p.type, p.transaction_id, p.created_at, p.updated_at
+	FROM payments p
+	JOIN chain c ON c.id = p.booking_id
+	LEFT JOIN payment_methods pm ON pm.id = p.payment_method_id
+	WHERE p.status IN ('success', 'settlement')
+	ORDER BY p.updated_at ASC, p.id ASC`
+	rows, err := r.db.Query(context.Background(), query, bookingID)
+	if err != nil {
+		r.Logger.Error("Error query get settled payment history: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []entity.Payment
+	for rows.Next() {
+		var payment entity.Payment
+		err := rows.Scan(&payment.ID, &payment.BookingID, &payment.PaymentMethod, &payment.Amount, &payment.Status,
+			&payment.Type, &payment.TransactionID, &payment.CreatedAt, &payment.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan settled payment: ", zap.Error(err))
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, nil
+}
+
 func (r *paymentRepository) Update(p dto.UpdatePayment) (*int, error) {
 	// Handle db transaction
 	tx, err := r.db.Begin(context.Background())
@@ -143,8 +226,8 @@ func (r *paymentRepository) Update(p dto.UpdatePayment) (*int, error) {
 
 	// Check payment current status
 	var payment entity.Payment
-	query :=`SELECT booking_id, status FROM payments WHERE id = $1 FOR UPDATE`
-	err = tx.QueryRow(context.Background(), query, p.PaymentID).Scan(&payment.BookingID, &payment.Status)
+	query :=`SELECT booking_id, status, type FROM payments WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, p.PaymentID).Scan(&payment.BookingID, &payment.Status, &payment.Type)
 	if err != nil {
 		r.Logger.Error("Error query get payment by id: ", zap.Error(err))
 		return nil, err
@@ -166,7 +249,16 @@ func (r *paymentRepository) Update(p dto.UpdatePayment) (*int, error) {
 		return nil, err
 	}
 
-	if p.Status == "success" {
+	// A settled refund only returns money, it never pays for the booking
+	if p.Status == "success" && payment.Type != "refund" {
+		var bookingStatus string
+		query = `SELECT status FROM bookings WHERE id = $1 FOR UPDATE`
+		err = tx.QueryRow(context.Background(), query, payment.BookingID).Scan(&bookingStatus)
+		if err != nil {
+			r.Logger.Error("Error query get booking: ", zap.Error(err))
+			return nil, err
+		}
+
 		// Update booking
 		query = `UPDATE bookings SET status = 'paid' WHERE id = $1`
 		_, err = tx.Exec(context.Background(), query, payment.BookingID)
@@ -175,50 +267,35 @@ func (r *paymentRepository) Update(p dto.UpdatePayment) (*int, error) {
 			return nil, err
 		}
 
-		// Update booking_seats
-		query = `UPDATE booking_seats SET booking_status = 'paid' WHERE booking_id = $1`
+		// Paying for an exchange retires the booking it replaces
+		if bookingStatus == "pending" {
+			err = retireExchangedBooking(tx, payment.BookingID)
+			if err != nil {
+				r.Logger.Error("Error query retire exchanged booking: ", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		// Update booking_seats, seats given up in a swap stay cancelled
+		query = `UPDATE booking_seats SET booking_status = 'paid' WHERE booking_id = $1 AND booking_status = 'pending'`
 		_, err = tx.Exec(context.Background(), query, payment.BookingID)
 		if err != nil {
 			r.Logger.Error("Error query update booking_seats: ", zap.Error(err))
 			return nil, err
 		}
 
-		// Get seats
-		query = `SELECT seat_id FROM booking_seats WHERE booking_id = $1`
-		rows, err := tx.Query(context.Background(), query, payment.BookingID)
+		// Issue tickets for seats still without one, a charge for extra seats only adds theirs
+		var seats []int
+		seats, err = untickedSeats(tx, payment.BookingID)
 		if err != nil {
 			r.Logger.Error("Error query get seats: ", zap.Error(err))
 			return nil, err
 		}
-		defer rows.Close()
-
-		var seats []int
-		for rows.Next() {
-			var seatID int
-			err := rows.Scan(&seatID)
-			if err != nil {
-				r.Logger.Error("Error scan seats: ", zap.Error(err))
-				return nil, err
-			}
-
-			seats = append(seats, seatID)
-		}
-
-		for _, seatID := range seats {
-			// Generate ticket
-			qrToken, err := utils.GenerateRandomToken(16)
-			if err != nil {
-				r.Logger.Error("Error generate random token: ", zap.Error(err))
-				return nil, err
-			}
 
-			query = `INSERT INTO tickets (booking_id, seat_id, qr_token, created_at)
-			VALUES ($1, $2, $3, NOW())`
-			_, err = tx.Exec(context.Background(), query, payment.BookingID, seatID, qrToken)
-			if err != nil {
-				r.Logger.Error("Error query create ticket: ", zap.Error(err))
-				return nil, err
-			}
+		err = issueTickets(tx, payment.BookingID, seats)
+		if err != nil {
+			r.Logger.Error("Error query create ticket: ", zap.Error(err))
+			return nil, err
 		}
 
 		if err := tx.Commit(context.Background()); err != nil {
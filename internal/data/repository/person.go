@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Crew first in a fixed order, then cast by billing order
+const creditOrder = `array_position(ARRAY['director', 'writer', 'producer', 'cast', 'composer', 'cinematographer', 'editor']::varchar[], mc.role), mc.billing_order, mc.id`
+
+type PersonRepository interface {
+	GetAll(q dto.PaginationQuery, name string) ([]entity.Person, int, error)
+	GetByID(id int) (*entity.Person, error)
+	Create(p entity.Person) (int, error)
+	Update(id int, p entity.Person) error
+	Delete(id int) error
+	GetCredits(movieID int) ([]entity.MovieCredit, error)
+	ReplaceCredits(movieID int, credits []entity.MovieCredit) error
+	GetMovies(personID int) ([]entity.MovieCredit, error)
+}
+
+type personRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewPersonRepository(db database.PgxIface, log *zap.Logger) PersonRepository {
+	return &personRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *personRepository) GetAll(q dto.PaginationQuery, name string) ([]entity.Person, int, error) {
+	offset := (q.Page - 1) * q.Limit
+
+	// Get total data for pagination
+	var total int
+	countQuery := `SELECT COUNT(*) FROM people
+	WHERE deleted_at IS NULL AND ($1::text IS NULL OR name ILIKE '%' || $1 || '%')`
+	err := r.db.QueryRow(context.Background(), countQuery, nullString(name)).Scan(&total)
+	if err != nil {
+		r.Logger.Error("Error query count people: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `SELECT id, name, biography, photo_url, birth_date, created_at, updated_at
+	FROM people
+	WHERE deleted_at IS NULL AND ($1::text IS NULL OR name ILIKE '%' || $1 || '%')
+	ORDER BY name ASC, id ASC`
+
+	var rows pgx.Rows
+	if !q.All && q.Limit > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+		rows, err = r.db.Query(context.Background(), query, nullString(name), q.Limit, offset)
+	} else {
+		rows, err = r.db.Query(context.Background(), query, nullString(name))
+	}
+	if err != nil {
+		r.Logger.Error("Error query get all people: ", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var people []entity.Person
+	for rows.Next() {
+		var p entity.Person
+		err := rows.Scan(&p.ID, &p.Name, &p.Biography, &p.PhotoURL, &p.BirthDate, &p.CreatedAt, &p.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan person: ", zap.Error(err))
+			return nil, 0, err
+		}
+		people = append(people, p)
+	}
+	return people, total, nil
+}
+
+func (r *personRepository) GetByID(id int) (*entity.Person, error) {
+	var p entity.Person
+	query := `SELECT id, name, biography, photo_url, birth_date, created_at, updated_at
+	FROM people WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&p.ID, &p.Name, &p.Biography, &p.PhotoURL,
+		&p.BirthDate, &p.CreatedAt, &p.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found person: ", zap.Error(err))
+		return nil, utils.ErrNotFound("person")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get person by id: ", zap.Error(err))
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *personRepository) Create(p entity.Person) (int, error) {
+	var id int
+	query := `INSERT INTO people (name, biography, photo_url, birth_date, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, NOW(), NOW())
+	RETURNING id`
+	err := r.db.QueryRow(context.Background(), query, p.Name, p.Biography, p.PhotoURL, p.BirthDate).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create person: ", zap.Error(err))
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *personRepository) Update(id int, p entity.Person) error {
+	query := `UPDATE people
+	SET name = $1, biography = $2, photo_url = $3, birth_date = $4, updated_at = NOW()
+	WHERE id = $5 AND deleted_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, p.Name, p.Biography, p.PhotoURL, p.BirthDate, id)
+	if err != nil {
+		r.Logger.Error("Error query update person: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("person")
+	}
+	return nil
+}
+
+func (r *personRepository) Delete(id int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	result, err := tx.Exec(context.Background(), `UPDATE people SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete person: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		err = utils.ErrNotFound("person")
+		return err
+	}
+
+	// Deleted people no longer appear in credits
+	_, err = tx.Exec(context.Background(), `DELETE FROM movie_credits WHERE person_id = $1`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete person credits: ", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *personRepository) GetCredits(movieID int) ([]entity.MovieCredit, error) {
+	query := `SELECT mc.id, mc.movie_id, mc.person_id, p.name, p.photo_url, mc.role, mc.character_name, mc.billing_order
+	FROM movie_credits mc
+	JOIN people p ON p.id = mc.person_id AND p.deleted_at IS NULL
+	WHERE mc.movie_id = $1
+	ORDER BY ` + creditOrder
+	rows, err := r.db.Query(context.Background(), query, movieID)
+	if err != nil {
+		r.Logger.Error("Error query get movie credits: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []entity.MovieCredit
+	for rows.Next() {
+		var c entity.MovieCredit
+		err := rows.Scan(&c.ID, &c.MovieID, &c.PersonID, &c.PersonName, &c.PhotoURL, &c.Role,
+			&c.CharacterName, &c.Order)
+		if err != nil {
+			r.Logger.Error("Error scan movie credit: ", zap.Error(err))
+			return nil, err
+		}
+		credits = append(credits, c)
+	}
+	return credits, nil
+}
+
+func (r *personRepository) ReplaceCredits(movieID int, credits []entity.MovieCredit) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Lock the movie so concurrent edits apply one after another
+	var id int
+	err = tx.QueryRow(context.Background(), `SELECT id FROM movies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, movieID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		err = utils.ErrNotFound("movie")
+		return err
+	}
+	if err != nil {
+		r.Logger.Error("Error query lock movie: ", zap.Error(err))
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM movie_credits WHERE movie_id = $1`, movieID)
+	if err != nil {
+		r.Logger.Error("Error query delete movie credits: ", zap.Error(err))
+		return err
+	}
+
+	query := `INSERT INTO movie_credits (movie_id, person_id, role, character_name, billing_order, created_at, updated_at)
+	SELECT $1, p.id, $3, $4, $5, NOW(), NOW()
+	FROM people p
+	WHERE p.id = $2 AND p.deleted_at IS NULL`
+	for _, c := range credits {
+		var result pgconn.CommandTag
+		result, err = tx.Exec(context.Background(), query, movieID, c.PersonID, c.Role, c.CharacterName, c.Order)
+		if err != nil {
+			r.Logger.Error("Error query create movie credit: ", zap.Error(err))
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			err = utils.ErrNotFound("person")
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *personRepository) GetMovies(personID int) ([]entity.MovieCredit, error) {
+	query := `SELECT mc.id, mc.movie_id, mc.person_id, mc.role, mc.character_name, mc.billing_order,
+	m.id, m.title, m.synopsis, m.poster_url, m.trailer_url, m.duration_minute, m.release_date, m.language, m.rating_age,
+	` + movieRating + `,
+	ARRAY(
+		SELECT g.name FROM genre_movies gm JOIN genres g ON g.id = gm.genre_id WHERE gm.movie_id = m.id
+	) AS genres
+	FROM movie_credits mc
+	JOIN movies m ON m.id = mc.movie_id AND m.deleted_at IS NULL
+	WHERE mc.person_id = $1
+	ORDER BY m.release_date DESC, m.id ASC, ` + creditOrder
+	rows, err := r.db.Query(context.Background(), query, personID)
+	if err != nil {
+		r.Logger.Error("Error query get person movies: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var credits []entity.MovieCredit
+	for rows.Next() {
+		var c entity.MovieCredit
+		var m entity.Movie
+		err := rows.Scan(&c.ID, &c.MovieID, &c.PersonID, &c.Role, &c.CharacterName, &c.Order,
+			&m.ID, &m.Title, &m.Synopsis, &m.PosterURL, &m.TrailerURL, &m.Duration, &m.ReleaseDate,
+			&m.Language, &m.RatingAge, &m.RatingAverage, &m.RatingCount, &m.Genres)
+		if err != nil {
+			r.Logger.Error("Error scan person movie: ", zap.Error(err))
+			return nil, err
+		}
+		c.Movie = &m
+		credits = append(credits, c)
+	}
+	return credits, nil
+}
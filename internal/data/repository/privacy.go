@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type PrivacyRepository interface {
+	GetExportData(userID int) (*dto.DataExport, error)
+	CreateExport(userID int) (*entity.DataExport, error)
+	SetExportFile(id int, filePath string) error
+	UpdateExportStatus(id int, status string, errMsg *string) error
+	GetExport(id int, userID int) (*entity.DataExport, error)
+	GetExportsByUser(userID int) ([]entity.DataExport, error)
+	Erase(userID int) ([]string, error)
+}
+
+type privacyRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewPrivacyRepository(db database.PgxIface, log *zap.Logger) PrivacyRepository {
+	return &privacyRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *privacyRepository) GetExportData(userID int) (*dto.DataExport, error) {
+	data := dto.DataExport{
+		GeneratedAt: time.Now(),
+		Bookings:    []dto.ExportBooking{},
+		Payments:    []dto.ExportPayment{},
+		Tickets:     []dto.ExportTicket{},
+		Reviews:     []dto.ExportReview{},
+	}
+
+	// Profile
+	query := `SELECT id, name, email, role, created_at FROM users WHERE id = $1 AND deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(&data.Profile.UserID,
+		&data.Profile.Name, &data.Profile.Email, &data.Profile.Role, &data.Profile.CreatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found user: ", zap.Error(err))
+		return nil, utils.ErrNotFound("user")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get export profile: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Bookings
+	query = `SELECT b.id, m.title, c.name, st.name, sc.start_time,
+	ARRAY_REMOVE(ARRAY_AGG(s.seat_code), NULL) AS seats, b.status, b.created_at
+	FROM bookings b
+	LEFT JOIN screenings sc ON sc.id = b.screening_id
+	LEFT JOIN studios st ON st.id = sc.studio_id
+	LEFT JOIN cinemas c ON c.id = st.cinema_id
+	LEFT JOIN movies m ON m.id = sc.movie_id
+	LEFT JOIN booking_seats bs ON bs.booking_id = b.id
+	LEFT JOIN seats s ON s.id = bs.seat_id
+	WHERE b.user_id = $1
+	GROUP BY b.id, m.title, c.name, st.name, sc.start_time, b.status, b.created_at
+	ORDER BY b.created_at ASC`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get export bookings: ", zap.Error(err))
+		return nil, err
+	}
+	for rows.Next() {
+		var b dto.ExportBooking
+		err = rows.Scan(&b.BookingID, &b.MovieTitle, &b.Cinema, &b.Studio, &b.StartTime, &b.Seats, &b.Status, &b.CreatedAt)
+		if err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan export booking: ", zap.Error(err))
+			return nil, err
+		}
+		data.Bookings = append(data.Bookings, b)
+	}
+	rows.Close()
+
+	// Payments
+	query = `SELECT p.id, p.booking_id, COALESCE(pm.name, ''), p.amount, p.status, p.transaction_id, p.created_at
+	FROM payments p
+	JOIN bookings b ON b.id = p.booking_id
+	LEFT JOIN payment_methods pm ON pm.id = p.payment_method_id
+	WHERE b.user_id = $1
+	ORDER BY p.created_at ASC`
+	rows, err = r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get export payments: ", zap.Error(err))
+		return nil, err
+	}
+	for rows.Next() {
+		var p dto.ExportPayment
+		err = rows.Scan(&p.PaymentID, &p.BookingID, &p.PaymentMethod, &p.Amount, &p.Status, &p.TransactionID, &p.CreatedAt)
+		if err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan export payment: ", zap.Error(err))
+			return nil, err
+		}
+		data.Payments = append(data.Payments, p)
+	}
+	rows.Close()
+
+	// Tickets
+	query = `SELECT t.id, t.booking_id, s.seat_code, t.qr_token, t.issued_at, t.created_at
+	FROM tickets t
+	JOIN bookings b ON b.id = t.booking_id
+	LEFT JOIN seats s ON s.id = t.seat_id
+	WHERE b.user_id = $1 OR t.holder_user_id = $1
+	ORDER BY t.created_at ASC`
+	rows, err = r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get export tickets: ", zap.Error(err))
+		return nil, err
+	}
+	for rows.Next() {
+		var t dto.ExportTicket
+		err = rows.Scan(&t.TicketID, &t.BookingID, &t.SeatCode, &t.QRToken, &t.IssuedAt, &t.CreatedAt)
+		if err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan export ticket: ", zap.Error(err))
+			return nil, err
+		}
+		data.Tickets = append(data.Tickets, t)
+	}
+	rows.Close()
+
+	// Reviews
+	query = `SELECT rv.id, m.title, rv.rating, rv.content, rv.status, rv.created_at
+	FROM reviews rv
+	JOIN movies m ON m.id = rv.movie_id
+	WHERE rv.user_id = $1 AND rv.deleted_at IS NULL
+	ORDER BY rv.created_at ASC`
+	rows, err = r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get export reviews: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var rv dto.ExportReview
+		err = rows.Scan(&rv.ReviewID, &rv.MovieTitle, &rv.Rating, &rv.Content, &rv.Status, &rv.CreatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan export review: ", zap.Error(err))
+			return nil, err
+		}
+		data.Reviews = append(data.Reviews, rv)
+	}
+
+	return &data, nil
+}
+
+func (r *privacyRepository) CreateExport(userID int) (*entity.DataExport, error) {
+	var e entity.DataExport
+	query := `INSERT INTO data_exports (user_id, status, created_at, updated_at)
+	VALUES ($1, 'pending', NOW(), NOW())
+	RETURNING id, user_id, status, created_at, updated_at`
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(&e.ID, &e.UserID, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		r.Logger.Error("Error query create data export: ", zap.Error(err))
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *privacyRepository) SetExportFile(id int, filePath string) error {
+	query := `UPDATE data_exports SET file_path = $1, status = 'processing', updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Exec(context.Background(), query, filePath, id)
+	if err != nil {
+		r.Logger.Error("Error query set data export file: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *privacyRepository) UpdateExportStatus(id int, status string, errMsg *string) error {
+	// Ready archives can be downloaded for 7 days
+	query := `UPDATE data_exports
+	SET status = $1,
+	error = $2,
+	completed_at = NOW(),
+	expired_at = CASE WHEN $1 = 'ready' THEN NOW() + interval '7 day' ELSE NULL END,
+	updated_at = NOW()
+	WHERE id = $3`
+	_, err := r.db.Exec(context.Background(), query, status, errMsg, id)
+	if err != nil {
+		r.Logger.Error("Error query update data export status: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *privacyRepository) GetExport(id int, userID int) (*entity.DataExport, error) {
+	var e entity.DataExport
+	query := `SELECT id, user_id, status, file_path, error, expired_at, completed_at, created_at, updated_at
+	FROM data_exports WHERE id = $1 AND user_id = $2`
+	err := r.db.QueryRow(context.Background(), query, id, userID).Scan(&e.ID, &e.UserID, &e.Status,
+		&e.FilePath, &e.Error, &e.ExpiredAt, &e.CompletedAt, &e.CreatedAt, &e.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found data export: ", zap.Error(err))
+		return nil, utils.ErrNotFound("data export")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get data export: ", zap.Error(err))
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *privacyRepository) GetExportsByUser(userID int) ([]entity.DataExport, error) {
+	query := `SELECT id, user_id, status, file_path, error, expired_at, completed_at, created_at, updated_at
+	FROM data_exports WHERE user_id = $1
+	ORDER BY created_at DESC`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get data exports: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []entity.DataExport
+	for rows.Next() {
+		var e entity.DataExport
+		err := rows.Scan(&e.ID, &e.UserID, &e.Status, &e.FilePath, &e.Error,
+			&e.ExpiredAt, &e.CompletedAt, &e.CreatedAt, &e.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan data export: ", zap.Error(err))
+			return nil, err
+		}
+		exports = append(exports, e)
+	}
+	return exports, nil
+}
+
+func (r *privacyRepository) Erase(userID int) ([]string, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Lock user
+	var email string
+	query := `SELECT email FROM users WHERE id = $1 AND erased_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, userID).Scan(&email)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found user: ", zap.Error(err))
+		return nil, utils.ErrNotFound("user")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get user for erasure: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Anonymise personal fields, bookings and payments stay linked to the same id
+	query = `UPDATE users
+	SET name = 'Deleted User',
+	email = $1,
+	password = '!',
+	deleted_at = COALESCE(deleted_at, NOW()),
+	erased_at = NOW(),
+	updated_at = NOW()
+	WHERE id = $2`
+	_, err = tx.Exec(context.Background(), query, fmt.Sprintf("erased-%d@erased.invalid", userID), userID)
+	if err != nil {
+		r.Logger.Error("Error query anonymise user: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Remove credentials and verification codes
+	_, err = tx.Exec(context.Background(), `DELETE FROM sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete sessions: ", zap.Error(err))
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM otp_codes WHERE email = $1 OR user_id = $2`, email, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete otp codes: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Partner keys stop authenticating, the rows stay for the audit trail
+	_, err = tx.Exec(context.Background(), `UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	if err != nil {
+		r.Logger.Error("Error query revoke api keys: ", zap.Error(err))
+		return nil, err
+	}
+
+	for _, table := range []string{"two_factor_challenges", "user_recovery_codes", "user_totp", "user_identities"} {
+		_, err = tx.Exec(context.Background(), fmt.Sprintf(`DELETE FROM %s WHERE user_id = $1`, table), userID)
+		if err != nil {
+			r.Logger.Error("Error query delete login credentials: ", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Remove reviews and take approved ones out of the movie ratings
+	query = `UPDATE movies m
+	SET rating_sum = m.rating_sum - x.rating_sum, rating_count = m.rating_count - x.rating_count
+	FROM (
+		SELECT movie_id, SUM(rating) AS rating_sum, COUNT(*) AS rating_count
+		FROM reviews
+		WHERE user_id = $1 AND status = 'approved' AND deleted_at IS NULL
+		GROUP BY movie_id
+	) x
+	WHERE m.id = x.movie_id`
+	_, err = tx.Exec(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query adjust movie ratings: ", zap.Error(err))
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM reviews WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete reviews: ", zap.Error(err))
+		return nil, err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM waitlist_entries WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete waitlist entries: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Open ticket transfers can no longer be answered
+	query = `UPDATE ticket_transfers SET status = 'cancelled', updated_at = NOW()
+	WHERE status = 'pending' AND (from_user_id = $1 OR to_user_id = $1)`
+	_, err = tx.Exec(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query cancel ticket transfers: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Remove export archives, files are deleted by the caller after commit
+	rows, err := tx.Query(context.Background(), `DELETE FROM data_exports WHERE user_id = $1 RETURNING file_path`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete data exports: ", zap.Error(err))
+		return nil, err
+	}
+	var files []string
+	for rows.Next() {
+		var path *string
+		if err = rows.Scan(&path); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan data export file: ", zap.Error(err))
+			return nil, err
+		}
+		if path != nil {
+			files = append(files, *path)
+		}
+	}
+	rows.Close()
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
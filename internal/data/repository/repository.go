@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/project-app-bioskop-golang/pkg/database"
 	"go.uber.org/zap"
 )
@@ -18,6 +21,19 @@ type Repository struct {
 	PaymentRepo PaymentRepository
 	OTPRepo OTPRepository
 	TicketRepo TicketRepository
+	PrivacyRepo PrivacyRepository
+	TwoFactorRepo TwoFactorRepository
+	APIKeyRepo APIKeyRepository
+	IdentityRepo IdentityRepository
+	MovieRunRepo MovieRunRepository
+	PersonRepo PersonRepository
+	ReviewRepo ReviewRepository
+	MediaRepo MediaRepository
+	CatalogRepo CatalogRepository
+	TranslationRepo TranslationRepository
+	WaitlistRepo WaitlistRepository
+	TransferRepo TransferRepository
+	BoxOfficeRepo BoxOfficeRepository
 }
 
 func NewRepository(db database.PgxIface, log *zap.Logger) Repository {
@@ -34,5 +50,24 @@ func NewRepository(db database.PgxIface, log *zap.Logger) Repository {
 		PaymentRepo: NewPaymentRepository(db, log),
 		OTPRepo: NewOTPRepository(db, log),
 		TicketRepo: NewTicketRepository(db, log),
+		PrivacyRepo: NewPrivacyRepository(db, log),
+		TwoFactorRepo: NewTwoFactorRepository(db, log),
+		APIKeyRepo: NewAPIKeyRepository(db, log),
+		IdentityRepo: NewIdentityRepository(db, log),
+		MovieRunRepo: NewMovieRunRepository(db, log),
+		PersonRepo: NewPersonRepository(db, log),
+		ReviewRepo: NewReviewRepository(db, log),
+		MediaRepo: NewMediaRepository(db, log),
+		CatalogRepo: NewCatalogRepository(db, log),
+		TranslationRepo: NewTranslationRepository(db, log),
+		WaitlistRepo: NewWaitlistRepository(db, log),
+		TransferRepo: NewTransferRepository(db, log),
+		BoxOfficeRepo: NewBoxOfficeRepository(db, log),
 	}
-}
\ No newline at end of file
+}
+
+// isUniqueViolation reports whether the insert hit a unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
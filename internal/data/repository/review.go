@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Average and count of approved reviews, expects movies aliased as m
+const movieRating = `COALESCE(ROUND(m.rating_sum::numeric / NULLIF(m.rating_count, 0), 1), 0)::float8 AS rating_average, m.rating_count`
+
+const reviewColumns = `rv.id, rv.movie_id, m.title, rv.user_id, u.name, rv.rating, rv.content, rv.status,
+	rv.moderation_note, rv.moderated_by, rv.moderated_at, rv.created_at, rv.updated_at`
+
+type ReviewRepository interface {
+	IsVerifiedViewer(userID int, movieID int) (bool, error)
+	Create(rv entity.Review) (int, error)
+	GetByID(id int) (*entity.Review, error)
+	GetByMovie(movieID int, q dto.PaginationQuery) ([]entity.Review, int, error)
+	GetByUser(userID int) ([]entity.Review, error)
+	GetAll(q dto.PaginationQuery, status string) ([]entity.Review, int, error)
+	Update(id int, userID int, rating int, content string) error
+	Moderate(id int, status string, note *string, adminID int) error
+	Delete(id int, userID int) error
+}
+
+type reviewRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewReviewRepository(db database.PgxIface, log *zap.Logger) ReviewRepository {
+	return &reviewRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *reviewRepository) IsVerifiedViewer(userID int, movieID int) (bool, error) {
+	// A paid booking for a screening of the movie that has already started
+	var verified bool
+	query := `SELECT EXISTS (
+		SELECT 1
+		FROM bookings b
+		JOIN screenings s ON s.id = b.screening_id
+		WHERE b.user_id = $1
+			AND s.movie_id = $2
+			AND b.status = 'paid'
+			AND b.deleted_at IS NULL
+			AND s.start_time <= NOW()
+	)`
+	err := r.db.QueryRow(context.Background(), query, userID, movieID).Scan(&verified)
+	if err != nil {
+		r.Logger.Error("Error query check verified viewer: ", zap.Error(err))
+		return false, err
+	}
+	return verified, nil
+}
+
+func (r *reviewRepository) Create(rv entity.Review) (int, error) {
+	var id int
+	query := `INSERT INTO reviews (movie_id, user_id, rating, content, status, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, 'pending', NOW(), NOW())
+	RETURNING id`
+	err := r.db.QueryRow(context.Background(), query, rv.MovieID, rv.UserID, rv.Rating, rv.Content).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create review: ", zap.Error(err))
+		if isUniqueViolation(err) {
+			return 0, errors.New("you have already reviewed this movie")
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *reviewRepository) GetByID(id int) (*entity.Review, error) {
+	query := `SELECT ` + reviewColumns + `
+	FROM reviews rv
+	JOIN movies m ON m.id = rv.movie_id
+	JOIN users u ON u.id = rv.user_id
+	WHERE rv.id = $1 AND rv.deleted_at IS NULL`
+	rv, err := scanReview(r.db.QueryRow(context.Background(), query, id))
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found review: ", zap.Error(err))
+		return nil, utils.ErrNotFound("review")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get review by id: ", zap.Error(err))
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (r *reviewRepository) GetByMovie(movieID int, q dto.PaginationQuery) ([]entity.Review, int, error) {
+	offset := (q.Page - 1) * q.Limit
+
+	// Get total data for pagination
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reviews WHERE movie_id = $1 AND status = 'approved' AND deleted_at IS NULL`
+	err := r.db.QueryRow(context.Background(), countQuery, movieID).Scan(&total)
+	if err != nil {
+		r.Logger.Error("Error query count movie reviews: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + reviewColumns + `
+	FROM reviews rv
+	JOIN movies m ON m.id = rv.movie_id
+	JOIN users u ON u.id = rv.user_id
+	WHERE rv.movie_id = $1 AND rv.status = 'approved' AND rv.deleted_at IS NULL
+	ORDER BY rv.created_at DESC, rv.id DESC`
+
+	var rows pgx.Rows
+	if !q.All && q.Limit > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+		rows, err = r.db.Query(context.Background(), query, movieID, q.Limit, offset)
+	} else {
+		rows, err = r.db.Query(context.Background(), query, movieID)
+	}
+	if err != nil {
+		r.Logger.Error("Error query get movie reviews: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	reviews, err := r.scanReviews(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *reviewRepository) GetByUser(userID int) ([]entity.Review, error) {
+	query := `SELECT ` + reviewColumns + `
+	FROM reviews rv
+	JOIN movies m ON m.id = rv.movie_id
+	JOIN users u ON u.id = rv.user_id
+	WHERE rv.user_id = $1 AND rv.deleted_at IS NULL
+	ORDER BY rv.created_at DESC, rv.id DESC`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get user reviews: ", zap.Error(err))
+		return nil, err
+	}
+	return r.scanReviews(rows)
+}
+
+func (r *reviewRepository) GetAll(q dto.PaginationQuery, status string) ([]entity.Review, int, error) {
+	offset := (q.Page - 1) * q.Limit
+
+	// Get total data for pagination
+	var total int
+	countQuery := `SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL AND ($1::text IS NULL OR status = $1)`
+	err := r.db.QueryRow(context.Background(), countQuery, nullString(status)).Scan(&total)
+	if err != nil {
+		r.Logger.Error("Error query count reviews: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	// Oldest first so the moderation queue is worked in order
+	query := `SELECT ` + reviewColumns + `
+	FROM reviews rv
+	JOIN movies m ON m.id = rv.movie_id
+	JOIN users u ON u.id = rv.user_id
+	WHERE rv.deleted_at IS NULL AND ($1::text IS NULL OR rv.status = $1)
+	ORDER BY rv.created_at ASC, rv.id ASC`
+
+	var rows pgx.Rows
+	if !q.All && q.Limit > 0 {
+		query += ` LIMIT $2 OFFSET $3`
+		rows, err = r.db.Query(context.Background(), query, nullString(status), q.Limit, offset)
+	} else {
+		rows, err = r.db.Query(context.Background(), query, nullString(status))
+	}
+	if err != nil {
+		r.Logger.Error("Error query get reviews: ", zap.Error(err))
+		return nil, 0, err
+	}
+
+	reviews, err := r.scanReviews(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *reviewRepository) Update(id int, userID int, rating int, content string) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	current, err := lockReview(tx, id)
+	if err != nil {
+		return err
+	}
+	if current.UserID != userID {
+		err = utils.ErrNotFound("review")
+		return err
+	}
+
+	// Edited reviews go back to the moderation queue
+	query := `UPDATE reviews
+	SET rating = $1, content = $2, status = 'pending', moderation_note = NULL,
+	moderated_by = NULL, moderated_at = NULL, updated_at = NOW()
+	WHERE id = $3`
+	_, err = tx.Exec(context.Background(), query, rating, content, id)
+	if err != nil {
+		r.Logger.Error("Error query update review: ", zap.Error(err))
+		return err
+	}
+
+	if current.Status == "approved" {
+		err = adjustRating(tx, current.MovieID, -current.Rating, -1)
+		if err != nil {
+			r.Logger.Error("Error query adjust movie rating: ", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *reviewRepository) Moderate(id int, status string, note *string, adminID int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	current, err := lockReview(tx, id)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE reviews
+	SET status = $1, moderation_note = $2, moderated_by = $3, moderated_at = NOW(), updated_at = NOW()
+	WHERE id = $4`
+	_, err = tx.Exec(context.Background(), query, status, note, adminID, id)
+	if err != nil {
+		r.Logger.Error("Error query moderate review: ", zap.Error(err))
+		return err
+	}
+
+	// Only approved reviews count towards the movie rating
+	switch {
+	case current.Status != "approved" && status == "approved":
+		err = adjustRating(tx, current.MovieID, current.Rating, 1)
+	case current.Status == "approved" && status != "approved":
+		err = adjustRating(tx, current.MovieID, -current.Rating, -1)
+	}
+	if err != nil {
+		r.Logger.Error("Error query adjust movie rating: ", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *reviewRepository) Delete(id int, userID int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		r.Logger.Error("Error start db transaction: ", zap.Error(err))
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	current, err := lockReview(tx, id)
+	if err != nil {
+		return err
+	}
+
+	// Zero user id is an admin removing any review
+	if userID != 0 && current.UserID != userID {
+		err = utils.ErrNotFound("review")
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `UPDATE reviews SET deleted_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		r.Logger.Error("Error query delete review: ", zap.Error(err))
+		return err
+	}
+
+	if current.Status == "approved" {
+		err = adjustRating(tx, current.MovieID, -current.Rating, -1)
+		if err != nil {
+			r.Logger.Error("Error query adjust movie rating: ", zap.Error(err))
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *reviewRepository) scanReviews(rows pgx.Rows) ([]entity.Review, error) {
+	defer rows.Close()
+
+	var reviews []entity.Review
+	for rows.Next() {
+		rv, err := scanReview(rows)
+		if err != nil {
+			r.Logger.Error("Error scan review: ", zap.Error(err))
+			return nil, err
+		}
+		reviews = append(reviews, *rv)
+	}
+	return reviews, nil
+}
+
+func scanReview(row pgx.Row) (*entity.Review, error) {
+	var rv entity.Review
+	err := row.Scan(&rv.ID, &rv.MovieID, &rv.MovieTitle, &rv.UserID, &rv.UserName, &rv.Rating, &rv.Content,
+		&rv.Status, &rv.ModerationNote, &rv.ModeratedBy, &rv.ModeratedAt, &rv.CreatedAt, &rv.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// lockReview reads the fields that drive the rating totals and holds the row
+// until the transaction ends.
+func lockReview(tx pgx.Tx, id int) (*entity.Review, error) {
+	var rv entity.Review
+	query := `SELECT id, movie_id, user_id, rating, status FROM reviews WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err := tx.QueryRow(context.Background(), query, id).Scan(&rv.ID, &rv.MovieID, &rv.UserID, &rv.Rating, &rv.Status)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("review")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// adjustRating applies a change to the running rating totals of a movie.
+func adjustRating(tx pgx.Tx, movieID int, sum int, count int) error {
+	query := `UPDATE movies SET rating_sum = rating_sum + $1, rating_count = rating_count + $2 WHERE id = $3`
+	_, err := tx.Exec(context.Background(), query, sum, count, movieID)
+	return err
+}
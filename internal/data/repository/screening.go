@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,12 +16,44 @@ import (
 
 type ScreeningRepository interface{
 	GetByCinema(q dto.ScreeningQuery) ([]entity.Screening, int, error)
+	SearchShowtimes(q dto.ScreeningQuery, from time.Time, to time.Time) ([]entity.Showtime, error)
 	GetByID(id int) (*entity.Screening, error)
 	Create(s dto.ScreeningRequest) error
 	Update(id int, data dto.UpdateScreeningRequest) error
 	Delete(id int) error
+	GetFormats() ([]entity.ScreeningFormat, error)
+	UpdateFormat(code string, req dto.ScreeningFormatRequest) (*entity.ScreeningFormat, error)
 }
 
+// Attribute filters shared by the screening list queries, parameters $4 to $8
+const screeningFilter = `
+		AND ($4::text IS NULL OR s.format = $4)
+		AND ($5::text IS NULL OR LOWER(COALESCE(s.audio_language, m.language)) = LOWER($5))
+		AND ($6::text IS NULL OR LOWER(s.subtitle_language) = LOWER($6))
+		AND (NOT $7::boolean OR s.audio_description)
+		AND (NOT $8::boolean OR s.closed_captions)`
+
+// A booked seat stays held while paid or while its pending booking has not
+// expired, needs booking_seats bs and bookings b
+const seatHeld = `(bs.booking_status = 'paid' OR (bs.booking_status = 'pending' AND b.expired_at > NOW()))`
+
+// Attribute columns, price and seat counts of a screening, needs studios st
+// and screening_formats f. Seats offered to the waitlist count as taken.
+const screeningColumns = `
+		s.format,
+		s.audio_language,
+		s.subtitle_language,
+		s.audio_description,
+		s.closed_captions,
+		f.surcharge,
+		st.price + f.surcharge AS price,
+		(SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.deleted_at IS NULL) AS seats_total,
+		(SELECT COUNT(DISTINCT bs.seat_id)
+			FROM booking_seats bs
+			JOIN bookings b ON b.id = bs.booking_id
+			WHERE bs.screening_id = s.id AND ` + seatHeld + `
+		) + ` + offeredSeats + ` AS seats_taken`
+
 type screeningRepository struct {
 	db     database.PgxIface
 	Logger *zap.Logger
@@ -55,12 +88,13 @@ func (r *screeningRepository) GetByCinema(q dto.ScreeningQuery) ([]entity.Screen
 	WHERE m.deleted_at IS NULL AND st.cinema_id = $1
 		AND s.start_time >= $2
 		AND s.start_time < $3
-		AND s.deleted_at IS NULL
+		AND s.deleted_at IS NULL` + screeningFilter + `
 	GROUP BY s.movie_id, m.title, m.poster_url,
 		m.duration_minute,
 		m.rating_age, st.type, st.price, s.id
 	`
-	err = r.db.QueryRow(context.Background(), countQuery, q.CinemaID, selectedDate, selectedDate.AddDate(0,0,1)).Scan(&total)
+	err = r.db.QueryRow(context.Background(), countQuery, q.CinemaID, selectedDate, selectedDate.AddDate(0,0,1),
+		nullString(q.Format), nullString(q.AudioLanguage), nullString(q.SubtitleLanguage), q.AudioDescription, q.ClosedCaptions).Scan(&total)
 	if err != nil {
 		r.Logger.Error("Error query count screenings: ", zap.Error(err))
 		return nil, 0, err
@@ -76,18 +110,20 @@ func (r *screeningRepository) GetByCinema(q dto.ScreeningQuery) ([]entity.Screen
 		s.studio_id,
 		s.movie_id,
 		s.start_time,
-		s.start_time + (m.duration_minute * INTERVAL '1 minute') AS end_time
+		s.start_time + (m.duration_minute * INTERVAL '1 minute') AS end_time,` + screeningColumns + `
 	FROM screenings s
 	JOIN movies m ON m.id = s.movie_id
+	JOIN screening_formats f ON f.code = s.format
 	LEFT JOIN studios st ON s.studio_id = st.id
 	LEFT JOIN cinemas c ON st.cinema_id = c.id
 	WHERE s.start_time >= $1
 		AND s.start_time < $2
 		AND s.deleted_at IS NULL
-		AND c.id = $3
+		AND c.id = $3` + screeningFilter + `
 	ORDER BY s.start_time ASC
 	`
-	rows, err = r.db.Query(context.Background(), query, selectedDate, selectedDate.AddDate(0,0,1), q.CinemaID)
+	rows, err = r.db.Query(context.Background(), query, selectedDate, selectedDate.AddDate(0,0,1), q.CinemaID,
+		nullString(q.Format), nullString(q.AudioLanguage), nullString(q.SubtitleLanguage), q.AudioDescription, q.ClosedCaptions)
 	if err != nil {
 		r.Logger.Error("Error query get all screenings: ", zap.Error(err))
 		return nil, 0, err
@@ -99,8 +135,12 @@ func (r *screeningRepository) GetByCinema(q dto.ScreeningQuery) ([]entity.Screen
     var s entity.Screening
 		var startTime time.Time
 		var endTime time.Time
+		var taken int
 
-    rows.Scan(&s.ID, &s.StudioID, &s.MovieID, &startTime, &endTime)
+    rows.Scan(&s.ID, &s.StudioID, &s.MovieID, &startTime, &endTime,
+			&s.Format, &s.AudioLanguage, &s.SubtitleLanguage, &s.AudioDescription, &s.ClosedCaptions, &s.Surcharge, &s.Price,
+			&s.SeatsTotal, &taken)
+		s.SeatsAvailable = max(s.SeatsTotal-taken, 0)
 
 		// Convert to WIB
 		loc, _ := time.LoadLocation("Asia/Jakarta")
@@ -113,23 +153,106 @@ func (r *screeningRepository) GetByCinema(q dto.ScreeningQuery) ([]entity.Screen
 	return screenings, total, nil
 }
 
+// SearchShowtimes lists upcoming screenings of a movie in every cinema,
+// starting from from up to but excluding to
+func (r *screeningRepository) SearchShowtimes(q dto.ScreeningQuery, from time.Time, to time.Time) ([]entity.Showtime, error) {
+	// Radius 0 means no limit
+	var radius *float64
+	if q.RadiusKm > 0 {
+		radius = &q.RadiusKm
+	}
+
+	query := `
+	SELECT * FROM (
+		SELECT
+			s.id,
+			s.studio_id,
+			s.movie_id,
+			s.start_time,
+			s.start_time + (m.duration_minute * INTERVAL '1 minute') AS end_time,` + screeningColumns + `,
+			st.name AS studio_name,
+			c.id AS cinema_id,
+			c.name AS cinema_name,
+			c.location,
+			c.address,
+			c.city,
+			c.region,
+			c.latitude,
+			c.longitude,
+			` + cinemaAmenities("c") + ` AS amenities,
+			CASE WHEN $10::float8 IS NULL OR c.latitude IS NULL THEN NULL
+				ELSE ` + cinemaDistance("$10", "$11") + `
+			END AS distance
+		FROM screenings s
+		JOIN movies m ON m.id = s.movie_id
+		JOIN screening_formats f ON f.code = s.format
+		JOIN studios st ON st.id = s.studio_id AND st.deleted_at IS NULL
+		JOIN cinemas c ON c.id = st.cinema_id AND c.deleted_at IS NULL
+		WHERE s.movie_id = $1
+			AND s.start_time >= GREATEST($2, NOW())
+			AND s.start_time < $3
+			AND s.deleted_at IS NULL` + screeningFilter + `
+			AND ($9::text IS NULL OR LOWER(c.city) = LOWER($9))
+	) x
+	WHERE ($12::float8 IS NULL OR distance <= $12)
+	ORDER BY distance ASC NULLS LAST, cinema_name ASC, cinema_id ASC, format ASC, start_time ASC
+	`
+	rows, err := r.db.Query(context.Background(), query, q.MovieID, from, to,
+		nullString(q.Format), nullString(q.AudioLanguage), nullString(q.SubtitleLanguage), q.AudioDescription, q.ClosedCaptions,
+		nullString(q.City), q.Latitude, q.Longitude, radius)
+	if err != nil {
+		r.Logger.Error("Error query search showtimes: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	var showtimes []entity.Showtime
+	for rows.Next() {
+		var s entity.Showtime
+		var taken int
+		err := rows.Scan(&s.ID, &s.StudioID, &s.MovieID, &s.StartTime, &s.EndTime,
+			&s.Format, &s.AudioLanguage, &s.SubtitleLanguage, &s.AudioDescription, &s.ClosedCaptions, &s.Surcharge, &s.Price,
+			&s.SeatsTotal, &taken,
+			&s.StudioName, &s.Cinema.ID, &s.Cinema.Name, &s.Cinema.Location, &s.Cinema.Address, &s.Cinema.City, &s.Cinema.Region,
+			&s.Cinema.Latitude, &s.Cinema.Longitude, &s.Cinema.Amenities, &s.DistanceKm)
+		if err != nil {
+			r.Logger.Error("Error scan showtime: ", zap.Error(err))
+			return nil, err
+		}
+
+		// Convert to WIB
+		s.StartTime = s.StartTime.In(loc)
+		s.EndTime = s.EndTime.In(loc)
+		s.SeatsAvailable = max(s.SeatsTotal-taken, 0)
+		showtimes = append(showtimes, s)
+	}
+
+	return showtimes, nil
+}
+
 func (r *screeningRepository) GetByID(id int) (*entity.Screening, error) {
 	var s entity.Screening
 	var startTime time.Time
 	var endTime time.Time
+	var taken int
 	query := `SELECT 
 		s.id,
 		s.studio_id,
 		s.movie_id,
 		s.start_time,
-		s.start_time + (m.duration_minute * INTERVAL '1 minute') AS end_time
+		s.start_time + (m.duration_minute * INTERVAL '1 minute') AS end_time,` + screeningColumns + `
 	FROM screenings s
 	JOIN movies m ON m.id = s.movie_id
+	JOIN screening_formats f ON f.code = s.format
 	LEFT JOIN studios st ON s.studio_id = st.id
 	LEFT JOIN cinemas c ON st.cinema_id = c.id
 	WHERE s.id = $1`
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&s.ID, &s.StudioID, &s.MovieID, &startTime, &endTime)
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&s.ID, &s.StudioID, &s.MovieID, &startTime, &endTime,
+		&s.Format, &s.AudioLanguage, &s.SubtitleLanguage, &s.AudioDescription, &s.ClosedCaptions, &s.Surcharge, &s.Price,
+		&s.SeatsTotal, &taken)
+	s.SeatsAvailable = max(s.SeatsTotal-taken, 0)
 
 	// Convert to WIB
 	loc, _ := time.LoadLocation("Asia/Jakarta")
@@ -182,7 +305,8 @@ func (r *screeningRepository) Create(s dto.ScreeningRequest) error {
 			// Convert to utc in db
 			loc, _ := time.LoadLocation("Asia/Jakarta")
 
-			wibTime, err := time.ParseInLocation(
+			var wibTime time.Time
+			wibTime, err = time.ParseInLocation(
 				"02-01-2006 15.04",
 				timeStr,
 				loc,
@@ -195,13 +319,27 @@ func (r *screeningRepository) Create(s dto.ScreeningRequest) error {
 				r.Logger.Error("Error parsing datetime: ", zap.Error(err))
 				return err
 			}
+
+			// Cinema must be open for the whole screening
+			var reason string
+			reason, err = screeningOutsideHours(tx, s.StudioID, s.MovieID, utcTime)
+			if err != nil {
+				r.Logger.Error("Error query check opening hours: ", zap.Error(err))
+				return err
+			}
+			if reason != "" {
+				err = errors.New(reason)
+				return err
+			}
 			query := `
-				INSERT INTO screenings (studio_id, movie_id, start_time, created_at, updated_at)
-				VALUES ($1, $2, $3, NOW(), NOW())
+				INSERT INTO screenings (studio_id, movie_id, start_time, format, audio_language, subtitle_language,
+					audio_description, closed_captions, created_at, updated_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 				RETURNING id
 			`
 			err = tx.QueryRow(context.Background(), query,
-				&s.StudioID, &s.MovieID, &utcTime).Scan(&screening.ID)
+				&s.StudioID, &s.MovieID, &utcTime, s.Format, s.AudioLanguage, s.SubtitleLanguage,
+				s.AudioDescription, s.ClosedCaptions).Scan(&screening.ID)
 			if err != nil {
 				r.Logger.Error("Error query create screening: ", zap.Error(err))
 				return err
@@ -224,8 +362,27 @@ func (r *screeningRepository) Update(id int, s dto.UpdateScreeningRequest) error
 		}
 	}()
 
-	// Convert string to datetime
-	startTime, err := time.Parse("2006-01-02 15:04", s.StartTime)
+	// Convert string to datetime, given in WIB like on create
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	wibTime, err := time.ParseInLocation("2006-01-02 15:04", s.StartTime, loc)
+	if err != nil {
+		r.Logger.Error("Error parsing datetime: ", zap.Error(err))
+		return err
+	}
+
+	// Convert to UTC for storage
+	startTime := wibTime.UTC()
+
+	// Cinema must be open for the whole screening
+	reason, err := screeningOutsideHours(tx, s.StudioID, s.MovieID, startTime)
+	if err != nil {
+		r.Logger.Error("Error query check opening hours: ", zap.Error(err))
+		return err
+	}
+	if reason != "" {
+		err = errors.New(reason)
+		return err
+	}
 
 	// Update screenings
 	query := `
@@ -233,12 +390,18 @@ func (r *screeningRepository) Update(id int, s dto.UpdateScreeningRequest) error
 		SET studio_id = $1,
 		movie_id = $2,
 		start_time = $3,
+		format = $4,
+		audio_language = $5,
+		subtitle_language = $6,
+		audio_description = $7,
+		closed_captions = $8,
 		updated_at = NOW()
-		WHERE id = $4 AND deleted_at IS NULL
+		WHERE id = $9 AND deleted_at IS NULL
 	`
 
 	result, err := tx.Exec(context.Background(), query,
-		&s.StudioID, &s.MovieID, &startTime, id)
+		&s.StudioID, &s.MovieID, &startTime, s.Format, s.AudioLanguage, s.SubtitleLanguage,
+		s.AudioDescription, s.ClosedCaptions, id)
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
@@ -280,4 +443,119 @@ func (r *screeningRepository) Delete(id int) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *screeningRepository) GetFormats() ([]entity.ScreeningFormat, error) {
+	query := `SELECT code, name, surcharge, created_at, updated_at
+	FROM screening_formats
+	ORDER BY surcharge ASC, code ASC`
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		r.Logger.Error("Error query get screening formats: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var formats []entity.ScreeningFormat
+	for rows.Next() {
+		var f entity.ScreeningFormat
+		err := rows.Scan(&f.Code, &f.Name, &f.Surcharge, &f.CreatedAt, &f.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan screening format: ", zap.Error(err))
+			return nil, err
+		}
+		formats = append(formats, f)
+	}
+
+	return formats, nil
+}
+
+func (r *screeningRepository) UpdateFormat(code string, req dto.ScreeningFormatRequest) (*entity.ScreeningFormat, error) {
+	var f entity.ScreeningFormat
+	query := `UPDATE screening_formats
+	SET name = $1, surcharge = $2, updated_at = NOW()
+	WHERE code = $3
+	RETURNING code, name, surcharge, created_at, updated_at`
+	err := r.db.QueryRow(context.Background(), query, req.Name, *req.Surcharge, code).Scan(&f.Code, &f.Name, &f.Surcharge, &f.CreatedAt, &f.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found screening format: ", zap.Error(err))
+		return nil, utils.ErrNotFound("screening format")
+	}
+	if err != nil {
+		r.Logger.Error("Error query update screening format: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// screeningOutsideHours explains why the cinema cannot run a screening of the
+// movie at start, or returns "" when it is open for the whole screening
+func screeningOutsideHours(tx pgx.Tx, studioID int, movieID int, start time.Time) (string, error) {
+	var cinemaID, duration int
+	query := `SELECT st.cinema_id, m.duration_minute FROM studios st, movies m WHERE st.id = $1 AND m.id = $2`
+	err := tx.QueryRow(context.Background(), query, studioID, movieID).Scan(&cinemaID, &duration)
+	if err == pgx.ErrNoRows {
+		// Unknown studio or movie is reported by the insert itself
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	localStart := start.In(loc)
+	localEnd := localStart.Add(time.Duration(duration) * time.Minute)
+
+	var closed bool
+	query = `SELECT EXISTS (SELECT 1 FROM cinema_closures WHERE cinema_id = $1 AND date = $2::date)`
+	err = tx.QueryRow(context.Background(), query, cinemaID, localStart.Format("2006-01-02")).Scan(&closed)
+	if err != nil {
+		return "", err
+	}
+	if closed {
+		return fmt.Sprintf("cinema is closed on %s", localStart.Format("02-01-2006")), nil
+	}
+
+	hours, err := getOpeningHours(tx, cinemaID)
+	if err != nil {
+		return "", err
+	}
+	if len(hours) > 0 && !withinOpeningHours(hours, localStart, localEnd) {
+		return fmt.Sprintf("screening at %s until %s is outside the cinema opening hours",
+			localStart.Format("02-01-2006 15.04"), localEnd.Format("15.04")), nil
+	}
+	return "", nil
+}
+
+// withinOpeningHours reports whether start to end fits in the opening hours of
+// its day, or of the previous day when that one closes after midnight
+func withinOpeningHours(hours []entity.OpeningHour, start time.Time, end time.Time) bool {
+	byDay := map[time.Weekday]entity.OpeningHour{}
+	for _, h := range hours {
+		byDay[time.Weekday(h.Weekday)] = h
+	}
+
+	for _, offset := range []int{0, -1} {
+		day := time.Date(start.Year(), start.Month(), start.Day()+offset, 0, 0, 0, 0, start.Location())
+		h, ok := byDay[day.Weekday()]
+		if !ok {
+			continue
+		}
+		openAt, err1 := time.Parse("15.04", h.OpenTime)
+		closeAt, err2 := time.Parse("15.04", h.CloseTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		opens := day.Add(time.Duration(openAt.Hour())*time.Hour + time.Duration(openAt.Minute())*time.Minute)
+		closes := day.Add(time.Duration(closeAt.Hour())*time.Hour + time.Duration(closeAt.Minute())*time.Minute)
+		if !closes.After(opens) {
+			closes = closes.AddDate(0, 0, 1)
+		}
+		if !start.Before(opens) && !end.After(closes) {
+			return true
+		}
+	}
+	return false
+}
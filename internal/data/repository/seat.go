@@ -27,7 +27,7 @@ func NewSeatRepository(db database.PgxIface, log *zap.Logger) SeatRepository {
 }
 
 func (r *seatRepository) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
-	query := `SELECT s.id, s.seat_code,
+	query := `SELECT s.id, s.seat_code, s.accessible,
 	CASE
 	WHEN NOT EXISTS (
 			SELECT 1
@@ -35,8 +35,7 @@ func (r *seatRepository) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
 			JOIN bookings b ON b.id = bs.booking_id
 			WHERE bs.seat_id = s.id
 				AND bs.screening_id = sc.id
-				AND b.status IN ('pending', 'paid')
-				AND b.expired_at > NOW()
+				AND ` + seatHeld + `
 	) THEN 'available'
 	ELSE 'booked'
 	END AS status
@@ -44,7 +43,8 @@ func (r *seatRepository) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
 	JOIN studios st ON st.id = s.studio_id
 	JOIN screenings sc ON sc.studio_id = st.id
 	JOIN movies m ON sc.movie_id = m.id
-	WHERE sc.id = $1 AND NOW() < sc.start_time + (m.duration_minute * INTERVAL '1 minute')
+	WHERE sc.id = $1 AND s.deleted_at IS NULL AND NOW() < sc.start_time + (m.duration_minute * INTERVAL '1 minute')
+	ORDER BY s.id
 	`
 
 	rows, err := r.db.Query(context.Background(), query, screeningID)
@@ -57,7 +57,7 @@ func (r *seatRepository) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
 	var seats []dto.SeatResponse
 	for rows.Next() {
 		var s dto.SeatResponse
-		err := rows.Scan(&s.ID, &s.SeatCode, &s.Status)
+		err := rows.Scan(&s.ID, &s.SeatCode, &s.Accessible, &s.Status)
 		if err != nil {
 			r.Logger.Error("Error query get available seats: ", zap.Error(err))
 			return nil, err
@@ -73,10 +73,13 @@ func (r *seatRepository) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
 }
 
 func (r *seatRepository) GetSeatsByBookingID(id int) ([]dto.SeatResponse, error) {
+	// Seats given up in a swap are left out, unless the whole booking was cancelled
 	query := `SELECT s.id, seat_code
 	FROM seats s
 	RIGHT JOIN booking_seats bs ON bs.seat_id = s.id
-	WHERE bs.booking_id = $1
+	JOIN bookings b ON b.id = bs.booking_id
+	WHERE bs.booking_id = $1 AND (bs.booking_status <> 'cancelled' OR b.status = 'cancelled')
+	ORDER BY s.id
 	`
 	rows, err := r.db.Query(context.Background(), query, id)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 type SessionRepository interface {
 	Create(userID int) (uuid.UUID, error)
 	Revoke(token string) error
+	RevokeByUserID(userID int, exceptToken string) error
 	ValidateToken(token string) (*int, error)
 }
 
@@ -74,4 +75,17 @@ func (r *sessionRepository) Revoke(token string) (error) {
 	}
 
 	return err
+}
+
+func (r *sessionRepository) RevokeByUserID(userID int, exceptToken string) error {
+	// Revoke every active session of a user, optionally keeping the current one
+	query := `UPDATE sessions SET revoked_at = NOW()
+	WHERE user_id = $1 AND revoked_at IS NULL AND token::text <> $2`
+	_, err := r.db.Exec(context.Background(), query, userID, exceptToken)
+	if err != nil {
+		r.Logger.Error("Error query revoke user sessions: ", zap.Error(err))
+		return err
+	}
+
+	return nil
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/project-app-bioskop-golang/internal/data/entity"
@@ -19,6 +20,7 @@ type StudioRepository interface{
 	Create(data dto.StudioRequest) (*entity.Studio, error)
 	Update(id int, data dto.StudioRequest) error
 	Delete(id int) error
+	SetAccessibleSeats(id int, seatCodes []string) error
 }
 
 type studioRepository struct {
@@ -224,4 +226,69 @@ func (r *studioRepository) Delete(id int) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *studioRepository) SetAccessibleSeats(id int, seatCodes []string) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM studios WHERE id = $1 AND deleted_at IS NULL)`
+	err = tx.QueryRow(context.Background(), query, id).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query get studio: ", zap.Error(err))
+		return err
+	}
+	if !exists {
+		err = utils.ErrNotFound("studio")
+		return err
+	}
+
+	// Every code must name a seat of this studio
+	var unknown []string
+	query = `SELECT code FROM unnest($2::text[]) AS code
+	WHERE NOT EXISTS (
+		SELECT 1 FROM seats WHERE studio_id = $1 AND seat_code = code AND deleted_at IS NULL
+	)`
+	rows, err := tx.Query(context.Background(), query, id, seatCodes)
+	if err != nil {
+		r.Logger.Error("Error query check seat codes: ", zap.Error(err))
+		return err
+	}
+	for rows.Next() {
+		var code string
+		if err = rows.Scan(&code); err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan seat code: ", zap.Error(err))
+			return err
+		}
+		unknown = append(unknown, code)
+	}
+	rows.Close()
+	if len(unknown) > 0 {
+		err = fmt.Errorf("unknown seats: %s", strings.Join(unknown, ", "))
+		return err
+	}
+
+	query = `UPDATE seats SET accessible = COALESCE(seat_code = ANY($2::text[]), false), updated_at = NOW()
+	WHERE studio_id = $1 AND deleted_at IS NULL`
+	_, err = tx.Exec(context.Background(), query, id, seatCodes)
+	if err != nil {
+		r.Logger.Error("Error query update accessible seats: ", zap.Error(err))
+		return err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,14 +2,35 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
 	"go.uber.org/zap"
 )
 
+// Columns of a ticket with its screening, needs tickets t, bookings b, seats se,
+// screenings s, movies m, studios st and cinemas c
+const ticketColumns = `t.id, t.booking_id, t.seat_id, se.seat_code, t.qr_token, t.issued_at,
+	COALESCE(b.user_id, 0), t.holder_user_id, t.checked_in_at, s.id, m.id, m.title, c.name, st.name, s.start_time, t.created_at`
+
+const ticketJoins = `FROM tickets t
+	JOIN bookings b ON b.id = t.booking_id
+	JOIN seats se ON se.id = t.seat_id
+	JOIN screenings s ON s.id = b.screening_id
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id`
+
 type TicketRepository interface{
-	GetByBookingID(id int) ([]dto.Ticket, error)
+	GetByBookingID(id int, userID int) ([]dto.Ticket, error)
+	GetByHolder(userID int) ([]entity.Ticket, error)
+	CheckIn(qrToken string) (*entity.Ticket, error)
 }
 
 type ticketRepository struct {
@@ -24,13 +45,16 @@ func NewTicketRepository(db database.PgxIface, log *zap.Logger) TicketRepository
 	}
 }
 
-func (r *ticketRepository) GetByBookingID(id int) ([]dto.Ticket, error) {
+// GetByBookingID returns the valid tickets of a booking held by the user,
+// tickets transferred to someone else are left out
+func (r *ticketRepository) GetByBookingID(id int, userID int) ([]dto.Ticket, error) {
 	query := `SELECT s.seat_code, t.qr_token
 	FROM tickets t
+	JOIN bookings b ON b.id = t.booking_id
 	LEFT JOIN seats s ON s.id = t.seat_id
-	WHERE t.booking_id = $1
+	WHERE t.booking_id = $1 AND t.revoked_at IS NULL AND COALESCE(t.holder_user_id, b.user_id) = $2
 	`
-	rows, err := r.db.Query(context.Background(), query, id)
+	rows, err := r.db.Query(context.Background(), query, id, userID)
 	if err != nil {
 		r.Logger.Error("Error query get tickets: ", zap.Error(err))
 		return nil, err
@@ -49,4 +73,147 @@ func (r *ticketRepository) GetByBookingID(id int) ([]dto.Ticket, error) {
 	}
 
 	return tickets, nil
-}
\ No newline at end of file
+}
+
+// GetByHolder lists the valid tickets a user holds, bought or received
+func (r *ticketRepository) GetByHolder(userID int) ([]entity.Ticket, error) {
+	query := `SELECT ` + ticketColumns + `
+	` + ticketJoins + `
+	WHERE t.revoked_at IS NULL AND b.status = 'paid' AND COALESCE(t.holder_user_id, b.user_id) = $1
+	ORDER BY s.start_time DESC, se.seat_code`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get held tickets: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets []entity.Ticket
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			r.Logger.Error("Error scan ticket: ", zap.Error(err))
+			return nil, err
+		}
+		tickets = append(tickets, *t)
+	}
+	return tickets, rows.Err()
+}
+
+// CheckIn admits a ticket at the door, each ticket admits once
+func (r *ticketRepository) CheckIn(qrToken string) (*entity.Ticket, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var revoked bool
+	var status string
+	query := `SELECT ` + ticketColumns + `, t.revoked_at IS NOT NULL, b.status
+	` + ticketJoins + `
+	WHERE t.qr_token = $1
+	FOR UPDATE OF t`
+	t, err := scanTicket(tx.QueryRow(context.Background(), query, qrToken), &revoked, &status)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("ticket")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get ticket: ", zap.Error(err))
+		return nil, err
+	}
+	if revoked || status != "paid" {
+		err = errors.New("ticket is no longer valid")
+		return nil, err
+	}
+	if t.CheckedInAt != nil {
+		loc, _ := time.LoadLocation("Asia/Jakarta")
+		err = fmt.Errorf("ticket was already checked in at %s", t.CheckedInAt.In(loc).Format("15.04"))
+		return nil, err
+	}
+
+	query = `UPDATE tickets SET checked_in_at = NOW() WHERE id = $1 RETURNING checked_in_at`
+	err = tx.QueryRow(context.Background(), query, t.ID).Scan(&t.CheckedInAt)
+	if err != nil {
+		r.Logger.Error("Error query check in ticket: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// scanTicket reads ticketColumns followed by any extra columns
+func scanTicket(row pgx.Row, extra ...any) (*entity.Ticket, error) {
+	var t entity.Ticket
+	dest := []any{&t.ID, &t.BookingID, &t.SeatID, &t.SeatCode, &t.QRToken, &t.IssuedAt,
+		&t.OwnerID, &t.HolderUserID, &t.CheckedInAt, &t.ScreeningID, &t.MovieID, &t.MovieTitle,
+		&t.CinemaName, &t.StudioName, &t.StartTime, &t.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// untickedSeats lists the paid seats of a booking that have no valid ticket
+func untickedSeats(tx pgx.Tx, bookingID int) ([]int, error) {
+	query := `SELECT bs.seat_id FROM booking_seats bs
+	WHERE bs.booking_id = $1 AND bs.booking_status = 'paid'
+		AND NOT EXISTS (
+			SELECT 1 FROM tickets t
+			WHERE t.booking_id = bs.booking_id AND t.seat_id = bs.seat_id AND t.revoked_at IS NULL
+		)`
+	rows, err := tx.Query(context.Background(), query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []int
+	for rows.Next() {
+		var seatID int
+		if err := rows.Scan(&seatID); err != nil {
+			return nil, err
+		}
+		seats = append(seats, seatID)
+	}
+	return seats, rows.Err()
+}
+
+// issueTickets creates a ticket with a fresh QR token for every seat
+func issueTickets(tx pgx.Tx, bookingID int, seats []int) error {
+	for _, seatID := range seats {
+		// Generate ticket
+		qrToken, err := utils.GenerateRandomToken(16)
+		if err != nil {
+			return err
+		}
+
+		query := `INSERT INTO tickets (booking_id, seat_id, qr_token, created_at)
+		VALUES ($1, $2, $3, NOW())`
+		_, err = tx.Exec(context.Background(), query, bookingID, seatID, qrToken)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelStaleTransfers withdraws pending transfers of a booking's revoked tickets
+func cancelStaleTransfers(tx pgx.Tx, bookingID int) error {
+	query := `UPDATE ticket_transfers tt
+	SET status = 'cancelled', updated_at = NOW()
+	FROM tickets t
+	WHERE t.id = tt.ticket_id AND t.booking_id = $1 AND t.revoked_at IS NOT NULL AND tt.status = 'pending'`
+	_, err := tx.Exec(context.Background(), query, bookingID)
+	return err
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const transferColumns = `tt.id, tt.ticket_id, t.booking_id, s.id, m.id, m.title, c.name, se.seat_code, s.start_time,
+	tt.from_user_id, fu.name, tt.to_user_id, tu.email, tt.status, tt.new_ticket_id, tt.created_at, tt.updated_at`
+
+const transferJoins = `FROM ticket_transfers tt
+	JOIN tickets t ON t.id = tt.ticket_id
+	JOIN bookings b ON b.id = t.booking_id
+	JOIN seats se ON se.id = t.seat_id
+	JOIN screenings s ON s.id = b.screening_id
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id
+	JOIN users fu ON fu.id = tt.from_user_id
+	JOIN users tu ON tu.id = tt.to_user_id`
+
+type TransferRepository interface {
+	Create(ticketID int, fromUserID int, toUserID int, cutoff time.Time) (int, error)
+	GetByID(id int) (*entity.TicketTransfer, error)
+	GetByUser(userID int) ([]entity.TicketTransfer, error)
+	Accept(id int, userID int, cutoff time.Time) (*entity.TicketTransfer, error)
+	Cancel(id int, userID int) error
+}
+
+type transferRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewTransferRepository(db database.PgxIface, log *zap.Logger) TransferRepository {
+	return &transferRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+// Create offers a ticket to another user. Screenings starting before cutoff
+// no longer accept transfers.
+func (r *transferRepository) Create(ticketID int, fromUserID int, toUserID int, cutoff time.Time) (int, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	err = checkTransferable(tx, ticketID, fromUserID, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM ticket_transfers WHERE ticket_id = $1 AND status = 'pending')`
+	err = tx.QueryRow(context.Background(), query, ticketID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check ticket transfer: ", zap.Error(err))
+		return 0, err
+	}
+	if exists {
+		err = errors.New("ticket already has a pending transfer")
+		return 0, err
+	}
+
+	var id int
+	query = `INSERT INTO ticket_transfers (ticket_id, from_user_id, to_user_id, status, created_at, updated_at)
+	VALUES ($1, $2, $3, 'pending', NOW(), NOW()) RETURNING id`
+	err = tx.QueryRow(context.Background(), query, ticketID, fromUserID, toUserID).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create ticket transfer: ", zap.Error(err))
+		return 0, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *transferRepository) GetByID(id int) (*entity.TicketTransfer, error) {
+	query := `SELECT ` + transferColumns + `
+	` + transferJoins + `
+	WHERE tt.id = $1`
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get ticket transfer: ", zap.Error(err))
+		return nil, err
+	}
+	transfers, err := r.scanTransfers(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, utils.ErrNotFound("transfer")
+	}
+	return &transfers[0], nil
+}
+
+// GetByUser lists the transfers a user sent or received, newest first
+func (r *transferRepository) GetByUser(userID int) ([]entity.TicketTransfer, error) {
+	query := `SELECT ` + transferColumns + `
+	` + transferJoins + `
+	WHERE tt.from_user_id = $1 OR tt.to_user_id = $1
+	ORDER BY tt.created_at DESC, tt.id DESC`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get user ticket transfers: ", zap.Error(err))
+		return nil, err
+	}
+	return r.scanTransfers(rows)
+}
+
+// Accept revokes the sender's ticket and issues the recipient a new one for
+// the same seat with a fresh QR token
+func (r *transferRepository) Accept(id int, userID int, cutoff time.Time) (*entity.TicketTransfer, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	var transfer entity.TicketTransfer
+	query := `SELECT id, ticket_id, from_user_id, to_user_id FROM ticket_transfers
+	WHERE id = $1 AND to_user_id = $2 AND status = 'pending'
+	FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, id, userID).Scan(&transfer.ID, &transfer.TicketID, &transfer.FromUserID, &transfer.ToUserID)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("transfer")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get ticket transfer: ", zap.Error(err))
+		return nil, err
+	}
+
+	// The ticket must still be the sender's to give
+	err = checkTransferable(tx, transfer.TicketID, transfer.FromUserID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `UPDATE tickets SET revoked_at = NOW() WHERE id = $1 RETURNING booking_id, seat_id`
+	var seatID int
+	err = tx.QueryRow(context.Background(), query, transfer.TicketID).Scan(&transfer.BookingID, &seatID)
+	if err != nil {
+		r.Logger.Error("Error query revoke ticket: ", zap.Error(err))
+		return nil, err
+	}
+
+	qrToken, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	var newTicketID int
+	query = `INSERT INTO tickets (booking_id, seat_id, qr_token, holder_user_id, created_at)
+	VALUES ($1, $2, $3, $4, NOW()) RETURNING id`
+	err = tx.QueryRow(context.Background(), query, transfer.BookingID, seatID, qrToken, userID).Scan(&newTicketID)
+	if err != nil {
+		r.Logger.Error("Error query create ticket: ", zap.Error(err))
+		return nil, err
+	}
+	transfer.NewTicketID = &newTicketID
+
+	query = `UPDATE ticket_transfers SET status = 'accepted', new_ticket_id = $2, updated_at = NOW() WHERE id = $1`
+	_, err = tx.Exec(context.Background(), query, id, newTicketID)
+	if err != nil {
+		r.Logger.Error("Error query accept ticket transfer: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// Cancel withdraws a pending transfer for the sender or declines it for the recipient
+func (r *transferRepository) Cancel(id int, userID int) error {
+	query := `UPDATE ticket_transfers
+	SET status = CASE WHEN from_user_id = $2 THEN 'cancelled'::ticket_transfer_status ELSE 'declined'::ticket_transfer_status END,
+	updated_at = NOW()
+	WHERE id = $1 AND status = 'pending' AND (from_user_id = $2 OR to_user_id = $2)`
+	result, err := r.db.Exec(context.Background(), query, id, userID)
+	if err != nil {
+		r.Logger.Error("Error query cancel ticket transfer: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("transfer")
+	}
+	return nil
+}
+
+func (r *transferRepository) scanTransfers(rows pgx.Rows) ([]entity.TicketTransfer, error) {
+	defer rows.Close()
+
+	var transfers []entity.TicketTransfer
+	for rows.Next() {
+		var t entity.TicketTransfer
+		err := rows.Scan(&t.ID, &t.TicketID, &t.BookingID, &t.ScreeningID, &t.MovieID, &t.MovieTitle, &t.CinemaName,
+			&t.SeatCode, &t.StartTime, &t.FromUserID, &t.FromName, &t.ToUserID, &t.ToEmail, &t.Status,
+			&t.NewTicketID, &t.CreatedAt, &t.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan ticket transfer: ", zap.Error(err))
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+// checkTransferable locks a ticket and checks the user holds it and it can
+// still change hands: valid, not checked in and before the cutoff
+func checkTransferable(tx pgx.Tx, ticketID int, userID int, cutoff time.Time) error {
+	var holderID int
+	var revoked, checkedIn bool
+	var status string
+	var startTime time.Time
+	query := `SELECT COALESCE(t.holder_user_id, b.user_id), t.revoked_at IS NOT NULL, t.checked_in_at IS NOT NULL,
+		b.status, s.start_time
+	FROM tickets t
+	JOIN bookings b ON b.id = t.booking_id
+	JOIN screenings s ON s.id = b.screening_id
+	WHERE t.id = $1
+	FOR UPDATE OF t`
+	err := tx.QueryRow(context.Background(), query, ticketID).Scan(&holderID, &revoked, &checkedIn, &status, &startTime)
+	if err == pgx.ErrNoRows || (err == nil && holderID != userID) {
+		return utils.ErrNotFound("ticket")
+	}
+	if err != nil {
+		return err
+	}
+	if revoked || status != "paid" {
+		return errors.New("ticket is no longer valid")
+	}
+	if checkedIn {
+		return errors.New("ticket has already been checked in")
+	}
+	if !startTime.After(cutoff) {
+		return errors.New("tickets can no longer be transferred for this screening")
+	}
+	return nil
+}
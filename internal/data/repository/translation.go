@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// TranslationTable describes where the translations of one record type live
+type TranslationTable struct {
+	Name     string
+	Table    string
+	Key      string
+	Parent   string
+	Required []string
+	Optional []string
+}
+
+func (t TranslationTable) fields() []string {
+	return append(append([]string{}, t.Required...), t.Optional...)
+}
+
+// Record types with translatable text, keyed by the name used in the API
+var TranslationTables = map[string]TranslationTable{
+	"movies": {
+		Name: "movie", Table: "movie_translations", Key: "movie_id", Parent: "movies",
+		Required: []string{"title", "synopsis"},
+	},
+	"genres": {
+		Name: "genre", Table: "genre_translations", Key: "genre_id", Parent: "genres",
+		Required: []string{"name"},
+	},
+	"cinemas": {
+		Name: "cinema", Table: "cinema_translations", Key: "cinema_id", Parent: "cinemas",
+		Required: []string{"name"}, Optional: []string{"location"},
+	},
+	"studio-types": {
+		Name: "studio type", Table: "studio_type_translations", Key: "studio_type_id", Parent: "studio_types",
+		Required: []string{"name"},
+	},
+}
+
+type TranslationRepository interface {
+	GetAll(t TranslationTable, id int) ([]entity.Translation, error)
+	Save(t TranslationTable, id int, locale string, fields map[string]string) (*entity.Translation, error)
+	Delete(t TranslationTable, id int, locale string) error
+	GetTexts(t TranslationTable, locale string, ids []int) (map[int]map[string]string, error)
+	GetNames(t TranslationTable, locale string) (map[string]string, error)
+}
+
+type translationRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewTranslationRepository(db database.PgxIface, log *zap.Logger) TranslationRepository {
+	return &translationRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+// fieldsObject builds a jsonb object of the translated columns, skipping NULLs
+func fieldsObject(t TranslationTable) string {
+	var pairs []string
+	for _, f := range t.fields() {
+		pairs = append(pairs, fmt.Sprintf("'%s', %s", f, f))
+	}
+	return "jsonb_strip_nulls(jsonb_build_object(" + strings.Join(pairs, ", ") + "))"
+}
+
+func (r *translationRepository) GetAll(t TranslationTable, id int) ([]entity.Translation, error) {
+	// Check record
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1%s)`, t.Parent, notDeleted(t))
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check translated record: ", zap.Error(err))
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrNotFound(t.Name)
+	}
+
+	query = fmt.Sprintf(`SELECT locale, %s, created_at, updated_at FROM %s WHERE %s = $1 ORDER BY locale ASC`,
+		fieldsObject(t), t.Table, t.Key)
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get translations: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []entity.Translation{}
+	for rows.Next() {
+		var tr entity.Translation
+		err := rows.Scan(&tr.Locale, &tr.Fields, &tr.CreatedAt, &tr.UpdatedAt)
+		if err != nil {
+			r.Logger.Error("Error scan translation: ", zap.Error(err))
+			return nil, err
+		}
+		translations = append(translations, tr)
+	}
+	return translations, nil
+}
+
+func (r *translationRepository) Save(t TranslationTable, id int, locale string, fields map[string]string) (*entity.Translation, error) {
+	// Check record
+	var exists bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1%s)`, t.Parent, notDeleted(t))
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check translated record: ", zap.Error(err))
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.ErrNotFound(t.Name)
+	}
+
+	columns := t.fields()
+	args := []any{id, locale}
+	var placeholders, updates []string
+	for i, c := range columns {
+		args = append(args, nullString(fields[c]))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+3))
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", c, c))
+	}
+
+	var tr entity.Translation
+	query = fmt.Sprintf(`INSERT INTO %s (%s, locale, %s, created_at, updated_at)
+	VALUES ($1, $2, %s, NOW(), NOW())
+	ON CONFLICT (%s, locale) DO UPDATE SET %s, updated_at = NOW()
+	RETURNING locale, %s, created_at, updated_at`,
+		t.Table, t.Key, strings.Join(columns, ", "), strings.Join(placeholders, ", "),
+		t.Key, strings.Join(updates, ", "), fieldsObject(t))
+	err = r.db.QueryRow(context.Background(), query, args...).Scan(&tr.Locale, &tr.Fields, &tr.CreatedAt, &tr.UpdatedAt)
+	if err != nil {
+		r.Logger.Error("Error query save translation: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &tr, nil
+}
+
+func (r *translationRepository) Delete(t TranslationTable, id int, locale string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND locale = $2`, t.Table, t.Key)
+	result, err := r.db.Exec(context.Background(), query, id, locale)
+	if err != nil {
+		r.Logger.Error("Error query delete translation: ", zap.Error(err))
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return utils.ErrNotFound("translation")
+	}
+	return nil
+}
+
+// GetTexts returns the translated fields of the given records by id
+func (r *translationRepository) GetTexts(t TranslationTable, locale string, ids []int) (map[int]map[string]string, error) {
+	texts := map[int]map[string]string{}
+	if len(ids) == 0 {
+		return texts, nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s, %s FROM %s WHERE locale = $1 AND %s = ANY($2)`,
+		t.Key, fieldsObject(t), t.Table, t.Key)
+	rows, err := r.db.Query(context.Background(), query, locale, ids)
+	if err != nil {
+		r.Logger.Error("Error query get translated texts: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var fields map[string]string
+		err := rows.Scan(&id, &fields)
+		if err != nil {
+			r.Logger.Error("Error scan translated text: ", zap.Error(err))
+			return nil, err
+		}
+		texts[id] = fields
+	}
+	return texts, nil
+}
+
+// GetNames maps default locale names to their translation, for records that
+// responses carry by name only such as genres and studio types
+func (r *translationRepository) GetNames(t TranslationTable, locale string) (map[string]string, error) {
+	query := fmt.Sprintf(`SELECT p.name, tr.name FROM %s tr JOIN %s p ON p.id = tr.%s WHERE tr.locale = $1`,
+		t.Table, t.Parent, t.Key)
+	rows, err := r.db.Query(context.Background(), query, locale)
+	if err != nil {
+		r.Logger.Error("Error query get translated names: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[string]string{}
+	for rows.Next() {
+		var name, translated string
+		err := rows.Scan(&name, &translated)
+		if err != nil {
+			r.Logger.Error("Error scan translated name: ", zap.Error(err))
+			return nil, err
+		}
+		names[name] = translated
+	}
+	return names, nil
+}
+
+// Studio types are never soft deleted
+func notDeleted(t TranslationTable) string {
+	if t.Parent == "studio_types" {
+		return ""
+	}
+	return " AND deleted_at IS NULL"
+}
@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TwoFactorRepository interface {
+	GetTOTP(userID int) (*entity.UserTOTP, error)
+	SaveSecret(userID int, secret string) error
+	Enable(userID int, codeHashes []string) error
+	Disable(userID int) error
+	MarkStepUsed(userID int, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID int, codeHashes []string) error
+	GetRecoveryCodes(userID int) ([]entity.RecoveryCode, error)
+	UseRecoveryCode(id int) (bool, error)
+	CreateChallenge(userID int) (uuid.UUID, error)
+	GetChallenge(token string) (*entity.TwoFactorChallenge, error)
+	AddChallengeAttempt(id int) error
+	CountRecentFailures(userID int, since time.Time) (int, error)
+	UseChallenge(id int) (bool, error)
+}
+
+type twoFactorRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewTwoFactorRepository(db database.PgxIface, log *zap.Logger) TwoFactorRepository {
+	return &twoFactorRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *twoFactorRepository) GetTOTP(userID int) (*entity.UserTOTP, error) {
+	var t entity.UserTOTP
+	query := `SELECT user_id, secret, enabled_at, last_used_step, created_at, updated_at
+	FROM user_totp WHERE user_id = $1`
+	err := r.db.QueryRow(context.Background(), query, userID).Scan(&t.UserID, &t.Secret,
+		&t.EnabledAt, &t.LastUsedStep, &t.CreatedAt, &t.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("two factor")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get totp: ", zap.Error(err))
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *twoFactorRepository) SaveSecret(userID int, secret string) error {
+	// Pending enrolment can be restarted, an enabled one cannot be overwritten
+	query := `INSERT INTO user_totp (user_id, secret, created_at, updated_at)
+	VALUES ($1, $2, NOW(), NOW())
+	ON CONFLICT (user_id) DO UPDATE
+	SET secret = EXCLUDED.secret, last_used_step = NULL, updated_at = NOW()
+	WHERE user_totp.enabled_at IS NULL`
+	_, err := r.db.Exec(context.Background(), query, userID, secret)
+	if err != nil {
+		r.Logger.Error("Error query save totp secret: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Enable(userID int, codeHashes []string) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := `UPDATE user_totp SET enabled_at = NOW(), updated_at = NOW() WHERE user_id = $1`
+	_, err = tx.Exec(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query enable totp: ", zap.Error(err))
+		return err
+	}
+
+	err = r.insertRecoveryCodes(tx, userID, codeHashes)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *twoFactorRepository) Disable(userID int) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete recovery codes: ", zap.Error(err))
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `DELETE FROM user_totp WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete totp: ", zap.Error(err))
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *twoFactorRepository) MarkStepUsed(userID int, step int64) (bool, error) {
+	// A code can only be used once, even inside its validity window
+	query := `UPDATE user_totp SET last_used_step = $1, updated_at = NOW()
+	WHERE user_id = $2 AND (last_used_step IS NULL OR last_used_step < $1)`
+	result, err := r.db.Exec(context.Background(), query, step, userID)
+	if err != nil {
+		r.Logger.Error("Error query mark totp step used: ", zap.Error(err))
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	err = r.insertRecoveryCodes(tx, userID, codeHashes)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (r *twoFactorRepository) insertRecoveryCodes(tx pgx.Tx, userID int, codeHashes []string) error {
+	_, err := tx.Exec(context.Background(), `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		r.Logger.Error("Error query delete recovery codes: ", zap.Error(err))
+		return err
+	}
+
+	query := `INSERT INTO user_recovery_codes (user_id, code_hash, created_at)
+	SELECT $1, unnest($2::text[]), NOW()`
+	_, err = tx.Exec(context.Background(), query, userID, codeHashes)
+	if err != nil {
+		r.Logger.Error("Error query create recovery codes: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) GetRecoveryCodes(userID int) ([]entity.RecoveryCode, error) {
+	query := `SELECT id, user_id, code_hash, used_at FROM user_recovery_codes
+	WHERE user_id = $1 AND used_at IS NULL`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get recovery codes: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []entity.RecoveryCode
+	for rows.Next() {
+		var c entity.RecoveryCode
+		err := rows.Scan(&c.ID, &c.UserID, &c.CodeHash, &c.UsedAt)
+		if err != nil {
+			r.Logger.Error("Error scan recovery code: ", zap.Error(err))
+			return nil, err
+		}
+		codes = append(codes, c)
+	}
+	return codes, nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(id int) (bool, error) {
+	query := `UPDATE user_recovery_codes SET used_at = NOW() WHERE id = $1 AND used_at IS NULL`
+	result, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query use recovery code: ", zap.Error(err))
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) CreateChallenge(userID int) (uuid.UUID, error) {
+	// Short lived token issued after a correct password
+	token, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		r.Logger.Error("Error create token: ", zap.Error(err))
+		return uuid.Nil, err
+	}
+
+	query := `INSERT INTO two_factor_challenges (token, user_id, expired_at, created_at)
+	VALUES ($1, $2, $3, NOW())`
+	_, err = r.db.Exec(context.Background(), query, token, userID, time.Now().Add(5*time.Minute))
+	if err != nil {
+		r.Logger.Error("Error query create two factor challenge: ", zap.Error(err))
+		return uuid.Nil, err
+	}
+	return token, nil
+}
+
+func (r *twoFactorRepository) GetChallenge(token string) (*entity.TwoFactorChallenge, error) {
+	var c entity.TwoFactorChallenge
+	query := `SELECT id, token, user_id, attempts, expired_at FROM two_factor_challenges
+	WHERE token::text = $1 AND expired_at > NOW() AND used_at IS NULL`
+	err := r.db.QueryRow(context.Background(), query, token).Scan(&c.ID, &c.Token, &c.UserID, &c.Attempts, &c.ExpiredAt)
+	if err == pgx.ErrNoRows {
+		return nil, utils.ErrNotFound("challenge")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get two factor challenge: ", zap.Error(err))
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *twoFactorRepository) AddChallengeAttempt(id int) error {
+	query := `UPDATE two_factor_challenges SET attempts = attempts + 1 WHERE id = $1`
+	_, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query update two factor challenge: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) CountRecentFailures(userID int, since time.Time) (int, error) {
+	// Wrong codes over every challenge of the user, a successful login starts over
+	query := `SELECT COALESCE(SUM(attempts), 0) FROM two_factor_challenges
+	WHERE user_id = $1 AND created_at > GREATEST($2::timestamptz, COALESCE(
+		(SELECT MAX(used_at) FROM two_factor_challenges WHERE user_id = $1), '-infinity'))`
+	var failures int
+	err := r.db.QueryRow(context.Background(), query, userID, since).Scan(&failures)
+	if err != nil {
+		r.Logger.Error("Error query count two factor failures: ", zap.Error(err))
+		return 0, err
+	}
+	return failures, nil
+}
+
+func (r *twoFactorRepository) UseChallenge(id int) (bool, error) {
+	// Only one verify can consume a challenge
+	query := `UPDATE two_factor_challenges SET used_at = NOW()
+	WHERE id = $1 AND used_at IS NULL AND expired_at > NOW()`
+	result, err := r.db.Exec(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query use two factor challenge: ", zap.Error(err))
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
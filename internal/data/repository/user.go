@@ -16,7 +16,10 @@ type UserRepository interface {
 	FindByEmail(email string) (*entity.User, error)
 	GetAll(q dto.PaginationQuery) ([]entity.User, int, error)
 	GetByID(id int) (entity.User, error)
+	GetPassword(id int) (*string, error)
 	Update(id int, data *entity.User) error
+	UpdatePassword(id int, password string) error
+	SetCinema(id int, cinemaID *int) error
 	Delete(id int) error
 }
 
@@ -34,11 +37,11 @@ func NewUserRepository(db database.PgxIface, log *zap.Logger) UserRepository {
 
 func (r *userRepository) Create(user *entity.User) (*entity.User, error) {
 	query := `
-		INSERT INTO users (name, email, password, role, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO users (name, email, password, role, locale, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
 		RETURNING id
 	`
-	err := r.db.QueryRow(context.Background(), query, user.Name, user.Email, user.Password, user.Role).Scan(&user.ID)
+	err := r.db.QueryRow(context.Background(), query, user.Name, user.Email, user.Password, user.Role, nullString(user.Locale)).Scan(&user.ID)
 	if err != nil {
 		r.Logger.Error("Error query create user: ", zap.Error(err))
 		return nil, err
@@ -49,14 +52,14 @@ func (r *userRepository) Create(user *entity.User) (*entity.User, error) {
 
 func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	query := `
-		SELECT id, created_at, updated_at, name, email, password, role
+		SELECT id, created_at, updated_at, name, email, password, role, COALESCE(locale, '')
 		FROM users
 		WHERE email = $1 AND deleted_at IS NULL
 	`
 	var user entity.User
 	err := r.db.QueryRow(context.Background(), query, email).Scan(
 		&user.ID, &user.CreatedAt, &user.UpdatedAt,
-		&user.Name, &user.Email, &user.Password, &user.Role,
+		&user.Name, &user.Email, &user.Password, &user.Role, &user.Locale,
 	)
 
 	if err != nil {
@@ -113,9 +116,9 @@ func (r *userRepository) GetAll(q dto.PaginationQuery) ([]entity.User, int, erro
 
 func (r *userRepository) GetByID(id int) (entity.User, error) {
 	var user entity.User
-	query := "SELECT id, name, email, role, created_at, updated_at FROM users WHERE id = $1"
+	query := "SELECT id, name, email, role, COALESCE(locale, ''), cinema_id, created_at, updated_at FROM users WHERE id = $1 AND deleted_at IS NULL"
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.Locale, &user.CinemaID, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == pgx.ErrNoRows {
 		r.Logger.Error("Error not found user: ", zap.Error(err))
@@ -130,18 +133,38 @@ func (r *userRepository) GetByID(id int) (entity.User, error) {
 	return user, nil
 }
 
+func (r *userRepository) GetPassword(id int) (*string, error) {
+	var password string
+	query := "SELECT password FROM users WHERE id = $1 AND deleted_at IS NULL"
+
+	err := r.db.QueryRow(context.Background(), query, id).Scan(&password)
+
+	if err == pgx.ErrNoRows {
+		r.Logger.Error("Error not found user: ", zap.Error(err))
+		return nil, utils.ErrNotFound("user")
+	}
+
+	if err != nil {
+		r.Logger.Error("Error query get user password: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &password, nil
+}
+
 func (r *userRepository) Update(id int, u *entity.User) error {
 	query := `
 		UPDATE users
 		SET name = COALESCE($1, name),
 		email = COALESCE($2, email),
 		role = COALESCE($3, role),
+		locale = COALESCE($4, locale),
 		updated_at = NOW()
-		WHERE id = $4 AND deleted_at IS NULL
+		WHERE id = $5 AND deleted_at IS NULL
 	`
 
 	result, err := r.db.Exec(context.Background(), query,
-		&u.Name, &u.Email, &u.Role, id,
+		&u.Name, &u.Email, &u.Role, nullString(u.Locale), id,
 	)
 
 	rowsAffected := result.RowsAffected()
@@ -158,6 +181,41 @@ func (r *userRepository) Update(id int, u *entity.User) error {
 	return nil
 }
 
+// SetCinema assigns the cinema a cashier sells for, nil clears it
+func (r *userRepository) SetCinema(id int, cinemaID *int) error {
+	query := `UPDATE users SET cinema_id = $1, updated_at = NOW() WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.Exec(context.Background(), query, cinemaID, id)
+	if err != nil {
+		r.Logger.Error("Error query set user cinema: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(id int, password string) error {
+	query := `
+		UPDATE users
+		SET password = $1,
+		updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(context.Background(), query, password, id)
+
+	rowsAffected := result.RowsAffected()
+	if rowsAffected == 0 {
+		r.Logger.Error("Error not found user: ", zap.Error(err))
+		return utils.ErrNotFound("user")
+	}
+
+	if err != nil {
+		r.Logger.Error("Error query update user password: ", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (r *userRepository) Delete(id int) error {
 	query := `
 		UPDATE users
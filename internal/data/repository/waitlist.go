@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/pkg/database"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Seats promised to waitlist offers that are still open, needs screenings s
+const offeredSeats = `(SELECT COALESCE(SUM(w.seat_count), 0)
+			FROM waitlist_entries w
+			WHERE w.screening_id = s.id AND w.status = 'offered' AND w.offer_expires_at > NOW()
+		)`
+
+const waitlistColumns = `w.id, w.screening_id, m.id, m.title, c.name, s.start_time, w.user_id, w.seat_count,
+	w.status, w.offer_expires_at, w.created_at, w.updated_at,
+	CASE WHEN w.status = 'waiting' THEN (
+		SELECT COUNT(*)::int FROM waitlist_entries o
+		WHERE o.screening_id = w.screening_id AND o.status = 'waiting'
+			AND (o.created_at, o.id) <= (w.created_at, w.id)
+	) END AS position`
+
+type WaitlistRepository interface {
+	Join(screeningID int, userID int, seatCount int) (int, error)
+	GetByID(id int) (*entity.WaitlistEntry, error)
+	GetByUser(userID int) ([]entity.WaitlistEntry, error)
+	Leave(id int, userID int) (int, error)
+	Offer(screeningID int) ([]entity.WaitlistEntry, error)
+	GetOpenScreenings() ([]int, error)
+}
+
+type waitlistRepository struct {
+	db     database.PgxIface
+	Logger *zap.Logger
+}
+
+func NewWaitlistRepository(db database.PgxIface, log *zap.Logger) WaitlistRepository {
+	return &waitlistRepository{
+		db:     db,
+		Logger: log,
+	}
+}
+
+func (r *waitlistRepository) Join(screeningID int, userID int, seatCount int) (int, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Lock the screening so seat counts do not move while joining
+	var startTime time.Time
+	query := `SELECT start_time FROM screenings WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, screeningID).Scan(&startTime)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("screening")
+	}
+	if err != nil {
+		r.Logger.Error("Error query get screening: ", zap.Error(err))
+		return 0, err
+	}
+	if !startTime.After(time.Now()) {
+		err = errors.New("screening has already started")
+		return 0, err
+	}
+
+	total, free, offered, err := seatCounts(tx, screeningID, userID)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return 0, err
+	}
+	if seatCount > total {
+		err = fmt.Errorf("the studio only has %d seats", total)
+		return 0, err
+	}
+	if seatCount <= free-offered {
+		err = errors.New("enough seats are available, book them directly")
+		return 0, err
+	}
+
+	var exists bool
+	query = `SELECT EXISTS (
+		SELECT 1 FROM waitlist_entries
+		WHERE screening_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')
+	)`
+	err = tx.QueryRow(context.Background(), query, screeningID, userID).Scan(&exists)
+	if err != nil {
+		r.Logger.Error("Error query check waitlist entry: ", zap.Error(err))
+		return 0, err
+	}
+	if exists {
+		err = errors.New("already on the waitlist for this screening")
+		return 0, err
+	}
+
+	var id int
+	query = `INSERT INTO waitlist_entries (screening_id, user_id, seat_count, status, created_at, updated_at)
+	VALUES ($1, $2, $3, 'waiting', NOW(), NOW()) RETURNING id`
+	err = tx.QueryRow(context.Background(), query, screeningID, userID, seatCount).Scan(&id)
+	if err != nil {
+		r.Logger.Error("Error query create waitlist entry: ", zap.Error(err))
+		return 0, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *waitlistRepository) GetByID(id int) (*entity.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + `
+	FROM waitlist_entries w
+	JOIN screenings s ON s.id = w.screening_id
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id
+	WHERE w.id = $1`
+	rows, err := r.db.Query(context.Background(), query, id)
+	if err != nil {
+		r.Logger.Error("Error query get waitlist entry: ", zap.Error(err))
+		return nil, err
+	}
+	entries, err := r.scanEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, utils.ErrNotFound("waitlist entry")
+	}
+	return &entries[0], nil
+}
+
+func (r *waitlistRepository) GetByUser(userID int) ([]entity.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + `
+	FROM waitlist_entries w
+	JOIN screenings s ON s.id = w.screening_id
+	JOIN movies m ON m.id = s.movie_id
+	JOIN studios st ON st.id = s.studio_id
+	JOIN cinemas c ON c.id = st.cinema_id
+	WHERE w.user_id = $1
+	ORDER BY w.created_at DESC, w.id DESC`
+	rows, err := r.db.Query(context.Background(), query, userID)
+	if err != nil {
+		r.Logger.Error("Error query get user waitlist: ", zap.Error(err))
+		return nil, err
+	}
+	return r.scanEntries(rows)
+}
+
+// Leave takes an open entry out of the queue and returns its screening
+func (r *waitlistRepository) Leave(id int, userID int) (int, error) {
+	var screeningID int
+	query := `UPDATE waitlist_entries SET status = 'cancelled', updated_at = NOW()
+	WHERE id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')
+	RETURNING screening_id`
+	err := r.db.QueryRow(context.Background(), query, id, userID).Scan(&screeningID)
+	if err == pgx.ErrNoRows {
+		return 0, utils.ErrNotFound("waitlist entry")
+	}
+	if err != nil {
+		r.Logger.Error("Error query leave waitlist: ", zap.Error(err))
+		return 0, err
+	}
+	return screeningID, nil
+}
+
+// Offer expires lapsed offers and holds, then offers the free seats to the
+// waiting users in queue order. It returns the entries that got an offer.
+func (r *waitlistRepository) Offer(screeningID int) ([]entity.WaitlistEntry, error) {
+	// Handle db transaction
+	tx, err := r.db.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// Lock the screening, bookings for it wait until offers are made
+	var open bool
+	query := `SELECT start_time > NOW() AND deleted_at IS NULL FROM screenings WHERE id = $1 FOR UPDATE`
+	err = tx.QueryRow(context.Background(), query, screeningID).Scan(&open)
+	if err != nil {
+		r.Logger.Error("Error query get screening: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Offers not taken pass to the next in line
+	query = `UPDATE waitlist_entries SET status = 'expired', updated_at = NOW()
+	WHERE screening_id = $1 AND status = 'offered' AND offer_expires_at <= NOW()`
+	_, err = tx.Exec(context.Background(), query, screeningID)
+	if err != nil {
+		r.Logger.Error("Error query expire waitlist offers: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Nobody can use a seat once the screening has started or was removed
+	if !open {
+		query = `UPDATE waitlist_entries SET status = 'expired', updated_at = NOW()
+		WHERE screening_id = $1 AND status IN ('waiting', 'offered')`
+		_, err = tx.Exec(context.Background(), query, screeningID)
+		if err != nil {
+			r.Logger.Error("Error query close waitlist: ", zap.Error(err))
+			return nil, err
+		}
+		err = tx.Commit(context.Background())
+		return nil, err
+	}
+
+	err = releaseExpiredHolds(tx, screeningID)
+	if err != nil {
+		r.Logger.Error("Error release expired holds: ", zap.Error(err))
+		return nil, err
+	}
+
+	_, free, offered, err := seatCounts(tx, screeningID, 0)
+	if err != nil {
+		r.Logger.Error("Error query count seats: ", zap.Error(err))
+		return nil, err
+	}
+	available := free - offered
+
+	// Queue order, first come first served
+	query = `SELECT id, seat_count FROM waitlist_entries
+	WHERE screening_id = $1 AND status = 'waiting'
+	ORDER BY created_at, id`
+	rows, err := tx.Query(context.Background(), query, screeningID)
+	if err != nil {
+		r.Logger.Error("Error query get waitlist queue: ", zap.Error(err))
+		return nil, err
+	}
+	var queue []entity.WaitlistEntry
+	for rows.Next() {
+		var e entity.WaitlistEntry
+		err = rows.Scan(&e.ID, &e.SeatCount)
+		if err != nil {
+			rows.Close()
+			r.Logger.Error("Error scan waitlist entry: ", zap.Error(err))
+			return nil, err
+		}
+		queue = append(queue, e)
+	}
+	rows.Close()
+
+	// The head of the queue is never skipped for a smaller request behind it
+	var ids []int
+	for _, e := range queue {
+		if e.SeatCount > available {
+			break
+		}
+		query = `UPDATE waitlist_entries
+		SET status = 'offered', offer_expires_at = NOW() + interval '15 minute', updated_at = NOW()
+		WHERE id = $1`
+		_, err = tx.Exec(context.Background(), query, e.ID)
+		if err != nil {
+			r.Logger.Error("Error query offer waitlist seats: ", zap.Error(err))
+			return nil, err
+		}
+		available -= e.SeatCount
+		ids = append(ids, e.ID)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []entity.WaitlistEntry
+	for _, id := range ids {
+		e, err := r.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *e)
+	}
+	return offers, nil
+}
+
+// GetOpenScreenings lists screenings with users still waiting or holding an offer
+func (r *waitlistRepository) GetOpenScreenings() ([]int, error) {
+	query := `SELECT DISTINCT screening_id FROM waitlist_entries WHERE status IN ('waiting', 'offered')`
+	rows, err := r.db.Query(context.Background(), query)
+	if err != nil {
+		r.Logger.Error("Error query get open waitlists: ", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			r.Logger.Error("Error scan screening id: ", zap.Error(err))
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *waitlistRepository) scanEntries(rows pgx.Rows) ([]entity.WaitlistEntry, error) {
+	defer rows.Close()
+
+	var entries []entity.WaitlistEntry
+	for rows.Next() {
+		var e entity.WaitlistEntry
+		err := rows.Scan(&e.ID, &e.ScreeningID, &e.MovieID, &e.MovieTitle, &e.CinemaName, &e.StartTime, &e.UserID, &e.SeatCount,
+			&e.Status, &e.OfferExpiresAt, &e.CreatedAt, &e.UpdatedAt, &e.Position)
+		if err != nil {
+			r.Logger.Error("Error scan waitlist entry: ", zap.Error(err))
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// seatCounts returns the seats of a screening's studio, the seats not held by
+// a booking and the seats promised to other users' waitlist offers
+func seatCounts(tx pgx.Tx, screeningID int, userID int) (int, int, int, error) {
+	var total, taken, offered int
+	query := `SELECT
+		(SELECT COUNT(*) FROM seats se WHERE se.studio_id = s.studio_id AND se.deleted_at IS NULL),
+		(SELECT COUNT(DISTINCT bs.seat_id)
+			FROM booking_seats bs
+			JOIN bookings b ON b.id = bs.booking_id
+			WHERE bs.screening_id = s.id AND ` + seatHeld + `
+		),
+		(SELECT COALESCE(SUM(w.seat_count), 0)
+			FROM waitlist_entries w
+			WHERE w.screening_id = s.id AND w.status = 'offered' AND w.offer_expires_at > NOW() AND w.user_id <> $2
+		)
+	FROM screenings s
+	WHERE s.id = $1`
+	err := tx.QueryRow(context.Background(), query, screeningID, userID).Scan(&total, &taken, &offered)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return total, total - taken, offered, nil
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -16,7 +20,41 @@ type RegisterRequest struct {
 type AuthResponse struct {
 	Name  string    `json:"name"`
 	Email string    `json:"email"`
-	Token uuid.UUID `json:"token"`
+	Token *uuid.UUID `json:"token,omitempty"`
+	TwoFactorRequired bool `json:"two_factor_required,omitempty"`
+	ChallengeToken *uuid.UUID `json:"challenge_token,omitempty"`
+	TwoFactorSetupRequired bool `json:"two_factor_setup_required,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required,uuid"`
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled bool `json:"enabled"`
+	Required bool `json:"required"`
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int `json:"recovery_codes_left"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCode string `json:"qr_code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type OTPRequest struct {
@@ -28,4 +66,16 @@ type OTPResponse struct {
 	Name  string    `json:"name"`
 	Email string    `json:"email"`
 	OTP string `json:"otp"`
-}
\ No newline at end of file
+}
+type OIDCLoginResponse struct {
+	Provider string `json:"provider"`
+	AuthorizationURL string `json:"authorization_url"`
+	State string `json:"state"`
+}
+
+type OIDCCallbackRequest struct {
+	Code string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+	// State kept in the cookie of the browser that started the login
+	BrowserState string `json:"-"`
+}
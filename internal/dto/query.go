@@ -1,9 +1,26 @@
 package dto
 
 type PaginationQuery struct {
-	Page  int
-	Limit int
-	All   bool
+	Page   int
+	Limit  int
+	All    bool
+	Locale string
+}
+
+type CinemaQuery struct {
+	PaginationQuery
+	City      string
+	Amenities []string
+}
+
+// Cinemas around a point, RadiusKm 0 means no limit
+type NearestCinemaQuery struct {
+	PaginationQuery
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	City      string
+	Amenities []string
 }
 
 type MovieQuery struct {
@@ -13,7 +30,46 @@ type MovieQuery struct {
 
 type ScreeningQuery struct {
 	PaginationQuery
-	CinemaID int
-	MovieID  int
-	Date     string
-}
\ No newline at end of file
+	CinemaID         int
+	MovieID          int
+	Date             string
+	Format           string
+	AudioLanguage    string
+	SubtitleLanguage string
+	AudioDescription bool
+	ClosedCaptions   bool
+	// Showtime search across cinemas, Date to DateTo inclusive
+	DateTo    string
+	City      string
+	Latitude  *float64
+	Longitude *float64
+	RadiusKm  float64
+}
+type MovieSearchQuery struct {
+	PaginationQuery
+	Keyword      string
+	Genre        string
+	Language     string
+	RatingAge    string
+	ReleasedFrom string
+	ReleasedTo   string
+	MinDuration  int
+	MaxDuration  int
+	CinemaID     int
+	Date         string
+	Sort         string
+	Order        string
+}
+
+type SeatSuggestionQuery struct {
+	ScreeningID int
+	PartySize   int
+	Aisle       bool
+	Accessible  bool
+}
+
+// Shifts opened on Date (dd-mm-yyyy, today by default), CashierID 0 means all cashiers
+type ShiftQuery struct {
+	Date      string
+	CashierID int
+}
@@ -1,8 +1,30 @@
 package dto
 
+import "time"
+
 type CinemaRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Location string `json:"location" validate:"required"`
+	Name      string   `json:"name" validate:"required"`
+	Location  string   `json:"location" validate:"required"`
+	Address   *string  `json:"address" validate:"omitempty,max=500"`
+	City      *string  `json:"city" validate:"omitempty,max=100"`
+	Region    *string  `json:"region" validate:"omitempty,max=100"`
+	Latitude  *float64 `json:"latitude" validate:"required_with=Longitude,omitempty,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" validate:"required_with=Latitude,omitempty,gte=-180,lte=180"`
+	// Replaced when given, kept when omitted
+	OpeningHours []OpeningHourRequest `json:"opening_hours" validate:"omitempty,max=7,dive"`
+	Amenities    []string             `json:"amenities" validate:"omitempty,dive,required,max=50"`
+}
+
+// Weekday 0 is Sunday, times are local HH.MM
+type OpeningHourRequest struct {
+	Weekday   int    `json:"weekday" validate:"gte=0,lte=6"`
+	OpenTime  string `json:"open_time" validate:"required,datetime=15.04"`
+	CloseTime string `json:"close_time" validate:"required,datetime=15.04"`
+}
+
+type CinemaClosureRequest struct {
+	Date   string `json:"date" validate:"required,datetime=02-01-2006"`
+	Reason string `json:"reason" validate:"max=500"`
 }
 
 type StudioRequest struct {
@@ -11,6 +33,18 @@ type StudioRequest struct {
 	Type     int    `json:"type" validate:"required"`
 }
 
+// Names holds the display name per locale, e.g. {"id": "Laga"}
+type GenreRequest struct {
+	Name     string            `json:"name" validate:"required,max=200"`
+	ParentID *int              `json:"parent_id" validate:"omitempty,gt=0"`
+	Names    map[string]string `json:"names" validate:"omitempty,dive,keys,bcp47_language_tag,endkeys,required,max=200"`
+}
+
+// Sources are merged into the genre in the path and then deleted
+type MergeGenreRequest struct {
+	SourceIDs []int `json:"source_ids" validate:"required,min=1,dive,gt=0"`
+}
+
 type MovieRequest struct {
 	Title       string `json:"title" validate:"required"`
 	Synopsis    string `json:"synopsis" validate:"required"`
@@ -29,12 +63,28 @@ type ScreeningRequest struct {
 	StartDate  string   `json:"start_date" validate:"required,datetime=02-01-2006"`
 	EndDate    string   `json:"end_date" validate:"required,datetime=02-01-2006"`
 	StartHours []string `json:"start_hours" validate:"required,dive,datetime=15.04"`
+	ScreeningAttributes
 }
 
 type UpdateScreeningRequest struct {
 	StudioID  int    `json:"studio_id"`
 	MovieID   int    `json:"movie_id"`
 	StartTime string `json:"start_time" validate:"required"`
+	ScreeningAttributes
+}
+
+// Empty format means 2D, empty audio language means the movie's original language
+type ScreeningAttributes struct {
+	Format           string  `json:"format" validate:"omitempty,max=20"`
+	AudioLanguage    *string `json:"audio_language" validate:"omitempty,max=50"`
+	SubtitleLanguage *string `json:"subtitle_language" validate:"omitempty,max=50"`
+	AudioDescription bool    `json:"audio_description"`
+	ClosedCaptions   bool    `json:"closed_captions"`
+}
+
+type ScreeningFormatRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Surcharge *float64 `json:"surcharge" validate:"required,gte=0"`
 }
 
 type BookingRequest struct {
@@ -79,4 +129,157 @@ type StudioType struct {
 	Row    int     `json:"row" validate:"required,gt=0"`
 	Column int     `json:"column" validate:"required,gt=0"`
 	Price  float64 `json:"price" validate:"required,gt=0"`
-}
\ No newline at end of file
+}
+type UpdateProfileRequest struct {
+	Name   string `json:"name" validate:"required"`
+	Locale string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=16"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type UserRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin customer cashier"`
+	// Cashiers sell for one cinema
+	CinemaID *int `json:"cinema_id" validate:"required_if=Role cashier,omitempty,gt=0"`
+}
+
+type APIKeyRequest struct {
+	Name       string     `json:"name" validate:"required"`
+	UserID     int        `json:"user_id" validate:"required"`
+	Scopes     []string   `json:"scopes" validate:"required,min=1,dive,oneof=seats bookings payments"`
+	CinemaIDs  []int      `json:"cinema_ids" validate:"dive,gt=0"`
+	AllowedIPs []string   `json:"allowed_ips" validate:"dive,cidr|ip"`
+	ExpiredAt  *time.Time `json:"expired_at"`
+}
+
+type MovieRunRequest struct {
+	MovieID        int    `json:"movie_id" validate:"required"`
+	CinemaID       int    `json:"cinema_id" validate:"required"`
+	PresaleStartAt string `json:"presale_start_at" validate:"omitempty,datetime=02-01-2006 15.04"`
+	StartDate      string `json:"start_date" validate:"required,datetime=02-01-2006"`
+	EndDate        string `json:"end_date" validate:"omitempty,datetime=02-01-2006"`
+}
+
+type PersonRequest struct {
+	Name      string `json:"name" validate:"required,max=150"`
+	Biography string `json:"biography"`
+	PhotoURL  string `json:"photo_url" validate:"omitempty,url"`
+	BirthDate string `json:"birth_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type CreditRequest struct {
+	PersonID      int    `json:"person_id" validate:"required,gt=0"`
+	Role          string `json:"role" validate:"required,oneof=director writer producer cast composer cinematographer editor"`
+	CharacterName string `json:"character_name" validate:"max=150"`
+	Order         int    `json:"billing_order" validate:"gte=0"`
+}
+
+// Replaces the whole credit list of a movie
+type MovieCreditsRequest struct {
+	Credits []CreditRequest `json:"credits" validate:"dive"`
+}
+
+type ReviewRequest struct {
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Content string `json:"content" validate:"required,max=5000"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+	Note   string `json:"note" validate:"max=1000"`
+}
+
+type MediaLinkRequest struct {
+	MediaID  int    `json:"media_id" validate:"required,gt=0"`
+	Kind     string `json:"kind" validate:"required"`
+	Position int    `json:"position" validate:"gte=0"`
+}
+
+// Catalog import rows, also used for export. Genres, cinemas, studios and
+// movies are referenced by name so files can move between environments.
+type MovieImportRow struct {
+	Title       string   `json:"title" validate:"required"`
+	Synopsis    string   `json:"synopsis" validate:"required"`
+	Genres      []string `json:"genres" validate:"required,dive,required,max=200"`
+	PosterURL   string   `json:"poster_url" validate:"required"`
+	TrailerURL  string   `json:"trailer_url" validate:"required"`
+	Duration    int      `json:"duration_minute" validate:"required,gt=0"`
+	ReleaseDate string   `json:"release_date" validate:"required,datetime=2006-01-02"`
+	Language    string   `json:"language" validate:"required"`
+	RatingAge   string   `json:"rating_age" validate:"required"`
+}
+
+type ScreeningImportRow struct {
+	Cinema           string `json:"cinema" validate:"required"`
+	Studio           string `json:"studio" validate:"required"`
+	Movie            string `json:"movie" validate:"required"`
+	StartTime        string `json:"start_time" validate:"required,datetime=02-01-2006 15.04"`
+	Format           string `json:"format" validate:"omitempty,max=20"`
+	AudioLanguage    string `json:"audio_language" validate:"omitempty,max=50"`
+	SubtitleLanguage string `json:"subtitle_language" validate:"omitempty,max=50"`
+}
+
+// Translated text keyed by field, e.g. title and synopsis for movies
+type TranslationRequest struct {
+	Fields map[string]string `json:"fields" validate:"required,min=1"`
+}
+
+type WaitlistRequest struct {
+	SeatCount int `json:"seat_count" validate:"required,min=1,max=10"`
+}
+
+// Replaces the accessible seats of a studio, an empty list clears them
+type AccessibleSeatsRequest struct {
+	SeatCodes []string `json:"seat_codes" validate:"omitempty,dive,required,max=10"`
+}
+
+// Seats to give up and seats to take instead, in the booking's screening
+type SeatSwapRequest struct {
+	Release []int `json:"release" validate:"omitempty,unique,dive,gt=0"`
+	Hold    []int `json:"hold" validate:"omitempty,unique,dive,gt=0"`
+}
+
+type ExchangeRequest struct {
+	ScreeningID int   `json:"screening_id" validate:"required,gt=0"`
+	Seats       []int `json:"seats" validate:"required,min=1,unique,dive,gt=0"`
+}
+
+type TransferRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type CheckInRequest struct {
+	QRToken string `json:"qr_token" validate:"required"`
+}
+
+// Walk-in sale, cash needs the amount handed over and EDC the terminal's approval code
+type BoxOfficeRequest struct {
+	ScreeningID   int     `json:"screening_id" validate:"required,gt=0"`
+	Seats         []int   `json:"seats" validate:"required,min=1,unique,dive,gt=0"`
+	CustomerPhone string  `json:"customer_phone" validate:"omitempty,min=8,max=20"`
+	PaymentMethod string  `json:"payment_method" validate:"required,oneof=cash edc"`
+	Tendered      float64 `json:"tendered" validate:"required_if=PaymentMethod cash,omitempty,gt=0"`
+	ApprovalCode  string  `json:"approval_code" validate:"required_if=PaymentMethod edc,omitempty,max=50"`
+}
+
+type OpenShiftRequest struct {
+	OpeningFloat float64 `json:"opening_float" validate:"gte=0"`
+}
+
+type CloseShiftRequest struct {
+	CountedCash *float64 `json:"counted_cash" validate:"required,gte=0"`
+	Note        string   `json:"note" validate:"omitempty,max=500"`
+}
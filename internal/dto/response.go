@@ -6,6 +6,21 @@ type CinemaResponse struct {
 	CinemaID int `json:"cinema_id"`
 	Name string `json:"name"`
 	Location string `json:"location"`
+	Address *string `json:"address"`
+	City *string `json:"city"`
+	Region *string `json:"region"`
+	Latitude *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	DistanceKm *float64 `json:"distance_km,omitempty"`
+	OpeningHours []OpeningHourResponse `json:"opening_hours,omitempty"`
+	Amenities []string `json:"amenities"`
+}
+
+type OpeningHourResponse struct {
+	Weekday   int    `json:"weekday"`
+	Day       string `json:"day"`
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
 }
 
 type StudioResponse struct {
@@ -26,12 +41,24 @@ type MovieResponse struct {
 	ReleaseDate time.Time `json:"release_date"`
 	Language    string    `json:"language"`
 	RatingAge   string    `json:"rating_age"`
+	RatingAverage float64 `json:"rating_average"`
+	RatingCount   int     `json:"rating_count"`
+	Credits     []CreditResponse `json:"credits,omitempty"`
 }
 
 type ScreeningResponse struct {
 	ScreeningID int `json:"screening_id"`
 	StartTime string `json:"start_time"`
 	EndTime string `json:"end_time"`
+	Format           string  `json:"format,omitempty"`
+	AudioLanguage    *string `json:"audio_language,omitempty"`
+	SubtitleLanguage *string `json:"subtitle_language,omitempty"`
+	AudioDescription bool    `json:"audio_description"`
+	ClosedCaptions   bool    `json:"closed_captions"`
+	Price            float64 `json:"price,omitempty"`
+	SeatsTotal       int     `json:"seats_total"`
+	SeatsAvailable   int     `json:"seats_available"`
+	Availability     string  `json:"availability"`
 }
 
 // Get schedule based on selected cinema and date
@@ -57,6 +84,7 @@ type SeatResponse struct {
 	ID int `json:"seat_id"`
 	SeatCode string `json:"seat_code"`
 	Status *string `json:"status,omitempty"`
+	Accessible bool `json:"accessible,omitempty"`
 }
 
 type BookingResponse struct {
@@ -69,6 +97,8 @@ type BookingResponse struct {
 	TotalAmount float64 `json:"total_amount"`
 	Status      string `json:"status"`
 	ExpiredAt   time.Time `json:"expired_at"`
+	ExchangedFrom *int `json:"exchanged_from,omitempty"`
+	ExchangedAt   *time.Time `json:"exchanged_at,omitempty"`
 }
 
 type PaymentResponse struct {
@@ -95,6 +125,8 @@ type TicketEmail struct {
 	Studio StudioResponse	`json:"studio"`
 	Screening ScreeningResponse `json:"screening"`
 	Tickets []Ticket `json:"tickets"`
+	Invoice *Invoice `json:"invoice,omitempty"`
+	Locale string `json:"-"`
 }
 
 type BookingHistory struct {
@@ -106,4 +138,435 @@ type BookingHistory struct {
 	Status      string `json:"status"`
 	Date string `json:"date"`
 	StartTime string `json:"start_time"`
-}
\ No newline at end of file
+}
+type UserResponse struct {
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Locale    string    `json:"locale,omitempty"`
+	CinemaID  *int      `json:"cinema_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DataExportResponse struct {
+	ExportID    int        `json:"export_id"`
+	Status      string     `json:"status"`
+	DownloadURL *string    `json:"download_url,omitempty"`
+	ExpiredAt   *time.Time `json:"expired_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Personal data bundled into an export archive
+type DataExport struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Profile     UserResponse    `json:"profile"`
+	Bookings    []ExportBooking `json:"bookings"`
+	Payments    []ExportPayment `json:"payments"`
+	Tickets     []ExportTicket  `json:"tickets"`
+	Reviews     []ExportReview  `json:"reviews"`
+}
+
+type ExportBooking struct {
+	BookingID  int       `json:"booking_id"`
+	MovieTitle string    `json:"movie_title"`
+	Cinema     string    `json:"cinema"`
+	Studio     string    `json:"studio"`
+	StartTime  time.Time `json:"start_time"`
+	Seats      []string  `json:"seats"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ExportPayment struct {
+	PaymentID     int       `json:"payment_id"`
+	BookingID     int       `json:"booking_id"`
+	PaymentMethod string    `json:"payment_method"`
+	Amount        float64   `json:"amount"`
+	Status        string    `json:"status"`
+	TransactionID *string   `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ExportTicket struct {
+	TicketID  int        `json:"ticket_id"`
+	BookingID int        `json:"booking_id"`
+	SeatCode  string     `json:"seat_code"`
+	QRToken   string     `json:"qr_token"`
+	IssuedAt  *time.Time `json:"issued_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ExportReview struct {
+	ReviewID   int       `json:"review_id"`
+	MovieTitle string    `json:"movie_title"`
+	Rating     int       `json:"rating"`
+	Content    string    `json:"content"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type APIKeyResponse struct {
+	APIKeyID   int        `json:"api_key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	UserID     int        `json:"user_id"`
+	Scopes     []string   `json:"scopes"`
+	CinemaIDs  []int      `json:"cinema_ids"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiredAt  *time.Time `json:"expired_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty"`
+}
+
+type MovieRunResponse struct {
+	MovieRunID     int     `json:"movie_run_id"`
+	MovieID        int     `json:"movie_id"`
+	CinemaID       int     `json:"cinema_id"`
+	CinemaName     string  `json:"cinema_name,omitempty"`
+	Status         string  `json:"status"`
+	PresaleStartAt *string `json:"presale_start_at"`
+	StartDate      string  `json:"start_date"`
+	EndDate        *string `json:"end_date"`
+}
+
+type ComingSoonResponse struct {
+	Movie          MovieResponse `json:"movie"`
+	Status         string        `json:"status"`
+	PresaleStartAt *string       `json:"presale_start_at"`
+	OpeningDate    string        `json:"opening_date"`
+}
+
+type PersonResponse struct {
+	PersonID  int     `json:"person_id"`
+	Name      string  `json:"name"`
+	Biography *string `json:"biography"`
+	PhotoURL  *string `json:"photo_url"`
+	BirthDate *string `json:"birth_date"`
+}
+
+type CreditResponse struct {
+	PersonID      int     `json:"person_id"`
+	Name          string  `json:"name"`
+	PhotoURL      *string `json:"photo_url"`
+	Role          string  `json:"role"`
+	CharacterName *string `json:"character_name,omitempty"`
+	Order         int     `json:"billing_order"`
+}
+
+// Movies featuring a person, one row per credit
+type FilmographyResponse struct {
+	Movie         MovieResponse `json:"movie"`
+	Role          string        `json:"role"`
+	CharacterName *string       `json:"character_name,omitempty"`
+}
+
+type ReviewResponse struct {
+	ReviewID       int        `json:"review_id"`
+	MovieID        int        `json:"movie_id"`
+	MovieTitle     string     `json:"movie_title"`
+	UserName       string     `json:"user_name"`
+	Rating         int        `json:"rating"`
+	Content        string     `json:"content"`
+	Status         string     `json:"status,omitempty"`
+	ModerationNote *string    `json:"moderation_note,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type MediaResponse struct {
+	MediaID      int                     `json:"media_id"`
+	OriginalName string                  `json:"original_name"`
+	MimeType     string                  `json:"mime_type"`
+	Size         int64                   `json:"size_bytes"`
+	Width        int                     `json:"width"`
+	Height       int                     `json:"height"`
+	Variants     map[string]MediaVariant `json:"variants"`
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+type MediaVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Size   int64  `json:"size_bytes"`
+}
+
+type MediaLinkResponse struct {
+	Kind     string        `json:"kind"`
+	Position int           `json:"position"`
+	Media    MediaResponse `json:"media"`
+}
+
+type ImportReport struct {
+	Kind    string           `json:"kind"`
+	DryRun  bool             `json:"dry_run"`
+	Applied bool             `json:"applied"`
+	Total   int              `json:"total"`
+	Created int              `json:"created"`
+	Skipped int              `json:"skipped"`
+	Failed  int              `json:"failed"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// Row is the 1-based position of the record in the file, excluding the CSV header
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ScreeningFormatResponse struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Surcharge float64 `json:"surcharge"`
+}
+
+type CinemaClosureResponse struct {
+	ClosureID int     `json:"closure_id"`
+	CinemaID  int     `json:"cinema_id"`
+	Date      string  `json:"date"`
+	Reason    *string `json:"reason"`
+}
+
+type AmenityResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Showtimes of one movie across cinemas, grouped by cinema then format
+type MovieShowtimes struct {
+	Movie    MovieResponse     `json:"movie"`
+	DateFrom string            `json:"date_from"`
+	DateTo   string            `json:"date_to"`
+	Cinemas  []CinemaShowtimes `json:"cinemas"`
+}
+
+type CinemaShowtimes struct {
+	Cinema  CinemaResponse    `json:"cinema"`
+	Formats []FormatShowtimes `json:"formats"`
+}
+
+type FormatShowtimes struct {
+	Format    string             `json:"format"`
+	Showtimes []ShowtimeResponse `json:"showtimes"`
+}
+
+type ShowtimeResponse struct {
+	ScreeningID      int     `json:"screening_id"`
+	Date             string  `json:"date"`
+	StartTime        string  `json:"start_time"`
+	EndTime          string  `json:"end_time"`
+	Studio           string  `json:"studio"`
+	AudioLanguage    *string `json:"audio_language,omitempty"`
+	SubtitleLanguage *string `json:"subtitle_language,omitempty"`
+	AudioDescription bool    `json:"audio_description"`
+	ClosedCaptions   bool    `json:"closed_captions"`
+	Price            float64 `json:"price"`
+	SeatsTotal       int     `json:"seats_total"`
+	SeatsAvailable   int     `json:"seats_available"`
+	Availability     string  `json:"availability"`
+}
+
+type WaitlistResponse struct {
+	WaitlistID     int        `json:"waitlist_id"`
+	ScreeningID    int        `json:"screening_id"`
+	MovieTitle     string     `json:"movie_title"`
+	CinemaName     string     `json:"cinema_name"`
+	Date           string     `json:"date"`
+	StartTime      string     `json:"start_time"`
+	SeatCount      int        `json:"seat_count"`
+	Status         string     `json:"status"`
+	Position       *int       `json:"position,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WaitlistOfferEmail struct {
+	Name           string `json:"name"`
+	ScreeningID    int    `json:"screening_id"`
+	MovieTitle     string `json:"movie_title"`
+	CinemaName     string `json:"cinema_name"`
+	Date           string `json:"date"`
+	StartTime      string `json:"start_time"`
+	SeatCount      int    `json:"seat_count"`
+	OfferExpiresAt string `json:"offer_expires_at"`
+	Locale         string `json:"-"`
+}
+
+// Seats and screening_id can be posted to /bookings as they are to hold the block
+type SeatSuggestion struct {
+	ScreeningID int            `json:"screening_id"`
+	PartySize   int            `json:"party_size"`
+	Seats       []int          `json:"seats"`
+	SeatCodes   []string       `json:"seat_codes"`
+	Details     []SeatResponse `json:"seat_details"`
+	Score       float64        `json:"score"`
+}
+
+// Positive price differences are charged, negative ones refunded
+type SeatSwapResponse struct {
+	Booking         BookingResponse    `json:"booking"`
+	Released        []int              `json:"released"`
+	Held            []int              `json:"held"`
+	PriceDifference float64            `json:"price_difference"`
+	Adjustment      *PaymentAdjustment `json:"adjustment,omitempty"`
+}
+
+type PaymentAdjustment struct {
+	PaymentID int     `json:"payment_id"`
+	Type      string  `json:"type"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+}
+
+type ExchangeResponse struct {
+	Booking         BookingResponse    `json:"booking"`
+	ExchangedFrom   int                `json:"exchanged_from"`
+	PriceDifference float64            `json:"price_difference"`
+	Fee             float64            `json:"fee"`
+	AmountDue       float64            `json:"amount_due"`
+	Adjustment      *PaymentAdjustment `json:"adjustment,omitempty"`
+}
+
+type TicketResponse struct {
+	TicketID    int        `json:"ticket_id"`
+	BookingID   int        `json:"booking_id"`
+	ScreeningID int        `json:"screening_id"`
+	MovieTitle  string     `json:"movie_title"`
+	CinemaName  string     `json:"cinema_name"`
+	StudioName  string     `json:"studio_name"`
+	Date        string     `json:"date"`
+	StartTime   string     `json:"start_time"`
+	SeatCode    string     `json:"seat_code"`
+	QRToken     string     `json:"qr_token"`
+	Received    bool       `json:"received"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+type TransferResponse struct {
+	TransferID  int       `json:"transfer_id"`
+	TicketID    int       `json:"ticket_id"`
+	BookingID   int       `json:"booking_id"`
+	ScreeningID int       `json:"screening_id"`
+	MovieTitle  string    `json:"movie_title"`
+	CinemaName  string    `json:"cinema_name"`
+	Date        string    `json:"date"`
+	StartTime   string    `json:"start_time"`
+	SeatCode    string    `json:"seat_code"`
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Incoming    bool      `json:"incoming"`
+	Status      string    `json:"status"`
+	NewTicketID *int      `json:"new_ticket_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TransferEmail struct {
+	Name       string `json:"name"`
+	FromName   string `json:"from_name"`
+	MovieTitle string `json:"movie_title"`
+	CinemaName string `json:"cinema_name"`
+	Date       string `json:"date"`
+	StartTime  string `json:"start_time"`
+	SeatCode   string `json:"seat_code"`
+	Locale     string `json:"-"`
+}
+
+type BoxOfficeSaleResponse struct {
+	BookingID     int              `json:"booking_id"`
+	ReceiptNumber string           `json:"receipt_number"`
+	MovieTitle    string           `json:"movie_title"`
+	CinemaName    string           `json:"cinema_name"`
+	StudioName    string           `json:"studio_name"`
+	Format        string           `json:"format"`
+	Date          string           `json:"date"`
+	StartTime     string           `json:"start_time"`
+	CustomerPhone *string          `json:"customer_phone,omitempty"`
+	Cashier       string           `json:"cashier"`
+	Price         float64          `json:"price"`
+	Tickets       []Ticket         `json:"tickets"`
+	Payment       BoxOfficePayment `json:"payment"`
+	ReceiptURL    string           `json:"receipt_url"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type BoxOfficePayment struct {
+	PaymentID    int      `json:"payment_id"`
+	Method       string   `json:"method"`
+	Amount       float64  `json:"amount"`
+	Tendered     *float64 `json:"tendered,omitempty"`
+	Change       *float64 `json:"change,omitempty"`
+	ApprovalCode *string  `json:"approval_code,omitempty"`
+	Status       string   `json:"status"`
+}
+
+// Cash reconciliation of a shift, expected cash is the opening float plus cash sales
+type ShiftReport struct {
+	ShiftID      int        `json:"shift_id"`
+	CashierID    int        `json:"cashier_id"`
+	Cashier      string     `json:"cashier"`
+	CinemaID     *int       `json:"cinema_id,omitempty"`
+	Cinema       *string    `json:"cinema,omitempty"`
+	OpenedAt     time.Time  `json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	Bookings     int        `json:"bookings"`
+	Tickets      int        `json:"tickets"`
+	OpeningFloat float64    `json:"opening_float"`
+	CashSales    float64    `json:"cash_sales"`
+	EDCSales     float64    `json:"edc_sales"`
+	TotalSales   float64    `json:"total_sales"`
+	ExpectedCash float64    `json:"expected_cash"`
+	CountedCash  *float64   `json:"counted_cash,omitempty"`
+	Variance     *float64   `json:"variance,omitempty"`
+	Note         *string    `json:"note,omitempty"`
+}
+
+// Shift totals of one cashier over the reported day
+type CashierSummary struct {
+	CashierID  int     `json:"cashier_id"`
+	Cashier    string  `json:"cashier"`
+	Shifts     int     `json:"shifts"`
+	Bookings   int     `json:"bookings"`
+	Tickets    int     `json:"tickets"`
+	CashSales  float64 `json:"cash_sales"`
+	EDCSales   float64 `json:"edc_sales"`
+	TotalSales float64 `json:"total_sales"`
+	Variance   float64 `json:"variance"`
+	OpenShifts int     `json:"open_shifts"`
+}
+
+type ShiftReportResponse struct {
+	Date     string           `json:"date"`
+	Cashiers []CashierSummary `json:"cashiers"`
+	Shifts   []ShiftReport    `json:"shifts"`
+}
+
+// Tax invoice of a booking, line prices include tax
+type Invoice struct {
+	Number        string        `json:"number"`
+	IssuedAt      string        `json:"issued_at"`
+	PaymentMethod string        `json:"payment_method"`
+	Lines         []InvoiceLine `json:"lines"`
+	Subtotal      float64       `json:"subtotal"`
+	TaxRate       float64       `json:"tax_rate"`
+	Tax           float64       `json:"tax"`
+	Total         float64       `json:"total"`
+}
+
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Amount      float64 `json:"amount"`
+}
+
+type BookingDocument struct {
+	Kind     string `json:"kind"`
+	FileName string `json:"file_name"`
+	URL      string `json:"url"`
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	"context"
 	"errors"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/project-app-bioskop-golang/internal/data/entity"
@@ -11,9 +13,16 @@ import (
 	"go.uber.org/zap"
 )
 
-func (mw *MiddlewareCustom) AuthMiddleware() func(http.Handler) http.Handler {
+// AuthMiddleware accepts a login session or an API key. API keys are only
+// accepted on routes that list at least one of the key's scopes.
+func (mw *MiddlewareCustom) AuthMiddleware(scopes ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get("X-API-Key"); key != "" {
+				mw.apiKeyAuth(w, r, next, key, scopes)
+				return
+			}
+
 			auth := r.Header.Get("Authorization")
 			token := strings.TrimSpace(strings.Replace(auth, "Bearer", "", 1))
 			userID, err := mw.Usecase.AuthUsecase.ValidateToken(token)
@@ -34,6 +43,43 @@ func (mw *MiddlewareCustom) AuthMiddleware() func(http.Handler) http.Handler {
 	}
 }
 
+func (mw *MiddlewareCustom) apiKeyAuth(w http.ResponseWriter, r *http.Request, next http.Handler, key string, scopes []string) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	apiKey, err := mw.Usecase.APIKeyUsecase.Authenticate(key, ip)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusUnauthorized, "invalid api key", err.Error())
+		return
+	}
+
+	// Check scope
+	isAllowed := false
+	for _, scope := range scopes {
+		if slices.Contains(apiKey.Scopes, scope) {
+			isAllowed = true
+			break
+		}
+	}
+
+	if !isAllowed {
+		utils.ResponseFailed(w, http.StatusForbidden, "invalid scope", "api key is not allowed for this route")
+		return
+	}
+
+	user, err := mw.Usecase.UserUsecase.GetByID(apiKey.UserID)
+	if err != nil {
+		utils.ResponseFailed(w, http.StatusUnauthorized, "user not found", err)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), "user", user)
+	ctx = context.WithValue(ctx, "api_key", *apiKey)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
 func (middlewareCostume *MiddlewareCustom) RequirePermission(roles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -61,3 +107,35 @@ func (middlewareCostume *MiddlewareCustom) RequirePermission(roles ...string) fu
 		})
 	}
 }
+
+func (mw *MiddlewareCustom) RequireTwoFactor() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value("user").(entity.User)
+			if !ok {
+				mw.Log.Error("Error retrieve user info", zap.Error(errors.New("error retrieve user")))
+				utils.ResponseFailed(w, http.StatusUnauthorized, "invalid user", errors.New("error retrieve user"))
+				return
+			}
+
+			// Policy only applies to roles that must enrol
+			if !mw.Usecase.TwoFactorUsecase.IsRequired(user) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			enabled, err := mw.Usecase.TwoFactorUsecase.IsEnabled(user.ID)
+			if err != nil {
+				utils.ResponseFailed(w, http.StatusInternalServerError, "check two factor failed", err.Error())
+				return
+			}
+
+			if !enabled {
+				utils.ResponseFailed(w, http.StatusForbidden, "two factor authentication required", "enable two factor authentication at /api/v1/me/2fa")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
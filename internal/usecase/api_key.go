@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+var errAPIKeyCinema = errors.New("api key is not allowed for this cinema")
+
+type APIKeyUsecase interface {
+	Create(createdBy int, data dto.APIKeyRequest) (*dto.APIKeyResponse, error)
+	GetAll() ([]dto.APIKeyResponse, error)
+	GetByID(id int) (*dto.APIKeyResponse, error)
+	Rotate(id int) (*dto.APIKeyResponse, error)
+	Revoke(id int) error
+	Authenticate(key string, ip string) (*entity.APIKey, error)
+	AllowScreening(key entity.APIKey, screeningID int) error
+	AllowBooking(key entity.APIKey, bookingID int) error
+}
+
+type apiKeyUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+}
+
+func NewAPIKeyUsecase(repo *repository.Repository, log *zap.Logger) APIKeyUsecase {
+	return &apiKeyUsecase{
+		Repo:   repo,
+		Logger: log,
+	}
+}
+
+func (u *apiKeyUsecase) Create(createdBy int, data dto.APIKeyRequest) (*dto.APIKeyResponse, error) {
+	// Key acts on behalf of an existing account
+	_, err := u.Repo.UserRepo.GetByID(data.UserID)
+	if err != nil {
+		u.Logger.Error("Error get user usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	if data.ExpiredAt != nil && data.ExpiredAt.Before(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		u.Logger.Error("Error generate api key: ", zap.Error(err))
+		return nil, err
+	}
+
+	newKey := entity.APIKey{
+		Name:       data.Name,
+		Prefix:     prefix,
+		KeyHash:    utils.HashAPIKey(key),
+		UserID:     data.UserID,
+		Scopes:     data.Scopes,
+		CinemaIDs:  data.CinemaIDs,
+		AllowedIPs: data.AllowedIPs,
+		ExpiredAt:  data.ExpiredAt,
+		CreatedBy:  &createdBy,
+	}
+	if newKey.CinemaIDs == nil {
+		newKey.CinemaIDs = []int{}
+	}
+	if newKey.AllowedIPs == nil {
+		newKey.AllowedIPs = []string{}
+	}
+
+	created, err := u.Repo.APIKeyRepo.Create(newKey)
+	if err != nil {
+		u.Logger.Error("Error create api key usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Plain key is only shown once
+	res := toAPIKeyResponse(*created)
+	res.Key = key
+	return &res, nil
+}
+
+func (u *apiKeyUsecase) GetAll() ([]dto.APIKeyResponse, error) {
+	keys, err := u.Repo.APIKeyRepo.GetAll()
+	if err != nil {
+		u.Logger.Error("Error get api keys usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var res []dto.APIKeyResponse
+	for _, k := range keys {
+		res = append(res, toAPIKeyResponse(k))
+	}
+	return res, nil
+}
+
+func (u *apiKeyUsecase) GetByID(id int) (*dto.APIKeyResponse, error) {
+	key, err := u.Repo.APIKeyRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get api key usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	res := toAPIKeyResponse(*key)
+	return &res, nil
+}
+
+func (u *apiKeyUsecase) Rotate(id int) (*dto.APIKeyResponse, error) {
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		u.Logger.Error("Error generate api key: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = u.Repo.APIKeyRepo.Rotate(id, prefix, utils.HashAPIKey(key))
+	if err != nil {
+		u.Logger.Error("Error rotate api key usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	rotated, err := u.Repo.APIKeyRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get api key usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	res := toAPIKeyResponse(*rotated)
+	res.Key = key
+	return &res, nil
+}
+
+func (u *apiKeyUsecase) Revoke(id int) error {
+	err := u.Repo.APIKeyRepo.Revoke(id)
+	if err != nil {
+		u.Logger.Error("Error revoke api key usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *apiKeyUsecase) Authenticate(key string, ip string) (*entity.APIKey, error) {
+	invalid := errors.New("invalid api key")
+
+	prefix, ok := utils.APIKeyPrefix(key)
+	if !ok {
+		return nil, invalid
+	}
+
+	apiKey, err := u.Repo.APIKeyRepo.GetByPrefix(prefix)
+	if err != nil {
+		return nil, invalid
+	}
+
+	if !utils.CheckAPIKey(key, apiKey.KeyHash) {
+		return nil, invalid
+	}
+
+	if apiKey.ExpiredAt != nil && apiKey.ExpiredAt.Before(time.Now()) {
+		return nil, errors.New("api key has expired")
+	}
+
+	if !utils.IPAllowed(ip, apiKey.AllowedIPs) {
+		return nil, errors.New("ip address is not allowed for this api key")
+	}
+
+	if err := u.Repo.APIKeyRepo.UpdateLastUsed(apiKey.ID); err != nil {
+		u.Logger.Error("Error update api key last used: ", zap.Error(err))
+	}
+
+	return apiKey, nil
+}
+
+func (u *apiKeyUsecase) AllowScreening(key entity.APIKey, screeningID int) error {
+	// Empty cinema list means every cinema
+	if len(key.CinemaIDs) == 0 {
+		return nil
+	}
+
+	cinemaID, err := u.Repo.APIKeyRepo.GetCinemaByScreening(screeningID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(key.CinemaIDs, cinemaID) {
+		return errAPIKeyCinema
+	}
+	return nil
+}
+
+func (u *apiKeyUsecase) AllowBooking(key entity.APIKey, bookingID int) error {
+	if len(key.CinemaIDs) == 0 {
+		return nil
+	}
+
+	cinemaID, err := u.Repo.APIKeyRepo.GetCinemaByBooking(bookingID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(key.CinemaIDs, cinemaID) {
+		return errAPIKeyCinema
+	}
+	return nil
+}
+
+func toAPIKeyResponse(k entity.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		APIKeyID:   k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		UserID:     k.UserID,
+		Scopes:     k.Scopes,
+		CinemaIDs:  k.CinemaIDs,
+		AllowedIPs: k.AllowedIPs,
+		ExpiredAt:  k.ExpiredAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"errors"
+	"time"
 
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
@@ -10,11 +11,21 @@ import (
 	"go.uber.org/zap"
 )
 
+// Wrong codes allowed per login challenge
+const maxTwoFactorAttempts = 5
+
+// Wrong codes allowed per user across all challenges within the lock window
+const (
+	maxTwoFactorFailures = 10
+	twoFactorLockWindow  = 15 * time.Minute
+)
+
 type AuthUsecase interface {
 	Login(email, password string) (*dto.AuthResponse, error)
-	Register(data dto.RegisterRequest) (*dto.OTPResponse, error)
+	VerifyTwoFactor(data dto.TwoFactorLoginRequest) (*dto.AuthResponse, error)
+	Register(data dto.RegisterRequest, locale string) (*dto.OTPResponse, error)
 	VerifyOTP(dto.OTPRequest) (*dto.AuthResponse, error)
-	ResendOTP(email string) (*string, error)
+	ResendOTP(email string) error
 	ValidateToken(token string) (*int, error)
 	Logout(token string) error
 }
@@ -54,23 +65,72 @@ func (u *authUsecase) Login(email, password string) (*dto.AuthResponse, error) {
 		return nil, errors.New("incorrect password")
 	}
 
+	return startLogin(u.Repo, u.Logger, u.Config, *user)
+}
+
+func (u *authUsecase) VerifyTwoFactor(data dto.TwoFactorLoginRequest) (*dto.AuthResponse, error) {
+	// Find pending challenge
+	challenge, err := u.Repo.TwoFactorRepo.GetChallenge(data.ChallengeToken)
+	if err != nil {
+		u.Logger.Error("Error get two factor challenge: ", zap.Error(err))
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	if challenge.Attempts >= maxTwoFactorAttempts {
+		return nil, errors.New("too many attempts, please login again")
+	}
+
+	// New challenges must not reset the guess budget of the account
+	failures, err := u.Repo.TwoFactorRepo.CountRecentFailures(challenge.UserID, time.Now().Add(-twoFactorLockWindow))
+	if err != nil {
+		u.Logger.Error("Error count two factor failures: ", zap.Error(err))
+		return nil, err
+	}
+	if failures >= maxTwoFactorFailures {
+		return nil, errors.New("too many attempts, please try again later")
+	}
+
+	// Check authenticator or recovery code
+	err = verifySecondFactor(u.Repo, u.Logger, challenge.UserID, data.Code)
+	if err != nil {
+		if errAttempt := u.Repo.TwoFactorRepo.AddChallengeAttempt(challenge.ID); errAttempt != nil {
+			u.Logger.Error("Error update two factor challenge: ", zap.Error(errAttempt))
+		}
+		return nil, err
+	}
+
+	used, err := u.Repo.TwoFactorRepo.UseChallenge(challenge.ID)
+	if err != nil {
+		u.Logger.Error("Error use two factor challenge: ", zap.Error(err))
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	user, err := u.Repo.UserRepo.GetByID(challenge.UserID)
+	if err != nil {
+		u.Logger.Error("Error get user: ", zap.Error(err))
+		return nil, err
+	}
+
 	// Record session
 	token, err := u.Repo.SessionRepo.Create(user.ID)
 	if err != nil {
 		u.Logger.Error("Error create token: ", zap.Error(err))
 		return nil, errors.New("token error")
 	}
-	
+
 	res := dto.AuthResponse{
 		Name: user.Name,
 		Email: user.Email,
-		Token: token,
+		Token: &token,
 	}
 
 	return &res, nil
 }
 
-func (u *authUsecase) Register(data dto.RegisterRequest) (*dto.OTPResponse, error) {
+func (u *authUsecase) Register(data dto.RegisterRequest, locale string) (*dto.OTPResponse, error) {
 	// Check if email is registered
 	user, err := u.Repo.UserRepo.FindByEmail(data.Email)
 	if user != nil {
@@ -84,6 +144,7 @@ func (u *authUsecase) Register(data dto.RegisterRequest) (*dto.OTPResponse, erro
 		Email: data.Email,
 		Password: &passwordHashed,
 		Role: "customer",
+		Locale: locale,
 	}
 	
 	// Execute create user
@@ -122,9 +183,9 @@ func (u *authUsecase) Register(data dto.RegisterRequest) (*dto.OTPResponse, erro
 	}
 
 	// Format email content
-	body := utils.SendOTP(res)
+	body := utils.SendOTP(res, locale)
 	to := user.Email
-	subject := "Email Verification"
+	subject := utils.T(locale, "otp.subject")
 	content := dto.EmailRequest{
 		To: to,
 		Subject: subject,
@@ -142,16 +203,37 @@ func (u *authUsecase) Register(data dto.RegisterRequest) (*dto.OTPResponse, erro
 }
 
 func (u *authUsecase) VerifyOTP(data dto.OTPRequest) (*dto.AuthResponse, error) {
+	if data.OTP == nil {
+		return nil, errors.New("otp is required")
+	}
+
+	// Get user
+	user, err := u.Repo.UserRepo.FindByEmail(data.Email)
+	if err != nil {
+		u.Logger.Error("Error find user by email: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Codes only activate new registrations, they never replace a login
+	verified, err := u.Repo.IdentityRepo.IsEmailVerified(user.Email)
+	if err != nil {
+		u.Logger.Error("Error check email verified: ", zap.Error(err))
+		return nil, err
+	}
+	if verified {
+		return nil, errors.New("email already verified")
+	}
+
 	// Find OTP by email
 	otpHash, err := u.Repo.OTPRepo.Find(data.Email)
 	if err != nil {
 		u.Logger.Error("Error find OTP: ", zap.Error(err))
-		return nil, err
+		return nil, errors.New("invalid OTP")
 	}
 
 	// Compare OTP
 	if !utils.CheckPassword(*data.OTP, *otpHash) {
-		u.Logger.Error("Invalid OTP: ", zap.Error(err))
+		u.Logger.Error("Invalid OTP: ", zap.Error(errors.New("invalid OTP")))
 		return nil, errors.New("invalid OTP")
 	}
 
@@ -162,42 +244,38 @@ func (u *authUsecase) VerifyOTP(data dto.OTPRequest) (*dto.AuthResponse, error)
 		return nil, err
 	}
 
-	// Get user
-	user, err := u.Repo.UserRepo.FindByEmail(data.Email)
-	if err == utils.ErrNotFound("user") {
-		u.Logger.Error("User not found: ", zap.Error(err))
-		return nil, err
+	return startLogin(u.Repo, u.Logger, u.Config, *user)
+}
+
+func (u *authUsecase) ResendOTP(email string) error {
+	user, err := u.Repo.UserRepo.FindByEmail(email)
+	if err != nil {
+		u.Logger.Error("Error find user by email: ", zap.Error(err))
+		return err
 	}
 
-	// Record session
-	token, err := u.Repo.SessionRepo.Create(user.ID)
+	// Verified accounts sign in with their password
+	verified, err := u.Repo.IdentityRepo.IsEmailVerified(user.Email)
 	if err != nil {
-		u.Logger.Error("Error create token: ", zap.Error(err))
-		return nil, errors.New("token error")
+		u.Logger.Error("Error check email verified: ", zap.Error(err))
+		return err
 	}
-	
-	res := dto.AuthResponse{
-		Name: user.Name,
-		Email: user.Email,
-		Token: token,
+	if verified {
+		return errors.New("email already verified")
 	}
 
-	return &res, nil
-}
-
-func (u *authUsecase) ResendOTP(email string) (*string, error) {
 	// Generate OTP
 	otpStr, err := utils.GenerateOTP(6)
 	if err != nil {
 		u.Logger.Error("Error generate OTP: ", zap.Error(err))
-		return nil, err
+		return err
 	}
 
 	// Hash OTP
 	otpHash := utils.HashPassword(otpStr)
 
 	otp := entity.OTP{
-		Email: email,
+		Email: user.Email,
 		OTPHash: otpHash,
 	}
 
@@ -205,13 +283,7 @@ func (u *authUsecase) ResendOTP(email string) (*string, error) {
 	err = u.Repo.OTPRepo.Create(otp)
 	if err != nil {
 		u.Logger.Error("Error insert OTP: ", zap.Error(err))
-		return nil, err
-	}
-
-	user, err := u.Repo.UserRepo.FindByEmail(email)
-	if err == utils.ErrNotFound("user") {
-		u.Logger.Error("User not found: ", zap.Error(err))
-		return nil, err
+		return err
 	}
 
 	res := dto.OTPResponse{
@@ -221,23 +293,24 @@ func (u *authUsecase) ResendOTP(email string) (*string, error) {
 	}
 
 	// Format email content
-	body := utils.SendOTP(res)
+	locale := userLocale(*user, u.Config)
+	body := utils.SendOTP(res, locale)
 	to := user.Email
-	subject := "Email Verification"
+	subject := utils.T(locale, "otp.subject")
 	content := dto.EmailRequest{
 		To: to,
 		Subject: subject,
 		Body: body,
 	}
 
-	// Send OTP
+	// Send OTP, the code only ever goes to the inbox
 	u.emailJobs <- utils.EmailJob{
 		EmailContent: content,
 		Config: u.Config,
 		Log: u.Logger,
 	}
 
-	return &otpStr, nil
+	return nil
 }
 
 func (u *authUsecase) ValidateToken(token string) (*int, error) {
@@ -258,4 +331,46 @@ func (u *authUsecase) Logout(token string) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// startLogin finishes a login once the first factor is accepted. It returns a
+// 2FA challenge when the user has 2FA enabled, otherwise a new session.
+func startLogin(repo *repository.Repository, log *zap.Logger, config utils.Configuration, user entity.User) (*dto.AuthResponse, error) {
+	// Second login step when 2FA is enabled
+	enabled, err := isTwoFactorEnabled(repo, log, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		challenge, err := repo.TwoFactorRepo.CreateChallenge(user.ID)
+		if err != nil {
+			log.Error("Error create two factor challenge: ", zap.Error(err))
+			return nil, errors.New("token error")
+		}
+
+		res := dto.AuthResponse{
+			Name: user.Name,
+			Email: user.Email,
+			TwoFactorRequired: true,
+			ChallengeToken: &challenge,
+		}
+		return &res, nil
+	}
+
+	// Record session
+	token, err := repo.SessionRepo.Create(user.ID)
+	if err != nil {
+		log.Error("Error create token: ", zap.Error(err))
+		return nil, errors.New("token error")
+	}
+	
+	res := dto.AuthResponse{
+		Name: user.Name,
+		Email: user.Email,
+		Token: &token,
+		TwoFactorSetupRequired: config.RequireAdmin2FA && user.Role == "admin",
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Fakes only implement what the registration OTP flow uses, anything else
+// panics through the nil embedded interface
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	user entity.User
+}
+
+func (f *fakeUserRepo) FindByEmail(email string) (*entity.User, error) {
+	if email != f.user.Email {
+		return nil, utils.ErrNotFound("user")
+	}
+	user := f.user
+	return &user, nil
+}
+
+type fakeIdentityRepo struct {
+	repository.IdentityRepository
+	verified bool
+}
+
+func (f *fakeIdentityRepo) IsEmailVerified(email string) (bool, error) {
+	return f.verified, nil
+}
+
+type fakeOTPRepo struct {
+	repository.OTPRepository
+	hash string
+	used bool
+}
+
+func (f *fakeOTPRepo) Find(email string) (*string, error) {
+	if f.used {
+		return nil, errors.New("no rows in result set")
+	}
+	return &f.hash, nil
+}
+
+func (f *fakeOTPRepo) Update(otp string) error {
+	if f.used {
+		return errors.New("invalid OTP")
+	}
+	f.used = true
+	return nil
+}
+
+type fakeTwoFactorRepo struct {
+	repository.TwoFactorRepository
+	enabled    bool
+	challenges int
+}
+
+func (f *fakeTwoFactorRepo) GetTOTP(userID int) (*entity.UserTOTP, error) {
+	if !f.enabled {
+		return nil, utils.ErrNotFound("two factor")
+	}
+	now := time.Now()
+	return &entity.UserTOTP{UserID: userID, EnabledAt: &now}, nil
+}
+
+func (f *fakeTwoFactorRepo) CreateChallenge(userID int) (uuid.UUID, error) {
+	f.challenges++
+	return uuid.New(), nil
+}
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	sessions int
+}
+
+func (f *fakeSessionRepo) Create(userID int) (uuid.UUID, error) {
+	f.sessions++
+	return uuid.New(), nil
+}
+
+func TestVerifyOTP(t *testing.T) {
+	code := "123456"
+
+	tests := []struct {
+		name          string
+		role          string
+		twoFactor     bool
+		verified      bool
+		otp           string
+		wantErr       bool
+		wantChallenge bool
+		wantSession   bool
+	}{
+		{name: "two factor user gets a challenge", role: "admin", twoFactor: true, otp: code, wantChallenge: true},
+		{name: "user without two factor gets a session", role: "customer", otp: code, wantSession: true},
+		{name: "verified account cannot log in with a code", role: "admin", twoFactor: true, verified: true, otp: code, wantErr: true},
+		{name: "wrong code", role: "customer", otp: "654321", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			twoFactor := &fakeTwoFactorRepo{enabled: tt.twoFactor}
+			sessions := &fakeSessionRepo{}
+			otps := &fakeOTPRepo{hash: utils.HashPassword(code)}
+			repo := &repository.Repository{
+				UserRepo:      &fakeUserRepo{user: entity.User{Model: entity.Model{ID: 7}, Name: "Admin", Email: "admin@example.com", Role: tt.role}},
+				IdentityRepo:  &fakeIdentityRepo{verified: tt.verified},
+				OTPRepo:       otps,
+				TwoFactorRepo: twoFactor,
+				SessionRepo:   sessions,
+			}
+			u := NewAuthUsecase(repo, zap.NewNop(), nil, utils.Configuration{})
+
+			otp := tt.otp
+			res, err := u.VerifyOTP(dto.OTPRequest{Email: "admin@example.com", OTP: &otp})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("VerifyOTP() expected error, got %+v", res)
+				}
+				if sessions.sessions != 0 || twoFactor.challenges != 0 {
+					t.Errorf("VerifyOTP() failed but created %d sessions and %d challenges", sessions.sessions, twoFactor.challenges)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyOTP() unexpected error: %v", err)
+			}
+
+			if res.TwoFactorRequired != tt.wantChallenge || (res.ChallengeToken != nil) != tt.wantChallenge {
+				t.Errorf("VerifyOTP() two factor required = %v, challenge = %v, want %v", res.TwoFactorRequired, res.ChallengeToken, tt.wantChallenge)
+			}
+			if (res.Token != nil) != tt.wantSession || (sessions.sessions == 1) != tt.wantSession {
+				t.Errorf("VerifyOTP() token = %v with %d sessions, want session %v", res.Token, sessions.sessions, tt.wantSession)
+			}
+			if !otps.used {
+				t.Errorf("VerifyOTP() did not use the code")
+			}
+		})
+	}
+}
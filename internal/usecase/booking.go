@@ -2,7 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 
+	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/utils"
@@ -10,31 +14,42 @@ import (
 )
 
 type BookingUsecase interface {
-	Create(b dto.BookingRequest) (*dto.BookingResponse, error)
+	Create(b dto.BookingRequest, locale string) (*dto.BookingResponse, error)
 	GetBookingHistory(ctx context.Context, q dto.PaginationQuery) ([]dto.BookingHistory, *dto.Pagination, error)
-	GetByID(id int) (*dto.BookingResponse, error)
+	GetByID(id int, locale string) (*dto.BookingResponse, error)
+	Cancel(id int, userID int) error
+	SwapSeats(ctx context.Context, id int, data dto.SeatSwapRequest, locale string) (*dto.SeatSwapResponse, error)
+	Exchange(ctx context.Context, id int, data dto.ExchangeRequest, locale string) (*dto.ExchangeResponse, error)
+	GetDocuments(ctx context.Context, id int) ([]dto.BookingDocument, error)
+	GetDocument(ctx context.Context, id int, kind string, locale string) ([]byte, string, error)
 }
 
 type bookingUsecase struct {
 	repo *repository.Repository
 	Logger *zap.Logger
+	waitlist WaitlistUsecase
+	payment PaymentUsecase
+	Config utils.Configuration
 }
 
-func NewBookingUsecase(repo *repository.Repository, log *zap.Logger) BookingUsecase {
+func NewBookingUsecase(repo *repository.Repository, log *zap.Logger, waitlist WaitlistUsecase, payment PaymentUsecase, config utils.Configuration) BookingUsecase {
 	return &bookingUsecase{
 		repo: repo,
 		Logger: log,
+		waitlist: waitlist,
+		payment: payment,
+		Config: config,
 	}
 }
 
-func (u *bookingUsecase) Create(b dto.BookingRequest) (*dto.BookingResponse, error) {
+func (u *bookingUsecase) Create(b dto.BookingRequest, locale string) (*dto.BookingResponse, error) {
 	booking, err := u.repo.BookingRepo.Create(b)
 	if err != nil {
 		u.Logger.Error("Error create booking usecase: ", zap.Error(err))
 		return nil, err
 	}
 
-	response, err := u.GetByID(booking.ID)
+	response, err := u.GetByID(booking.ID, locale)
 	if err != nil {
 		u.Logger.Error("Error get booking by id usecase: ", zap.Error(err))
 		return nil, err
@@ -42,7 +57,7 @@ func (u *bookingUsecase) Create(b dto.BookingRequest) (*dto.BookingResponse, err
 	return response, nil
 }
 
-func (u *bookingUsecase) GetByID(id int) (*dto.BookingResponse, error) {
+func (u *bookingUsecase) GetByID(id int, locale string) (*dto.BookingResponse, error) {
 	b, err := u.repo.BookingRepo.GetByID(id)
 	if err != nil {
 		u.Logger.Error("Error get booking by id usecase: ", zap.Error(err))
@@ -54,6 +69,8 @@ func (u *bookingUsecase) GetByID(id int) (*dto.BookingResponse, error) {
 	studio, err := u.repo.StudioRepo.GetByID(screening.StudioID)
 	cinema, err := u.repo.CinemaRepo.GetByID(studio.CinemaID)
 	seats, err := u.repo.SeatRepo.GetSeatsByBookingID(b.ID)
+	localizeMovie(u.repo, locale, movie)
+	localizeCinema(u.repo, locale, cinema)
 	
 	response := dto.BookingResponse{
 		BookingID: b.ID,
@@ -62,18 +79,115 @@ func (u *bookingUsecase) GetByID(id int) (*dto.BookingResponse, error) {
 		Studio: dto.StudioResponse{
 			StudioID: studio.ID,
 			Name: studio.Name,
-			Type: studio.Type,
+			Type: localizeStudioType(studioTypeNames(u.repo, locale), studio.Type),
 			Price: studio.Price,
 		},
 		Cinema: *cinema,
 		Seats: seats,
 		Status: b.Status,
-		TotalAmount: studio.Price * float64(len(seats)),
+		TotalAmount: screening.Price * float64(len(seats)),
 		ExpiredAt: b.ExpiredAt,
+		ExchangedFrom: b.ExchangedFromID,
+		ExchangedAt: b.ExchangedAt,
 	}
 	return &response, err
 }
 
+func (u *bookingUsecase) Cancel(id int, userID int) error {
+	screeningID, err := u.repo.BookingRepo.Cancel(id, userID)
+	if err != nil {
+		u.Logger.Error("Error cancel booking usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Released seats go to the waitlist first
+	u.waitlist.OfferSeats(screeningID)
+	return nil
+}
+
+func (u *bookingUsecase) SwapSeats(ctx context.Context, id int, data dto.SeatSwapRequest, locale string) (*dto.SeatSwapResponse, error) {
+	if len(data.Release) == 0 && len(data.Hold) == 0 {
+		return nil, errors.New("release or hold at least one seat")
+	}
+
+	user := ctx.Value("user").(entity.User)
+	swap, err := u.repo.BookingRepo.SwapSeats(id, user.ID, data.Release, data.Hold)
+	if err != nil {
+		u.Logger.Error("Error swap seats usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Released seats go to the waitlist first
+	if len(swap.Released) > 0 {
+		u.waitlist.OfferSeats(swap.ScreeningID)
+	}
+
+	booking, err := u.GetByID(id, locale)
+	if err != nil {
+		u.Logger.Error("Error get booking by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Reissued tickets replace the ones sent before
+	if booking.Status == "paid" && len(swap.Held) > 0 {
+		u.payment.SendTicket(ctx, id)
+	}
+
+	response := dto.SeatSwapResponse{
+		Booking: *booking,
+		Released: swap.Released,
+		Held: swap.Held,
+		PriceDifference: swap.PriceDifference,
+	}
+	if a := swap.Adjustment; a != nil {
+		response.Adjustment = &dto.PaymentAdjustment{
+			PaymentID: a.ID,
+			Type: a.Type,
+			Amount: math.Abs(swap.PriceDifference),
+			Status: a.Status,
+		}
+	}
+	return &response, nil
+}
+
+func (u *bookingUsecase) Exchange(ctx context.Context, id int, data dto.ExchangeRequest, locale string) (*dto.ExchangeResponse, error) {
+	user := ctx.Value("user").(entity.User)
+	exchange, err := u.repo.BookingRepo.Exchange(id, user.ID, data.ScreeningID, data.Seats, u.Config.ExchangeFee)
+	if err != nil {
+		u.Logger.Error("Error exchange booking usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Seats of the old booking go to the waitlist first, the new tickets replace its ones
+	if exchange.Completed {
+		u.waitlist.OfferSeats(exchange.PreviousScreeningID)
+		u.payment.SendTicket(ctx, exchange.BookingID)
+	}
+
+	booking, err := u.GetByID(exchange.BookingID, locale)
+	if err != nil {
+		u.Logger.Error("Error get booking by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := dto.ExchangeResponse{
+		Booking: *booking,
+		ExchangedFrom: exchange.ExchangedFromID,
+		PriceDifference: exchange.PriceDifference,
+		Fee: exchange.Fee,
+		AmountDue: math.Max(exchange.PriceDifference+exchange.Fee, 0),
+	}
+	if a := exchange.Adjustment; a != nil {
+		response.Adjustment = &dto.PaymentAdjustment{
+			PaymentID: a.ID,
+			Type: a.Type,
+			Amount: math.Abs(exchange.PriceDifference + exchange.Fee),
+			Status: a.Status,
+		}
+	}
+	return &response, nil
+}
+
 func (u *bookingUsecase) GetBookingHistory(ctx context.Context, q dto.PaginationQuery) ([]dto.BookingHistory, *dto.Pagination, error) {
 	bookings, total, err := u.repo.BookingRepo.GetBookingHistory(ctx, q)
 	if err != nil {
@@ -102,4 +216,85 @@ func (u *bookingUsecase) GetBookingHistory(ctx context.Context, q dto.Pagination
 	}
 	
 	return bookings, &pagination, nil
-}
\ No newline at end of file
+}
+
+// GetDocuments lists the PDFs of a paid booking the user can download
+func (u *bookingUsecase) GetDocuments(ctx context.Context, id int) ([]dto.BookingDocument, error) {
+	user := ctx.Value("user").(entity.User)
+
+	data, err := u.documentData(user, id, "")
+	if err != nil {
+		return nil, err
+	}
+
+	documents := []dto.BookingDocument{}
+	if len(data.Tickets) > 0 {
+		documents = append(documents, dto.BookingDocument{
+			Kind: "tickets",
+			FileName: utils.TicketsFileName(id),
+			URL: fmt.Sprintf("%s/api/v1/bookings/%d/documents/tickets", u.Config.BaseURL, id),
+		})
+	}
+	if data.Invoice != nil {
+		documents = append(documents, dto.BookingDocument{
+			Kind: "invoice",
+			FileName: utils.InvoiceFileName(id),
+			URL: fmt.Sprintf("%s/api/v1/bookings/%d/documents/invoice", u.Config.BaseURL, id),
+		})
+	}
+	return documents, nil
+}
+
+// GetDocument renders the tickets or invoice PDF of a booking with its file name
+func (u *bookingUsecase) GetDocument(ctx context.Context, id int, kind string, locale string) ([]byte, string, error) {
+	user := ctx.Value("user").(entity.User)
+
+	data, err := u.documentData(user, id, locale)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case kind == "tickets" && len(data.Tickets) > 0:
+		file, err := utils.TicketsPDF(*data, u.Config)
+		if err != nil {
+			u.Logger.Error("Error render tickets usecase: ", zap.Error(err))
+			return nil, "", err
+		}
+		return file, utils.TicketsFileName(id), nil
+	case kind == "invoice" && data.Invoice != nil:
+		file, err := utils.InvoicePDF(*data, u.Config)
+		if err != nil {
+			u.Logger.Error("Error render invoice usecase: ", zap.Error(err))
+			return nil, "", err
+		}
+		return file, utils.InvoiceFileName(id), nil
+	}
+	return nil, "", utils.ErrNotFound("document")
+}
+
+// documentData returns the document contents of a paid booking the user
+// bought or holds tickets of
+func (u *bookingUsecase) documentData(user entity.User, id int, locale string) (*dto.TicketEmail, error) {
+	b, err := u.repo.BookingRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get booking by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if b.Status != "paid" {
+		if b.UserID != user.ID {
+			return nil, utils.ErrNotFound("booking")
+		}
+		return nil, errors.New("documents are available once the booking is paid")
+	}
+
+	data, err := bookingDocuments(u.repo, u.Config, user, id, locale)
+	if err != nil {
+		u.Logger.Error("Error get booking documents usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if b.UserID != user.ID && len(data.Tickets) == 0 {
+		return nil, utils.ErrNotFound("booking")
+	}
+	return data, nil
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type BoxOfficeUsecase interface {
+	Sell(ctx context.Context, data dto.BoxOfficeRequest, locale string) (*dto.BoxOfficeSaleResponse, error)
+	GetReceipt(ctx context.Context, bookingID int, locale string) ([]byte, error)
+	OpenShift(ctx context.Context, data dto.OpenShiftRequest) (*dto.ShiftReport, error)
+	CurrentShift(ctx context.Context) (*dto.ShiftReport, error)
+	CloseShift(ctx context.Context, data dto.CloseShiftRequest) (*dto.ShiftReport, error)
+	GetShift(ctx context.Context, id int) (*dto.ShiftReport, error)
+	Reports(query dto.ShiftQuery) (*dto.ShiftReportResponse, error)
+}
+
+type boxOfficeUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+	Config utils.Configuration
+}
+
+func NewBoxOfficeUsecase(repo *repository.Repository, log *zap.Logger, config utils.Configuration) BoxOfficeUsecase {
+	return &boxOfficeUsecase{
+		Repo:   repo,
+		Logger: log,
+		Config: config,
+	}
+}
+
+// Sell books seats for a walk-in customer paid at the counter
+func (u *boxOfficeUsecase) Sell(ctx context.Context, data dto.BoxOfficeRequest, locale string) (*dto.BoxOfficeSaleResponse, error) {
+	cashier := ctx.Value("user").(entity.User)
+
+	bookingID, err := u.Repo.BoxOfficeRepo.Sell(cashier, data)
+	if err != nil {
+		u.Logger.Error("Error box office sell usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	sale, err := u.Repo.BoxOfficeRepo.GetSale(bookingID)
+	if err != nil {
+		u.Logger.Error("Error get box office sale usecase: ", zap.Error(err))
+		return nil, err
+	}
+	localizeSale(u.Repo, locale, sale)
+
+	response := toBoxOfficeSaleResponse(*sale, u.Config)
+	return &response, nil
+}
+
+// GetReceipt renders the receipt of a sale, cashiers only reprint their own
+func (u *boxOfficeUsecase) GetReceipt(ctx context.Context, bookingID int, locale string) ([]byte, error) {
+	cashier := ctx.Value("user").(entity.User)
+
+	sale, err := u.Repo.BoxOfficeRepo.GetSale(bookingID)
+	if err != nil {
+		u.Logger.Error("Error get box office sale usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if cashier.Role != "admin" && sale.CashierID != cashier.ID {
+		return nil, utils.ErrNotFound("sale")
+	}
+	localizeSale(u.Repo, locale, sale)
+
+	receipt, err := utils.BoxOfficeReceipt(toBoxOfficeSaleResponse(*sale, u.Config), locale, u.Config)
+	if err != nil {
+		u.Logger.Error("Error render receipt usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func (u *boxOfficeUsecase) OpenShift(ctx context.Context, data dto.OpenShiftRequest) (*dto.ShiftReport, error) {
+	cashier := ctx.Value("user").(entity.User)
+
+	id, err := u.Repo.BoxOfficeRepo.OpenShift(cashier.ID, cashier.CinemaID, data.OpeningFloat)
+	if err != nil {
+		u.Logger.Error("Error open shift usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return u.shiftReport(id)
+}
+
+func (u *boxOfficeUsecase) CurrentShift(ctx context.Context) (*dto.ShiftReport, error) {
+	cashier := ctx.Value("user").(entity.User)
+
+	shift, err := u.Repo.BoxOfficeRepo.GetOpenShift(cashier.ID)
+	if err != nil {
+		u.Logger.Error("Error get open shift usecase: ", zap.Error(err))
+		return nil, err
+	}
+	report := toShiftReport(*shift)
+	return &report, nil
+}
+
+// CloseShift records the counted cash, the report shows the variance against
+// the opening float plus cash sales
+func (u *boxOfficeUsecase) CloseShift(ctx context.Context, data dto.CloseShiftRequest) (*dto.ShiftReport, error) {
+	cashier := ctx.Value("user").(entity.User)
+
+	id, err := u.Repo.BoxOfficeRepo.CloseShift(cashier.ID, *data.CountedCash, data.Note)
+	if err != nil {
+		u.Logger.Error("Error close shift usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return u.shiftReport(id)
+}
+
+// GetShift returns any shift to admins, cashiers only see their own
+func (u *boxOfficeUsecase) GetShift(ctx context.Context, id int) (*dto.ShiftReport, error) {
+	user := ctx.Value("user").(entity.User)
+
+	shift, err := u.Repo.BoxOfficeRepo.GetShift(id)
+	if err != nil {
+		u.Logger.Error("Error get shift usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if user.Role != "admin" && shift.CashierID != user.ID {
+		return nil, utils.ErrNotFound("shift")
+	}
+	report := toShiftReport(*shift)
+	return &report, nil
+}
+
+// Reports sums the shifts opened on a day per cashier
+func (u *boxOfficeUsecase) Reports(query dto.ShiftQuery) (*dto.ShiftReportResponse, error) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	day := time.Now().In(loc)
+	if query.Date != "" {
+		var err error
+		day, err = time.ParseInLocation("02-01-2006", query.Date, loc)
+		if err != nil {
+			return nil, errors.New("date must be in dd-mm-yyyy format")
+		}
+	}
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	shifts, err := u.Repo.BoxOfficeRepo.GetShifts(from, from.AddDate(0, 0, 1), query.CashierID)
+	if err != nil {
+		u.Logger.Error("Error get shifts usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := dto.ShiftReportResponse{
+		Date:     from.Format("02-01-2006"),
+		Cashiers: []dto.CashierSummary{},
+		Shifts:   []dto.ShiftReport{},
+	}
+	index := map[int]int{}
+	for _, s := range shifts {
+		report := toShiftReport(s)
+		response.Shifts = append(response.Shifts, report)
+
+		i, ok := index[s.CashierID]
+		if !ok {
+			i = len(response.Cashiers)
+			index[s.CashierID] = i
+			response.Cashiers = append(response.Cashiers, dto.CashierSummary{
+				CashierID: s.CashierID,
+				Cashier:   s.CashierName,
+			})
+		}
+		summary := &response.Cashiers[i]
+		summary.Shifts++
+		summary.Bookings += report.Bookings
+		summary.Tickets += report.Tickets
+		summary.CashSales += report.CashSales
+		summary.EDCSales += report.EDCSales
+		summary.TotalSales += report.TotalSales
+		if report.Variance != nil {
+			summary.Variance += *report.Variance
+		} else {
+			summary.OpenShifts++
+		}
+	}
+	return &response, nil
+}
+
+func (u *boxOfficeUsecase) shiftReport(id int) (*dto.ShiftReport, error) {
+	shift, err := u.Repo.BoxOfficeRepo.GetShift(id)
+	if err != nil {
+		u.Logger.Error("Error get shift usecase: ", zap.Error(err))
+		return nil, err
+	}
+	report := toShiftReport(*shift)
+	return &report, nil
+}
+
+func toShiftReport(s entity.CashierShift) dto.ShiftReport {
+	report := dto.ShiftReport{
+		ShiftID:      s.ID,
+		CashierID:    s.CashierID,
+		Cashier:      s.CashierName,
+		CinemaID:     s.CinemaID,
+		Cinema:       s.CinemaName,
+		OpenedAt:     s.OpenedAt,
+		ClosedAt:     s.ClosedAt,
+		Bookings:     s.Bookings,
+		Tickets:      s.Tickets,
+		OpeningFloat: s.OpeningFloat,
+		CashSales:    s.CashSales,
+		EDCSales:     s.EDCSales,
+		TotalSales:   s.CashSales + s.EDCSales,
+		ExpectedCash: s.OpeningFloat + s.CashSales,
+		CountedCash:  s.CountedCash,
+		Note:         s.Note,
+	}
+	if s.CountedCash != nil {
+		variance := *s.CountedCash - report.ExpectedCash
+		report.Variance = &variance
+	}
+	return report
+}
+
+func toBoxOfficeSaleResponse(s entity.BoxOfficeSale, config utils.Configuration) dto.BoxOfficeSaleResponse {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+
+	var tickets []dto.Ticket
+	for _, t := range s.Tickets {
+		tickets = append(tickets, dto.Ticket{SeatCode: t.SeatCode, QRToken: t.QRToken})
+	}
+
+	payment := dto.BoxOfficePayment{
+		PaymentID:    s.PaymentID,
+		Method:       s.PaymentMethod,
+		Amount:       s.Amount,
+		Tendered:     s.Tendered,
+		ApprovalCode: s.ApprovalCode,
+		Status:       "success",
+	}
+	if s.Tendered != nil {
+		change := *s.Tendered - s.Amount
+		payment.Change = &change
+	}
+
+	return dto.BoxOfficeSaleResponse{
+		BookingID:     s.BookingID,
+		ReceiptNumber: fmt.Sprintf("BO-%06d", s.BookingID),
+		MovieTitle:    s.MovieTitle,
+		CinemaName:    s.CinemaName,
+		StudioName:    s.StudioName,
+		Format:        s.Format,
+		Date:          s.StartTime.In(loc).Format("02-01-2006"),
+		StartTime:     s.StartTime.In(loc).Format("15.04"),
+		CustomerPhone: s.CustomerPhone,
+		Cashier:       s.CashierName,
+		Price:         s.Price,
+		Tickets:       tickets,
+		Payment:       payment,
+		ReceiptURL:    fmt.Sprintf("%s/api/v1/box-office/bookings/%d/receipt", config.BaseURL, s.BookingID),
+		CreatedAt:     s.CreatedAt,
+	}
+}
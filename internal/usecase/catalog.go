@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Keeps a single import inside one reasonably sized transaction
+const catalogMaxRows = 5000
+
+type CatalogUsecase interface {
+	Import(kind string, format string, r io.Reader, dryRun bool) (*dto.ImportReport, error)
+	Export(kind string, format string, from string, to string) ([]byte, error)
+}
+
+type catalogUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+}
+
+func NewCatalogUsecase(repo *repository.Repository, log *zap.Logger) CatalogUsecase {
+	return &catalogUsecase{
+		Repo:   repo,
+		Logger: log,
+	}
+}
+
+func (u *catalogUsecase) Import(kind string, format string, r io.Reader, dryRun bool) (*dto.ImportReport, error) {
+	var report *dto.ImportReport
+	var invalid []dto.ImportRowError
+	var err error
+
+	switch kind {
+	case "movies":
+		var rows []dto.MovieImportRow
+		rows, err = utils.DecodeCatalog[dto.MovieImportRow](format, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > catalogMaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", catalogMaxRows)
+		}
+		invalid = validateCatalogRows(rows)
+		report, err = u.Repo.CatalogRepo.ImportMovies(rows, invalidRows(invalid), !dryRun)
+	case "screenings":
+		var rows []dto.ScreeningImportRow
+		rows, err = utils.DecodeCatalog[dto.ScreeningImportRow](format, r)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > catalogMaxRows {
+			return nil, fmt.Errorf("file has more than %d rows", catalogMaxRows)
+		}
+		invalid = validateCatalogRows(rows)
+		report, err = u.Repo.CatalogRepo.ImportScreenings(rows, invalidRows(invalid), !dryRun)
+	default:
+		return nil, errors.New("kind must be movies or screenings")
+	}
+	if err != nil {
+		u.Logger.Error("Error import catalog usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Merge field validation with database checks into one report
+	report.Kind = kind
+	report.DryRun = dryRun
+	report.Errors = append(report.Errors, invalid...)
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Row < report.Errors[j].Row
+	})
+	failed := make(map[int]bool)
+	for _, e := range report.Errors {
+		failed[e.Row] = true
+	}
+	report.Failed = len(failed)
+	if report.Errors == nil {
+		report.Errors = []dto.ImportRowError{}
+	}
+	return report, nil
+}
+
+func (u *catalogUsecase) Export(kind string, format string, from string, to string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch kind {
+	case "movies":
+		rows, err := u.Repo.CatalogRepo.ExportMovies()
+		if err != nil {
+			u.Logger.Error("Error export movies usecase: ", zap.Error(err))
+			return nil, err
+		}
+		if err := utils.EncodeCatalog(format, &buf, rows); err != nil {
+			return nil, err
+		}
+	case "screenings":
+		// Defaults to the coming week in cinema local time
+		loc, _ := time.LoadLocation("Asia/Jakarta")
+		now := time.Now().In(loc)
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		if from != "" {
+			t, err := time.ParseInLocation("02-01-2006", from, loc)
+			if err != nil {
+				return nil, errors.New("from must use DD-MM-YYYY")
+			}
+			start = t
+		}
+		end := start.AddDate(0, 0, 7)
+		if to != "" {
+			t, err := time.ParseInLocation("02-01-2006", to, loc)
+			if err != nil {
+				return nil, errors.New("to must use DD-MM-YYYY")
+			}
+			// Inclusive end date
+			end = t.AddDate(0, 0, 1)
+		}
+		if !end.After(start) {
+			return nil, errors.New("to must not be before from")
+		}
+
+		rows, err := u.Repo.CatalogRepo.ExportScreenings(start.UTC(), end.UTC())
+		if err != nil {
+			u.Logger.Error("Error export screenings usecase: ", zap.Error(err))
+			return nil, err
+		}
+		if err := utils.EncodeCatalog(format, &buf, rows); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("kind must be movies or screenings")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func validateCatalogRows[T any](rows []T) []dto.ImportRowError {
+	var errs []dto.ImportRowError
+	for i, row := range rows {
+		messages, err := utils.ValidateErrors(row)
+		if err == nil {
+			continue
+		}
+		if len(messages) == 0 {
+			errs = append(errs, dto.ImportRowError{Row: i + 1, Message: err.Error()})
+		}
+		for _, m := range messages {
+			errs = append(errs, dto.ImportRowError{Row: i + 1, Field: m.Field, Message: m.Message})
+		}
+	}
+	return errs
+}
+
+// invalidRows lists 0-based indexes of rows that failed validation
+func invalidRows(errs []dto.ImportRowError) map[int]bool {
+	rows := make(map[int]bool)
+	for _, e := range errs {
+		rows[e.Row-1] = true
+	}
+	return rows
+}
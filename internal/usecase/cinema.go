@@ -1,6 +1,13 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
@@ -9,11 +16,16 @@ import (
 )
 
 type CinemaUsecase interface{
-	GetAll(q dto.PaginationQuery) ([]dto.CinemaResponse, *dto.Pagination, error)
-	GetByID(id int) (*dto.CinemaResponse, error)
+	GetAll(q dto.CinemaQuery) ([]dto.CinemaResponse, *dto.Pagination, error)
+	GetNearest(q dto.NearestCinemaQuery) ([]dto.CinemaResponse, *dto.Pagination, error)
+	GetByID(id int, locale string) (*dto.CinemaResponse, error)
 	Create(data dto.CinemaRequest) (*dto.CinemaResponse, error)
 	Update(id int, data dto.CinemaRequest) error
 	Delete(id int) error
+	GetAmenities() ([]dto.AmenityResponse, error)
+	GetClosures(id int) ([]dto.CinemaClosureResponse, error)
+	CreateClosure(id int, data dto.CinemaClosureRequest) (*dto.CinemaClosureResponse, error)
+	DeleteClosure(id int, closureID int) error
 }
 
 type cinemaUsecase struct {
@@ -28,7 +40,7 @@ func NewCinemaUsecase(repo *repository.Repository, log *zap.Logger) CinemaUsecas
 	}
 }
 
-func (s *cinemaUsecase) GetAll(q dto.PaginationQuery) ([]dto.CinemaResponse, *dto.Pagination, error) {
+func (s *cinemaUsecase) GetAll(q dto.CinemaQuery) ([]dto.CinemaResponse, *dto.Pagination, error) {
 	// Execute repo to get all cinemas
 	cinemas, total, err := s.Repo.CinemaRepo.GetAll(q)
 	if err != nil {
@@ -58,49 +70,81 @@ func (s *cinemaUsecase) GetAll(q dto.PaginationQuery) ([]dto.CinemaResponse, *dt
 
 	var response []dto.CinemaResponse
 	for _, c := range cinemas {
-		response = append(response, dto.CinemaResponse{
-			CinemaID: c.ID,
-			Name: c.Name,
-			Location: c.Location,
-		})
+		response = append(response, toCinemaResponse(c))
 	}
+	localizeCinemas(s.Repo, q.Locale, response)
 
 	return response, &pagination, nil
 }
 
-func (s *cinemaUsecase) GetByID(id int) (*dto.CinemaResponse, error) {
+func (s *cinemaUsecase) GetNearest(q dto.NearestCinemaQuery) ([]dto.CinemaResponse, *dto.Pagination, error) {
+	// Execute repo to get cinemas by distance
+	response, total, err := s.Repo.CinemaRepo.GetNearest(q)
+	if err != nil {
+		s.Logger.Error("Error get nearest cinemas usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	// Calculate total pages
+	var totalPages int
+	totalPages = utils.TotalPage(q.Limit, total)
+
+	// Create pagination
+	var pagination dto.Pagination
+
+	if q.All {
+		pagination = dto.Pagination{
+			TotalRecords: total,
+		}
+	} else {
+		pagination = dto.Pagination{
+			CurrentPage:  &q.Page,
+			Limit:        &q.Limit,
+			TotalPages:   &totalPages,
+			TotalRecords: total,
+		}
+	}
+
+	// Metres are noise for a cinema list
+	for i := range response {
+		distance := math.Round(*response[i].DistanceKm*100) / 100
+		response[i].DistanceKm = &distance
+	}
+	localizeCinemas(s.Repo, q.Locale, response)
+
+	return response, &pagination, nil
+}
+
+func (s *cinemaUsecase) GetByID(id int, locale string) (*dto.CinemaResponse, error) {
 	c, err := s.Repo.CinemaRepo.GetByID(id)
 	if err != nil {
 		s.Logger.Error("Error get cinema by id usecase: ", zap.Error(err))
 		return nil, err
 	}
+	localizeCinema(s.Repo, locale, c)
 	return c, err
 }
 
 func (s *cinemaUsecase) Create(data dto.CinemaRequest) (*dto.CinemaResponse, error) {
-	cinema := entity.Cinema{
-		Name: data.Name,
-		Location: data.Location,
+	cinema, err := s.toCinema(data)
+	if err != nil {
+		return nil, err
 	}
-	newCinema, err := s.Repo.CinemaRepo.Create(cinema)
+	newCinema, err := s.Repo.CinemaRepo.Create(*cinema)
 	if err != nil {
 		s.Logger.Error("Error create cinema usecase: ", zap.Error(err))
 		return nil, err
 	}
-	response := dto.CinemaResponse{
-		CinemaID: newCinema.ID,
-		Name: newCinema.Name,
-		Location: newCinema.Location,
-	}
+	response := toCinemaResponse(*newCinema)
 	return &response, err
 }
 
 func (s *cinemaUsecase) Update(id int, data dto.CinemaRequest) error {
-	cinema := entity.Cinema{
-		Name: data.Name,
-		Location: data.Location,
+	cinema, err := s.toCinema(data)
+	if err != nil {
+		return err
 	}
-	err := s.Repo.CinemaRepo.Update(id, &cinema)
+	err = s.Repo.CinemaRepo.Update(id, cinema)
 	if err != nil {
 		s.Logger.Error("Error update cinema usecase: ", zap.Error(err))
 		return err
@@ -115,4 +159,167 @@ func (s *cinemaUsecase) Delete(id int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *cinemaUsecase) GetAmenities() ([]dto.AmenityResponse, error) {
+	amenities, err := s.Repo.CinemaRepo.GetAmenities()
+	if err != nil {
+		s.Logger.Error("Error get amenities usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := []dto.AmenityResponse{}
+	for _, a := range amenities {
+		response = append(response, dto.AmenityResponse{
+			Code: a.Code,
+			Name: a.Name,
+		})
+	}
+	return response, nil
+}
+
+// GetClosures lists closures from today onwards
+func (s *cinemaUsecase) GetClosures(id int) ([]dto.CinemaClosureResponse, error) {
+	// Make sure the cinema exists so unknown ids give 404 instead of an empty list
+	if _, err := s.Repo.CinemaRepo.GetByID(id); err != nil {
+		s.Logger.Error("Error get cinema by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	closures, err := s.Repo.CinemaRepo.GetClosures(id, time.Now().In(loc))
+	if err != nil {
+		s.Logger.Error("Error get cinema closures usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := []dto.CinemaClosureResponse{}
+	for _, c := range closures {
+		response = append(response, toClosureResponse(c))
+	}
+	return response, nil
+}
+
+func (s *cinemaUsecase) CreateClosure(id int, data dto.CinemaClosureRequest) (*dto.CinemaClosureResponse, error) {
+	date, err := time.Parse("02-01-2006", data.Date)
+	if err != nil {
+		return nil, errors.New("date must use DD-MM-YYYY")
+	}
+
+	closure := entity.CinemaClosure{
+		CinemaID: id,
+		Date: date,
+		Reason: trimOptional(&data.Reason),
+	}
+	newClosure, err := s.Repo.CinemaRepo.CreateClosure(closure)
+	if err != nil {
+		s.Logger.Error("Error create cinema closure usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toClosureResponse(*newClosure)
+	return &response, nil
+}
+
+func (s *cinemaUsecase) DeleteClosure(id int, closureID int) error {
+	err := s.Repo.CinemaRepo.DeleteClosure(id, closureID)
+	if err != nil {
+		s.Logger.Error("Error delete cinema closure usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *cinemaUsecase) toCinema(data dto.CinemaRequest) (*entity.Cinema, error) {
+	cinema := entity.Cinema{
+		Name: data.Name,
+		Location: data.Location,
+		Address: trimOptional(data.Address),
+		City: trimOptional(data.City),
+		Region: trimOptional(data.Region),
+		Latitude: data.Latitude,
+		Longitude: data.Longitude,
+	}
+
+	// One row per weekday, a cinema cannot open and close at the same time
+	if data.OpeningHours != nil {
+		cinema.OpeningHours = []entity.OpeningHour{}
+		seen := map[int]bool{}
+		for _, h := range data.OpeningHours {
+			if seen[h.Weekday] {
+				return nil, fmt.Errorf("duplicate opening hours for %s", time.Weekday(h.Weekday))
+			}
+			seen[h.Weekday] = true
+			if h.OpenTime == h.CloseTime {
+				return nil, fmt.Errorf("opening hours for %s must not open and close at the same time", time.Weekday(h.Weekday))
+			}
+			cinema.OpeningHours = append(cinema.OpeningHours, entity.OpeningHour{
+				Weekday: h.Weekday,
+				OpenTime: h.OpenTime,
+				CloseTime: h.CloseTime,
+			})
+		}
+	}
+
+	// Amenities must be known
+	if data.Amenities != nil {
+		amenities, err := s.Repo.CinemaRepo.GetAmenities()
+		if err != nil {
+			s.Logger.Error("Error get amenities usecase: ", zap.Error(err))
+			return nil, err
+		}
+		known := map[string]bool{}
+		for _, a := range amenities {
+			known[a.Code] = true
+		}
+		cinema.Amenities = []string{}
+		for _, code := range data.Amenities {
+			code = strings.ToLower(strings.TrimSpace(code))
+			if !known[code] {
+				return nil, fmt.Errorf("unknown amenity %q", code)
+			}
+			if !slices.Contains(cinema.Amenities, code) {
+				cinema.Amenities = append(cinema.Amenities, code)
+			}
+		}
+	}
+	return &cinema, nil
+}
+
+func toClosureResponse(c entity.CinemaClosure) dto.CinemaClosureResponse {
+	return dto.CinemaClosureResponse{
+		ClosureID: c.ID,
+		CinemaID: c.CinemaID,
+		Date: c.Date.Format("02-01-2006"),
+		Reason: c.Reason,
+	}
+}
+
+func toCinemaResponse(c entity.Cinema) dto.CinemaResponse {
+	var hours []dto.OpeningHourResponse
+	for _, h := range c.OpeningHours {
+		hours = append(hours, dto.OpeningHourResponse{
+			Weekday: h.Weekday,
+			Day: time.Weekday(h.Weekday).String(),
+			OpenTime: h.OpenTime,
+			CloseTime: h.CloseTime,
+		})
+	}
+	amenities := c.Amenities
+	if amenities == nil {
+		amenities = []string{}
+	}
+
+	return dto.CinemaResponse{
+		CinemaID: c.ID,
+		Name: c.Name,
+		Location: c.Location,
+		Address: c.Address,
+		City: c.City,
+		Region: c.Region,
+		Latitude: c.Latitude,
+		Longitude: c.Longitude,
+		OpeningHours: hours,
+		Amenities: amenities,
+	}
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"errors"
+
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
@@ -11,8 +13,10 @@ import (
 type GenreUsecase interface{
 	GetAll(q dto.PaginationQuery) ([]entity.Genre, *dto.Pagination, error)
 	GetByID(id int) (*entity.Genre, error)
-	Create(data entity.Genre) (*entity.Genre, error)
+	Create(data dto.GenreRequest) (*entity.Genre, error)
+	Update(id int, data dto.GenreRequest) (*entity.Genre, error)
 	Delete(id int) error
+	Merge(id int, data dto.MergeGenreRequest) (*entity.Genre, error)
 }
 
 type genreUsecase struct {
@@ -67,7 +71,7 @@ func (s *genreUsecase) GetByID(id int) (*entity.Genre, error) {
 	return genre, err
 }
 
-func (s *genreUsecase) Create(data entity.Genre) (*entity.Genre, error) {
+func (s *genreUsecase) Create(data dto.GenreRequest) (*entity.Genre, error) {
 	newgenre, err := s.Repo.GenreRepo.Create(data)
 	if err != nil {
 		s.Logger.Error("Error create genre usecase: ", zap.Error(err))
@@ -76,6 +80,15 @@ func (s *genreUsecase) Create(data entity.Genre) (*entity.Genre, error) {
 	return newgenre, err
 }
 
+func (s *genreUsecase) Update(id int, data dto.GenreRequest) (*entity.Genre, error) {
+	genre, err := s.Repo.GenreRepo.Update(id, data)
+	if err != nil {
+		s.Logger.Error("Error update genre usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return genre, nil
+}
+
 func (s *genreUsecase) Delete(id int) error {
 	err := s.Repo.GenreRepo.Delete(id)
 	if err != nil {
@@ -83,4 +96,26 @@ func (s *genreUsecase) Delete(id int) error {
 		return err
 	}
 	return nil
+}
+
+func (s *genreUsecase) Merge(id int, data dto.MergeGenreRequest) (*entity.Genre, error) {
+	// Drop repeated ids, a genre cannot be merged into itself
+	seen := map[int]bool{}
+	var sources []int
+	for _, sid := range data.SourceIDs {
+		if sid == id {
+			return nil, errors.New("genre cannot be merged into itself")
+		}
+		if !seen[sid] {
+			seen[sid] = true
+			sources = append(sources, sid)
+		}
+	}
+
+	genre, err := s.Repo.GenreRepo.Merge(id, sources)
+	if err != nil {
+		s.Logger.Error("Error merge genres usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return genre, nil
 }
\ No newline at end of file
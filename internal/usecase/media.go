@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/storage"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// Uploads are decoded to build variants, so only formats the standard
+// library can read are accepted
+var mediaTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Resized copies generated next to the original, by target width
+var mediaVariants = []struct {
+	Name  string
+	Width int
+}{
+	{"medium", 800},
+	{"thumb", 300},
+}
+
+// Kinds of images each entity accepts
+var mediaKinds = map[string][]string{
+	"movie":  {"poster", "backdrop", "still"},
+	"cinema": {"logo", "photo"},
+}
+
+// Guards against decompression bombs before decoding pixels
+const mediaMaxPixels = 40_000_000
+
+type MediaUsecase interface {
+	Upload(userID int, filename string, data []byte) (*dto.MediaResponse, error)
+	GetByID(id int) (*dto.MediaResponse, error)
+	Open(id int, variant string) (storage.Object, *entity.MediaVariant, time.Time, error)
+	Delete(id int) error
+	Link(entityType string, entityID int, data dto.MediaLinkRequest) error
+	Unlink(entityType string, entityID int, mediaID int) error
+	GetLinks(entityType string, entityID int) ([]dto.MediaLinkResponse, error)
+}
+
+type mediaUsecase struct {
+	Repo    *repository.Repository
+	Logger  *zap.Logger
+	Config  utils.Configuration
+	Storage storage.Storage
+}
+
+func NewMediaUsecase(repo *repository.Repository, log *zap.Logger, config utils.Configuration) MediaUsecase {
+	return &mediaUsecase{
+		Repo:    repo,
+		Logger:  log,
+		Config:  config,
+		Storage: storage.NewLocal(config.MediaPath),
+	}
+}
+
+func (u *mediaUsecase) Upload(userID int, filename string, data []byte) (*dto.MediaResponse, error) {
+	// Validate size and content, the client supplied type is ignored
+	if len(data) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	if int64(len(data)) > u.Config.MediaMaxSize {
+		return nil, fmt.Errorf("file is larger than %d MB", u.Config.MediaMaxSize>>20)
+	}
+
+	mtype := mimetype.Detect(data)
+	mimeType := strings.Split(mtype.String(), ";")[0]
+	if !mediaTypes[mimeType] {
+		return nil, fmt.Errorf("unsupported file type %s", mimeType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("file is not a valid image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > mediaMaxPixels {
+		return nil, errors.New("image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("file is not a valid image")
+	}
+
+	// Store original and resized variants under a unique prefix
+	prefix := fmt.Sprintf("media/%s/%s", time.Now().UTC().Format("2006/01"), uuid.NewString())
+	media := entity.Media{
+		OriginalName: filepath.Base(filename),
+		MimeType:     mimeType,
+		Size:         int64(len(data)),
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+		UploadedBy:   &userID,
+	}
+
+	var stored []string
+	cleanup := func() {
+		for _, key := range stored {
+			if err := u.Storage.Delete(key); err != nil {
+				u.Logger.Error("Error delete media file: ", zap.Error(err))
+			}
+		}
+	}
+
+	original := entity.MediaVariant{
+		Variant:    "original",
+		StorageKey: prefix + "/original" + mtype.Extension(),
+		MimeType:   mimeType,
+		Size:       int64(len(data)),
+		Width:      cfg.Width,
+		Height:     cfg.Height,
+	}
+	if err := u.Storage.Put(original.StorageKey, bytes.NewReader(data)); err != nil {
+		u.Logger.Error("Error store media file: ", zap.Error(err))
+		return nil, err
+	}
+	stored = append(stored, original.StorageKey)
+	media.Variants = append(media.Variants, original)
+
+	for _, v := range mediaVariants {
+		resized := utils.ResizeImage(img, v.Width)
+
+		// Re-encoding also strips metadata such as EXIF location
+		var buf bytes.Buffer
+		variant := entity.MediaVariant{
+			Variant: v.Name,
+			Width:   resized.Bounds().Dx(),
+			Height:  resized.Bounds().Dy(),
+		}
+		if mimeType == "image/jpeg" {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+			variant.MimeType = "image/jpeg"
+			variant.StorageKey = prefix + "/" + v.Name + ".jpg"
+		} else {
+			err = png.Encode(&buf, resized)
+			variant.MimeType = "image/png"
+			variant.StorageKey = prefix + "/" + v.Name + ".png"
+		}
+		if err != nil {
+			cleanup()
+			u.Logger.Error("Error encode media variant: ", zap.Error(err))
+			return nil, err
+		}
+		variant.Size = int64(buf.Len())
+
+		if err := u.Storage.Put(variant.StorageKey, &buf); err != nil {
+			cleanup()
+			u.Logger.Error("Error store media file: ", zap.Error(err))
+			return nil, err
+		}
+		stored = append(stored, variant.StorageKey)
+		media.Variants = append(media.Variants, variant)
+	}
+
+	id, err := u.Repo.MediaRepo.Create(media)
+	if err != nil {
+		cleanup()
+		u.Logger.Error("Error create media usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	return u.GetByID(id)
+}
+
+func (u *mediaUsecase) GetByID(id int) (*dto.MediaResponse, error) {
+	m, err := u.Repo.MediaRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get media usecase: ", zap.Error(err))
+		return nil, err
+	}
+	response := u.toMediaResponse(*m)
+	return &response, nil
+}
+
+func (u *mediaUsecase) Open(id int, variant string) (storage.Object, *entity.MediaVariant, time.Time, error) {
+	v, err := u.Repo.MediaRepo.GetVariant(id, variant)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	obj, modTime, err := u.Storage.Open(v.StorageKey)
+	if err == storage.ErrNotExist {
+		u.Logger.Error("Error media file missing: ", zap.String("key", v.StorageKey))
+		return nil, nil, time.Time{}, utils.ErrNotFound("media")
+	}
+	if err != nil {
+		u.Logger.Error("Error open media file: ", zap.Error(err))
+		return nil, nil, time.Time{}, err
+	}
+	return obj, v, modTime, nil
+}
+
+func (u *mediaUsecase) Delete(id int) error {
+	keys, err := u.Repo.MediaRepo.Delete(id)
+	if err != nil {
+		u.Logger.Error("Error delete media usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Files go after the rows, a leftover file is harmless
+	for _, key := range keys {
+		if err := u.Storage.Delete(key); err != nil {
+			u.Logger.Error("Error delete media file: ", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (u *mediaUsecase) Link(entityType string, entityID int, data dto.MediaLinkRequest) error {
+	allowed := false
+	for _, kind := range mediaKinds[entityType] {
+		if kind == data.Kind {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("kind must be one of %s", strings.Join(mediaKinds[entityType], ", "))
+	}
+
+	err := u.Repo.MediaRepo.Link(entity.MediaLink{
+		MediaID:    data.MediaID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Kind:       data.Kind,
+		Position:   data.Position,
+	}, u.mediaURL(data.MediaID, "medium"))
+	if err != nil {
+		u.Logger.Error("Error link media usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *mediaUsecase) Unlink(entityType string, entityID int, mediaID int) error {
+	err := u.Repo.MediaRepo.Unlink(entityType, entityID, mediaID)
+	if err != nil {
+		u.Logger.Error("Error unlink media usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *mediaUsecase) GetLinks(entityType string, entityID int) ([]dto.MediaLinkResponse, error) {
+	links, err := u.Repo.MediaRepo.GetLinks(entityType, entityID)
+	if err != nil {
+		u.Logger.Error("Error get media links usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := []dto.MediaLinkResponse{}
+	for _, l := range links {
+		response = append(response, dto.MediaLinkResponse{
+			Kind:     l.Kind,
+			Position: l.Position,
+			Media:    u.toMediaResponse(*l.Media),
+		})
+	}
+	return response, nil
+}
+
+func (u *mediaUsecase) mediaURL(id int, variant string) string {
+	return fmt.Sprintf("%s/api/v1/media/%d/%s", u.Config.BaseURL, id, variant)
+}
+
+func (u *mediaUsecase) toMediaResponse(m entity.Media) dto.MediaResponse {
+	response := dto.MediaResponse{
+		MediaID:      m.ID,
+		OriginalName: m.OriginalName,
+		MimeType:     m.MimeType,
+		Size:         m.Size,
+		Width:        m.Width,
+		Height:       m.Height,
+		Variants:     make(map[string]dto.MediaVariant),
+		CreatedAt:    m.CreatedAt,
+	}
+	for _, v := range m.Variants {
+		response.Variants[v.Variant] = dto.MediaVariant{
+			URL:    u.mediaURL(m.ID, v.Variant),
+			Width:  v.Width,
+			Height: v.Height,
+			Size:   v.Size,
+		}
+	}
+	return response
+}
@@ -1,6 +1,11 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/utils"
@@ -9,10 +14,12 @@ import (
 
 type MovieUsecase interface{
 	GetAll(q dto.MovieQuery) ([]dto.MovieResponse, *dto.Pagination, error)
-	GetByID(id int) (*dto.MovieResponse, error)
+	Search(q dto.MovieSearchQuery) ([]dto.MovieResponse, *dto.Pagination, error)
+	GetByID(id int, locale string) (*dto.MovieResponse, error)
 	Create(data dto.MovieRequest) (*dto.MovieResponse, error)
 	Update(id int, data dto.MovieRequest) error
 	Delete(id int) error
+	SetCredits(id int, data dto.MovieCreditsRequest) ([]dto.CreditResponse, error)
 }
 
 type movieUsecase struct {
@@ -68,18 +75,82 @@ func (s *movieUsecase) GetAll(q dto.MovieQuery) ([]dto.MovieResponse, *dto.Pagin
 			ReleaseDate: m.ReleaseDate,
 			Language: m.Language,
 			RatingAge: m.RatingAge,
+			RatingAverage: m.RatingAverage,
+			RatingCount: m.RatingCount,
 		})
 	}
+	localizeMovies(s.Repo, q.Locale, response)
 
 	return response, &pagination, nil
 }
 
-func (s *movieUsecase) GetByID(id int) (*dto.MovieResponse, error) {
+func (s *movieUsecase) Search(q dto.MovieSearchQuery) ([]dto.MovieResponse, *dto.Pagination, error) {
+	// Execute repo to search movies
+	movies, total, err := s.Repo.MovieRepo.Search(q)
+	if err != nil {
+		s.Logger.Error("Error search movies usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	// Calculate total pages
+	var totalPages int
+	totalPages = utils.TotalPage(q.Limit, total)
+
+	// Create pagination
+	var pagination dto.Pagination
+
+	if q.All {
+		pagination = dto.Pagination{
+			TotalRecords: total,
+		}
+	} else {
+		pagination = dto.Pagination{
+			CurrentPage:  &q.Page,
+			Limit:        &q.Limit,
+			TotalPages:   &totalPages,
+			TotalRecords: total,
+		}
+	}
+
+	var response []dto.MovieResponse
+	for _, m := range movies {
+		response = append(response, dto.MovieResponse{
+			MovieID: m.ID,
+			Title: m.Title,
+			Synopsis: m.Synopsis,
+			Genres: m.Genres,
+			PosterURL: m.PosterURL,
+			TrailerURL: m.TrailerURL,
+			Duration: m.Duration,
+			ReleaseDate: m.ReleaseDate,
+			Language: m.Language,
+			RatingAge: m.RatingAge,
+			RatingAverage: m.RatingAverage,
+			RatingCount: m.RatingCount,
+		})
+	}
+	localizeMovies(s.Repo, q.Locale, response)
+
+	return response, &pagination, nil
+}
+
+func (s *movieUsecase) GetByID(id int, locale string) (*dto.MovieResponse, error) {
 	m, err := s.Repo.MovieRepo.GetByID(id)
 	if err != nil {
 		s.Logger.Error("Error get movie by id usecase: ", zap.Error(err))
 		return nil, err
 	}
+
+	// Attach cast and crew
+	credits, err := s.Repo.PersonRepo.GetCredits(id)
+	if err != nil {
+		s.Logger.Error("Error get movie credits usecase: ", zap.Error(err))
+		return nil, err
+	}
+	for _, c := range credits {
+		m.Credits = append(m.Credits, toCreditResponse(c))
+	}
+	localizeMovie(s.Repo, locale, m)
 	return m, err
 }
 
@@ -113,4 +184,51 @@ func (s *movieUsecase) Delete(id int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *movieUsecase) SetCredits(id int, data dto.MovieCreditsRequest) ([]dto.CreditResponse, error) {
+	var credits []entity.MovieCredit
+	seen := make(map[string]bool)
+	for _, c := range data.Credits {
+		credit := entity.MovieCredit{
+			PersonID: c.PersonID,
+			Role:     c.Role,
+			Order:    c.Order,
+		}
+
+		// Only cast members play a character
+		character := strings.TrimSpace(c.CharacterName)
+		if character != "" {
+			if c.Role != "cast" {
+				return nil, errors.New("character name is only allowed for cast")
+			}
+			credit.CharacterName = &character
+		}
+
+		key := fmt.Sprintf("%d|%s|%s", c.PersonID, c.Role, character)
+		if seen[key] {
+			return nil, errors.New("duplicate credit for person " + fmt.Sprint(c.PersonID))
+		}
+		seen[key] = true
+
+		credits = append(credits, credit)
+	}
+
+	err := s.Repo.PersonRepo.ReplaceCredits(id, credits)
+	if err != nil {
+		s.Logger.Error("Error set movie credits usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	result, err := s.Repo.PersonRepo.GetCredits(id)
+	if err != nil {
+		s.Logger.Error("Error get movie credits usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := []dto.CreditResponse{}
+	for _, c := range result {
+		response = append(response, toCreditResponse(c))
+	}
+	return response, nil
+}
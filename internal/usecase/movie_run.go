@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"go.uber.org/zap"
+)
+
+type MovieRunUsecase interface {
+	Create(data dto.MovieRunRequest) (*dto.MovieRunResponse, error)
+	Update(id int, data dto.MovieRunRequest) error
+	Delete(id int) error
+	GetByMovie(movieID int) ([]dto.MovieRunResponse, error)
+	NowShowing(cinemaID int, locale string) ([]dto.MovieResponse, error)
+	ComingSoon(cinemaID int, locale string) ([]dto.ComingSoonResponse, error)
+}
+
+type movieRunUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+}
+
+func NewMovieRunUsecase(repo *repository.Repository, log *zap.Logger) MovieRunUsecase {
+	return &movieRunUsecase{
+		Repo:   repo,
+		Logger: log,
+	}
+}
+
+func (u *movieRunUsecase) Create(data dto.MovieRunRequest) (*dto.MovieRunResponse, error) {
+	run, err := u.toMovieRun(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.Repo.MovieRunRepo.Create(*run)
+	if err != nil {
+		u.Logger.Error("Error create movie run usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	created, err := u.Repo.MovieRunRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get movie run usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toMovieRunResponse(*created)
+	return &response, nil
+}
+
+func (u *movieRunUsecase) Update(id int, data dto.MovieRunRequest) error {
+	run, err := u.toMovieRun(data)
+	if err != nil {
+		return err
+	}
+
+	err = u.Repo.MovieRunRepo.Update(id, *run)
+	if err != nil {
+		u.Logger.Error("Error update movie run usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *movieRunUsecase) Delete(id int) error {
+	err := u.Repo.MovieRunRepo.Delete(id)
+	if err != nil {
+		u.Logger.Error("Error delete movie run usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *movieRunUsecase) GetByMovie(movieID int) ([]dto.MovieRunResponse, error) {
+	runs, err := u.Repo.MovieRunRepo.GetByMovie(movieID)
+	if err != nil {
+		u.Logger.Error("Error get movie runs usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var response []dto.MovieRunResponse
+	for _, r := range runs {
+		response = append(response, toMovieRunResponse(r))
+	}
+	return response, nil
+}
+
+func (u *movieRunUsecase) NowShowing(cinemaID int, locale string) ([]dto.MovieResponse, error) {
+	movies, err := u.Repo.MovieRunRepo.NowShowing(cinemaID)
+	if err != nil {
+		u.Logger.Error("Error get now showing movies usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var response []dto.MovieResponse
+	for _, m := range movies {
+		response = append(response, toMovieResponse(m))
+	}
+	localizeMovies(u.Repo, locale, response)
+	return response, nil
+}
+
+func (u *movieRunUsecase) ComingSoon(cinemaID int, locale string) ([]dto.ComingSoonResponse, error) {
+	runs, err := u.Repo.MovieRunRepo.ComingSoon(cinemaID)
+	if err != nil {
+		u.Logger.Error("Error get coming soon movies usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var response []dto.ComingSoonResponse
+	var movies []dto.MovieResponse
+	for _, r := range runs {
+		movies = append(movies, toMovieResponse(*r.Movie))
+	}
+	localizeMovies(u.Repo, locale, movies)
+
+	for i, r := range runs {
+		run := toMovieRunResponse(r)
+		response = append(response, dto.ComingSoonResponse{
+			Movie:          movies[i],
+			Status:         r.Status,
+			PresaleStartAt: run.PresaleStartAt,
+			OpeningDate:    run.StartDate,
+		})
+	}
+	return response, nil
+}
+
+func (u *movieRunUsecase) toMovieRun(data dto.MovieRunRequest) (*entity.MovieRun, error) {
+	// Check references
+	if _, err := u.Repo.MovieRepo.GetByID(data.MovieID); err != nil {
+		u.Logger.Error("Error get movie usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if _, err := u.Repo.CinemaRepo.GetByID(data.CinemaID); err != nil {
+		u.Logger.Error("Error get cinema usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Run dates are calendar dates, pre-sale is a local time
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	run := entity.MovieRun{
+		MovieID:  data.MovieID,
+		CinemaID: data.CinemaID,
+	}
+
+	startDate, err := time.Parse("02-01-2006", data.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	run.StartDate = startDate
+
+	if data.EndDate != "" {
+		endDate, err := time.Parse("02-01-2006", data.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		if endDate.Before(startDate) {
+			return nil, errors.New("end date must not be before start date")
+		}
+		run.EndDate = &endDate
+	}
+
+	if data.PresaleStartAt != "" {
+		presale, err := time.ParseInLocation("02-01-2006 15.04", data.PresaleStartAt, loc)
+		if err != nil {
+			return nil, err
+		}
+		opening := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+		if !presale.Before(opening) {
+			return nil, errors.New("pre-sale must start before the start date")
+		}
+		utc := presale.UTC()
+		run.PresaleStartAt = &utc
+	}
+
+	return &run, nil
+}
+
+func toMovieRunResponse(r entity.MovieRun) dto.MovieRunResponse {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	response := dto.MovieRunResponse{
+		MovieRunID: r.ID,
+		MovieID:    r.MovieID,
+		CinemaID:   r.CinemaID,
+		CinemaName: r.CinemaName,
+		Status:     r.Status,
+		StartDate:  r.StartDate.Format("02-01-2006"),
+	}
+	if r.PresaleStartAt != nil {
+		presale := r.PresaleStartAt.In(loc).Format("02-01-2006 15.04")
+		response.PresaleStartAt = &presale
+	}
+	if r.EndDate != nil {
+		endDate := r.EndDate.Format("02-01-2006")
+		response.EndDate = &endDate
+	}
+	return response
+}
+
+func toMovieResponse(m entity.Movie) dto.MovieResponse {
+	return dto.MovieResponse{
+		MovieID:       m.ID,
+		Title:         m.Title,
+		Synopsis:      m.Synopsis,
+		Genres:        m.Genres,
+		PosterURL:     m.PosterURL,
+		TrailerURL:    m.TrailerURL,
+		Duration:      m.Duration,
+		ReleaseDate:   m.ReleaseDate,
+		Language:      m.Language,
+		RatingAge:     m.RatingAge,
+		RatingAverage: m.RatingAverage,
+		RatingCount:   m.RatingCount,
+	}
+}
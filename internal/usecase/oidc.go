@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/oidc"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type OIDCUsecase interface {
+	Start() (*dto.OIDCLoginResponse, error)
+	Callback(data dto.OIDCCallbackRequest) (*dto.AuthResponse, error)
+}
+
+type oidcUsecase struct {
+	Repo     *repository.Repository
+	Logger   *zap.Logger
+	Config   utils.Configuration
+	Provider *oidc.Provider
+}
+
+func NewOIDCUsecase(repo *repository.Repository, log *zap.Logger, config utils.Configuration) OIDCUsecase {
+	return &oidcUsecase{
+		Repo:   repo,
+		Logger: log,
+		Config: config,
+		Provider: oidc.NewProvider(oidc.Config{
+			Name:         config.OIDC.Name,
+			Issuer:       config.OIDC.Issuer,
+			ClientID:     config.OIDC.ClientID,
+			ClientSecret: config.OIDC.ClientSecret,
+			RedirectURL:  config.OIDC.RedirectURL,
+			Scopes:       config.OIDC.Scopes,
+		}),
+	}
+}
+
+func (u *oidcUsecase) Start() (*dto.OIDCLoginResponse, error) {
+	if !u.Provider.Enabled() {
+		return nil, errors.New("external login is not configured")
+	}
+
+	// Generate state, nonce and PKCE verifier
+	state, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.RandomString(48)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := u.Provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		u.Logger.Error("Error build oidc authorization url: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = u.Repo.IdentityRepo.CreateState(entity.OIDCState{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiredAt:    time.Now().Add(10 * time.Minute),
+	})
+	if err != nil {
+		u.Logger.Error("Error create oidc state usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	res := dto.OIDCLoginResponse{
+		Provider:         u.Provider.Name(),
+		AuthorizationURL: authURL,
+		State:            state,
+	}
+	return &res, nil
+}
+
+func (u *oidcUsecase) Callback(data dto.OIDCCallbackRequest) (*dto.AuthResponse, error) {
+	if !u.Provider.Enabled() {
+		return nil, errors.New("external login is not configured")
+	}
+
+	// Only the browser that started the login may finish it
+	if data.BrowserState == "" || subtle.ConstantTimeCompare([]byte(data.BrowserState), []byte(data.State)) != 1 {
+		return nil, errors.New("invalid or expired login request")
+	}
+
+	state, err := u.Repo.IdentityRepo.ConsumeState(data.State)
+	if err != nil {
+		u.Logger.Error("Error consume oidc state usecase: ", zap.Error(err))
+		return nil, errors.New("invalid or expired login request")
+	}
+
+	// Redeem code and verify ID token
+	claims, err := u.Provider.Exchange(data.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		u.Logger.Error("Error exchange oidc code: ", zap.Error(err))
+		return nil, err
+	}
+
+	provider := u.Provider.Name()
+
+	// Returning user
+	user, err := u.Repo.IdentityRepo.FindUser(provider, claims.Subject)
+	if err == nil {
+		return startLogin(u.Repo, u.Logger, u.Config, *user)
+	}
+	if err.Error() != utils.ErrNotFound("user").Error() {
+		u.Logger.Error("Error find user by identity usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Accounts are only matched or created from a verified email
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email == "" || !claims.IsEmailVerified() {
+		return nil, errors.New("email is not verified by the identity provider")
+	}
+
+	existing, _ := u.Repo.UserRepo.FindByEmail(email)
+	if existing != nil {
+		err = u.claimAccount(*existing)
+		if err != nil {
+			return nil, err
+		}
+
+		err = u.Repo.IdentityRepo.Link(existing.ID, provider, claims.Subject, email)
+		if err != nil {
+			u.Logger.Error("Error link identity usecase: ", zap.Error(err))
+			return nil, err
+		}
+
+		return startLogin(u.Repo, u.Logger, u.Config, *existing)
+	}
+
+	// New customer, password login stays unusable until a reset
+	random, err := oidc.RandomString(32)
+	if err != nil {
+		return nil, err
+	}
+	password := utils.HashPassword(random)
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = email[:strings.Index(email, "@")]
+	}
+
+	user, err = u.Repo.IdentityRepo.CreateUser(entity.User{
+		Name:     name,
+		Email:    email,
+		Password: &password,
+		Role:     "customer",
+	}, provider, claims.Subject)
+	if err != nil {
+		u.Logger.Error("Error create user usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	return startLogin(u.Repo, u.Logger, u.Config, *user)
+}
+
+// claimAccount handles a local account whose email was never verified. The
+// provider proved ownership of the address, so whoever registered it without
+// verifying loses the password and any open sessions.
+func (u *oidcUsecase) claimAccount(user entity.User) error {
+	verified, err := u.Repo.IdentityRepo.IsEmailVerified(user.Email)
+	if err != nil {
+		return err
+	}
+	if verified {
+		return nil
+	}
+
+	random, err := oidc.RandomString(32)
+	if err != nil {
+		return err
+	}
+
+	err = u.Repo.UserRepo.UpdatePassword(user.ID, utils.HashPassword(random))
+	if err != nil {
+		u.Logger.Error("Error reset password usecase: ", zap.Error(err))
+		return err
+	}
+
+	err = u.Repo.SessionRepo.RevokeByUserID(user.ID, "")
+	if err != nil {
+		u.Logger.Error("Error revoke sessions usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
@@ -71,7 +74,8 @@ func (u *paymentUsecase) Update(ctx context.Context, b dto.UpdatePayment) error
 		return err
 	}
 
-	if b.Status == "success" {
+	// Settled refunds have no tickets to send
+	if b.Status == "success" && bookingID != nil {
 		u.SendTicket(ctx, *bookingID)
 		return nil
 	}
@@ -80,17 +84,56 @@ func (u *paymentUsecase) Update(ctx context.Context, b dto.UpdatePayment) error
 }
 
 func (u *paymentUsecase) SendTicket(ctx context.Context, id int) error {
-	booking, err := u.repo.BookingRepo.GetByID(id)
+	user := ctx.Value("user").(entity.User)
+
+	// Ticket emails follow the user's language rather than the request
+	res, err := bookingDocuments(u.repo, u.config, user, id, userLocale(user, u.config))
 	if err != nil {
+		u.Logger.Error("Error get ticket email data usecase: ", zap.Error(err))
 		return err
 	}
 
-	screening, err := u.repo.ScreeningRepo.GetByID(booking.ScreeningID)
-	movie, err := u.repo.MovieRepo.GetByID(screening.MovieID)
-	studio, err := u.repo.StudioRepo.GetByID(screening.StudioID)
-	cinema, err := u.repo.CinemaRepo.GetByID(studio.CinemaID)
-	tickets, err := u.repo.TicketRepo.GetByBookingID(id)
-	user := ctx.Value("user").(entity.User)
+	// Send ticket
+	u.ticketJobs <- utils.TicketJob{
+		Config: u.config,
+		Log: u.Logger,
+		Data: *res,
+	}
+
+	return nil
+}
+
+// bookingDocuments gathers what the ticket and invoice PDFs of a booking
+// show. Tickets are the ones the user holds, the invoice is only for the
+// customer who paid for the booking.
+func bookingDocuments(repo *repository.Repository, config utils.Configuration, user entity.User, id int, locale string) (*dto.TicketEmail, error) {
+	booking, err := repo.BookingRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	screening, err := repo.ScreeningRepo.GetByID(booking.ScreeningID)
+	if err != nil {
+		return nil, err
+	}
+	movie, err := repo.MovieRepo.GetByID(screening.MovieID)
+	if err != nil {
+		return nil, err
+	}
+	studio, err := repo.StudioRepo.GetByID(screening.StudioID)
+	if err != nil {
+		return nil, err
+	}
+	cinema, err := repo.CinemaRepo.GetByID(studio.CinemaID)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := repo.TicketRepo.GetByBookingID(id, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	localizeMovie(repo, locale, movie)
+	localizeCinema(repo, locale, cinema)
 
 	res := dto.TicketEmail{
 		Profile: dto.ProfileResponse{
@@ -104,24 +147,63 @@ func (u *paymentUsecase) SendTicket(ctx context.Context, id int) error {
 		Studio: dto.StudioResponse{
 			StudioID: studio.ID,
 			Name: studio.Name,
-			Type: studio.Type,
+			Type: localizeStudioType(studioTypeNames(repo, locale), studio.Type),
 			Price: studio.Price,
 		},
 		Screening: dto.ScreeningResponse{
 			ScreeningID: screening.ID,
 			StartTime: screening.StartTime.Format("15.04"),
+			Format: screening.Format,
+			AudioLanguage: screening.AudioLanguage,
+			SubtitleLanguage: screening.SubtitleLanguage,
+			Price: screening.Price,
 		},
 		Tickets: tickets,
+		Locale: locale,
 	}
 
-	fmt.Println(tickets)
+	if booking.UserID != user.ID || booking.Status != "paid" {
+		return &res, nil
+	}
 
-	// Send ticket
-	u.ticketJobs <- utils.TicketJob{
-		Config: u.config,
-		Log: u.Logger,
-		Data: res,
+	// Invoice, one line per settled payment, charge and refund so it shows
+	// what was actually paid even if prices changed after the sale
+	payment, err := repo.PaymentRepo.GetSettledByBooking(id)
+	if err != nil {
+		return nil, err
+	}
+	history, err := repo.PaymentRepo.GetSettledHistory(id)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
-}
\ No newline at end of file
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	paidAt := payment.UpdatedAt.In(loc)
+	invoice := dto.Invoice{
+		Number: fmt.Sprintf("INV-%s-%06d", paidAt.Format("200601"), booking.ID),
+		IssuedAt: history[len(history)-1].UpdatedAt.In(loc).Format("02-01-2006"),
+		PaymentMethod: payment.PaymentMethod,
+		TaxRate: config.Invoice.TaxRate,
+	}
+	for _, p := range history {
+		amount, err := strconv.ParseFloat(p.Amount, 64)
+		if err != nil {
+			return nil, err
+		}
+		if p.Type == "refund" {
+			amount = -amount
+		}
+		invoice.Lines = append(invoice.Lines, dto.InvoiceLine{
+			Description: fmt.Sprintf(utils.T(locale, "invoice.line_"+p.Type), p.BookingID),
+			Quantity: 1,
+			UnitPrice: amount,
+			Amount: amount,
+		})
+		invoice.Total += amount
+	}
+	invoice.Subtotal = math.Round(invoice.Total/(1+invoice.TaxRate/100)*100) / 100
+	invoice.Tax = invoice.Total - invoice.Subtotal
+	res.Invoice = &invoice
+
+	return &res, nil
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type PersonUsecase interface {
+	GetAll(q dto.PaginationQuery, name string) ([]dto.PersonResponse, *dto.Pagination, error)
+	GetByID(id int) (*dto.PersonResponse, error)
+	Create(data dto.PersonRequest) (*dto.PersonResponse, error)
+	Update(id int, data dto.PersonRequest) error
+	Delete(id int) error
+	GetMovies(id int, locale string) ([]dto.FilmographyResponse, error)
+}
+
+type personUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+}
+
+func NewPersonUsecase(repo *repository.Repository, log *zap.Logger) PersonUsecase {
+	return &personUsecase{
+		Repo:   repo,
+		Logger: log,
+	}
+}
+
+func (u *personUsecase) GetAll(q dto.PaginationQuery, name string) ([]dto.PersonResponse, *dto.Pagination, error) {
+	// Execute repo to get all people
+	people, total, err := u.Repo.PersonRepo.GetAll(q, strings.TrimSpace(name))
+	if err != nil {
+		u.Logger.Error("Error get all people usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	// Create pagination
+	var pagination dto.Pagination
+	if q.All {
+		pagination = dto.Pagination{
+			TotalRecords: total,
+		}
+	} else {
+		totalPages := utils.TotalPage(q.Limit, total)
+		pagination = dto.Pagination{
+			CurrentPage:  &q.Page,
+			Limit:        &q.Limit,
+			TotalPages:   &totalPages,
+			TotalRecords: total,
+		}
+	}
+
+	var response []dto.PersonResponse
+	for _, p := range people {
+		response = append(response, toPersonResponse(p))
+	}
+	return response, &pagination, nil
+}
+
+func (u *personUsecase) GetByID(id int) (*dto.PersonResponse, error) {
+	p, err := u.Repo.PersonRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get person by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+	response := toPersonResponse(*p)
+	return &response, nil
+}
+
+func (u *personUsecase) Create(data dto.PersonRequest) (*dto.PersonResponse, error) {
+	p, err := toPerson(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := u.Repo.PersonRepo.Create(*p)
+	if err != nil {
+		u.Logger.Error("Error create person usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return u.GetByID(id)
+}
+
+func (u *personUsecase) Update(id int, data dto.PersonRequest) error {
+	p, err := toPerson(data)
+	if err != nil {
+		return err
+	}
+
+	err = u.Repo.PersonRepo.Update(id, *p)
+	if err != nil {
+		u.Logger.Error("Error update person usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *personUsecase) Delete(id int) error {
+	err := u.Repo.PersonRepo.Delete(id)
+	if err != nil {
+		u.Logger.Error("Error delete person usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *personUsecase) GetMovies(id int, locale string) ([]dto.FilmographyResponse, error) {
+	// Make sure the person exists so unknown ids give 404 instead of an empty list
+	if _, err := u.Repo.PersonRepo.GetByID(id); err != nil {
+		u.Logger.Error("Error get person by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	credits, err := u.Repo.PersonRepo.GetMovies(id)
+	if err != nil {
+		u.Logger.Error("Error get person movies usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var movies []dto.MovieResponse
+	for _, c := range credits {
+		movies = append(movies, toMovieResponse(*c.Movie))
+	}
+	localizeMovies(u.Repo, locale, movies)
+
+	var response []dto.FilmographyResponse
+	for i, c := range credits {
+		response = append(response, dto.FilmographyResponse{
+			Movie:         movies[i],
+			Role:          c.Role,
+			CharacterName: c.CharacterName,
+		})
+	}
+	return response, nil
+}
+
+func toPerson(data dto.PersonRequest) (*entity.Person, error) {
+	p := entity.Person{
+		Name: strings.TrimSpace(data.Name),
+	}
+	if data.Biography != "" {
+		p.Biography = &data.Biography
+	}
+	if data.PhotoURL != "" {
+		p.PhotoURL = &data.PhotoURL
+	}
+	if data.BirthDate != "" {
+		birthDate, err := time.Parse("2006-01-02", data.BirthDate)
+		if err != nil {
+			return nil, err
+		}
+		p.BirthDate = &birthDate
+	}
+	return &p, nil
+}
+
+func toPersonResponse(p entity.Person) dto.PersonResponse {
+	response := dto.PersonResponse{
+		PersonID:  p.ID,
+		Name:      p.Name,
+		Biography: p.Biography,
+		PhotoURL:  p.PhotoURL,
+	}
+	if p.BirthDate != nil {
+		birthDate := p.BirthDate.Format("2006-01-02")
+		response.BirthDate = &birthDate
+	}
+	return response
+}
+
+func toCreditResponse(c entity.MovieCredit) dto.CreditResponse {
+	return dto.CreditResponse{
+		PersonID:      c.PersonID,
+		Name:          c.PersonName,
+		PhotoURL:      c.PhotoURL,
+		Role:          c.Role,
+		CharacterName: c.CharacterName,
+		Order:         c.Order,
+	}
+}
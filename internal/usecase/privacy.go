@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type PrivacyUsecase interface {
+	RequestExport(userID int) (*dto.DataExportResponse, error)
+	GetExports(userID int) ([]dto.DataExportResponse, error)
+	GetExport(userID int, id int) (*dto.DataExportResponse, error)
+	GetExportFile(userID int, id int) (string, error)
+	EraseAccount(userID int, data dto.DeleteAccountRequest) error
+	Erase(userID int) error
+}
+
+type privacyUsecase struct {
+	Repo       *repository.Repository
+	Logger     *zap.Logger
+	emailJobs  chan<- utils.EmailJob
+	exportJobs chan<- utils.ExportJob
+	Config     utils.Configuration
+}
+
+func NewPrivacyUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan<- utils.EmailJob, exportJobs chan<- utils.ExportJob, config utils.Configuration) PrivacyUsecase {
+	return &privacyUsecase{
+		Repo:       repo,
+		Logger:     log,
+		emailJobs:  emailJobs,
+		exportJobs: exportJobs,
+		Config:     config,
+	}
+}
+
+func (u *privacyUsecase) RequestExport(userID int) (*dto.DataExportResponse, error) {
+	// Reuse an export that is still being generated
+	exports, err := u.Repo.PrivacyRepo.GetExportsByUser(userID)
+	if err != nil {
+		u.Logger.Error("Error get data exports usecase: ", zap.Error(err))
+		return nil, err
+	}
+	for _, e := range exports {
+		if e.Status == "pending" || e.Status == "processing" {
+			response := u.toExportResponse(e)
+			return &response, nil
+		}
+	}
+
+	export, err := u.Repo.PrivacyRepo.CreateExport(userID)
+	if err != nil {
+		u.Logger.Error("Error create data export usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	filePath := filepath.Join(u.Config.ExportPath, fmt.Sprintf("export-%d-%d.zip", userID, export.ID))
+	err = u.Repo.PrivacyRepo.SetExportFile(export.ID, filePath)
+	if err != nil {
+		u.Logger.Error("Error set data export file usecase: ", zap.Error(err))
+		return nil, err
+	}
+	export.Status = "processing"
+
+	// Gather data and generate archive in background
+	exportID := export.ID
+	u.exportJobs <- utils.ExportJob{
+		FilePath: filePath,
+		Gather: func() (*dto.DataExport, error) {
+			data, err := u.Repo.PrivacyRepo.GetExportData(userID)
+			if err != nil {
+				u.Logger.Error("Error get export data usecase: ", zap.Error(err))
+			}
+			return data, err
+		},
+		Done: func(data *dto.DataExport, err error) {
+			u.completeExport(exportID, data, err)
+		},
+	}
+
+	response := u.toExportResponse(*export)
+	return &response, nil
+}
+
+func (u *privacyUsecase) completeExport(id int, data *dto.DataExport, jobErr error) {
+	if jobErr != nil {
+		msg := jobErr.Error()
+		if err := u.Repo.PrivacyRepo.UpdateExportStatus(id, "failed", &msg); err != nil {
+			u.Logger.Error("Error update data export status: ", zap.Error(err))
+		}
+		return
+	}
+
+	if err := u.Repo.PrivacyRepo.UpdateExportStatus(id, "ready", nil); err != nil {
+		u.Logger.Error("Error update data export status: ", zap.Error(err))
+		return
+	}
+
+	// Notify user
+	profile := data.Profile
+	downloadURL := fmt.Sprintf("%s/api/v1/me/exports/%d/download", u.Config.BaseURL, id)
+	content := dto.EmailRequest{
+		To:      profile.Email,
+		Subject: "Your Data Export Is Ready",
+		Body:    utils.SendExportReady(profile.Name, downloadURL),
+	}
+
+	u.emailJobs <- utils.EmailJob{
+		EmailContent: content,
+		Config:       u.Config,
+		Log:          u.Logger,
+	}
+}
+
+func (u *privacyUsecase) GetExports(userID int) ([]dto.DataExportResponse, error) {
+	exports, err := u.Repo.PrivacyRepo.GetExportsByUser(userID)
+	if err != nil {
+		u.Logger.Error("Error get data exports usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var response []dto.DataExportResponse
+	for _, e := range exports {
+		response = append(response, u.toExportResponse(e))
+	}
+	return response, nil
+}
+
+func (u *privacyUsecase) GetExport(userID int, id int) (*dto.DataExportResponse, error) {
+	export, err := u.Repo.PrivacyRepo.GetExport(id, userID)
+	if err != nil {
+		u.Logger.Error("Error get data export usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := u.toExportResponse(*export)
+	return &response, nil
+}
+
+func (u *privacyUsecase) GetExportFile(userID int, id int) (string, error) {
+	export, err := u.Repo.PrivacyRepo.GetExport(id, userID)
+	if err != nil {
+		u.Logger.Error("Error get data export usecase: ", zap.Error(err))
+		return "", err
+	}
+
+	if export.Status != "ready" || export.FilePath == nil {
+		return "", errors.New("data export is not ready")
+	}
+
+	if export.ExpiredAt != nil && export.ExpiredAt.Before(time.Now()) {
+		return "", errors.New("data export has expired")
+	}
+
+	return *export.FilePath, nil
+}
+
+func (u *privacyUsecase) EraseAccount(userID int, data dto.DeleteAccountRequest) error {
+	// Confirm with password before erasing
+	password, err := u.Repo.UserRepo.GetPassword(userID)
+	if err != nil {
+		u.Logger.Error("Error get user password usecase: ", zap.Error(err))
+		return err
+	}
+
+	if !utils.CheckPassword(data.Password, *password) {
+		u.Logger.Error("Incorrect password: ", zap.Error(errors.New("incorrect password")))
+		return errors.New("incorrect password")
+	}
+
+	return u.Erase(userID)
+}
+
+func (u *privacyUsecase) Erase(userID int) error {
+	files, err := u.Repo.PrivacyRepo.Erase(userID)
+	if err != nil {
+		u.Logger.Error("Error erase user usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Remove generated archives
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			u.Logger.Error("Error remove data export file: ", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (u *privacyUsecase) toExportResponse(e entity.DataExport) dto.DataExportResponse {
+	response := dto.DataExportResponse{
+		ExportID:    e.ID,
+		Status:      e.Status,
+		ExpiredAt:   e.ExpiredAt,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: e.CompletedAt,
+	}
+
+	if e.Status == "ready" {
+		url := fmt.Sprintf("%s/api/v1/me/exports/%d/download", u.Config.BaseURL, e.ID)
+		response.DownloadURL = &url
+	}
+	return response
+}
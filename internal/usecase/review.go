@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+var ErrReviewNotAllowed = errors.New("only customers who watched this movie can review it")
+
+type ReviewUsecase interface {
+	Create(userID int, movieID int, data dto.ReviewRequest) (*dto.ReviewResponse, error)
+	GetByMovie(movieID int, q dto.PaginationQuery) ([]dto.ReviewResponse, *dto.Pagination, error)
+	GetByUser(userID int) ([]dto.ReviewResponse, error)
+	GetAll(q dto.PaginationQuery, status string) ([]dto.ReviewResponse, *dto.Pagination, error)
+	Update(id int, userID int, data dto.ReviewRequest) error
+	Moderate(id int, adminID int, data dto.ModerateReviewRequest) error
+	Delete(id int, userID int) error
+	Remove(id int) error
+}
+
+type reviewUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+}
+
+func NewReviewUsecase(repo *repository.Repository, log *zap.Logger) ReviewUsecase {
+	return &reviewUsecase{
+		Repo:   repo,
+		Logger: log,
+	}
+}
+
+func (u *reviewUsecase) Create(userID int, movieID int, data dto.ReviewRequest) (*dto.ReviewResponse, error) {
+	if _, err := u.Repo.MovieRepo.GetByID(movieID); err != nil {
+		u.Logger.Error("Error get movie usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Only verified viewers may review
+	verified, err := u.Repo.ReviewRepo.IsVerifiedViewer(userID, movieID)
+	if err != nil {
+		u.Logger.Error("Error check verified viewer usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if !verified {
+		return nil, ErrReviewNotAllowed
+	}
+
+	id, err := u.Repo.ReviewRepo.Create(entity.Review{
+		MovieID: movieID,
+		UserID:  userID,
+		Rating:  data.Rating,
+		Content: strings.TrimSpace(data.Content),
+	})
+	if err != nil {
+		u.Logger.Error("Error create review usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	rv, err := u.Repo.ReviewRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get review usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toReviewResponse(*rv, true)
+	return &response, nil
+}
+
+func (u *reviewUsecase) GetByMovie(movieID int, q dto.PaginationQuery) ([]dto.ReviewResponse, *dto.Pagination, error) {
+	reviews, total, err := u.Repo.ReviewRepo.GetByMovie(movieID, q)
+	if err != nil {
+		u.Logger.Error("Error get movie reviews usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	var response []dto.ReviewResponse
+	for _, rv := range reviews {
+		response = append(response, toReviewResponse(rv, false))
+	}
+	return response, reviewPagination(q, total), nil
+}
+
+func (u *reviewUsecase) GetByUser(userID int) ([]dto.ReviewResponse, error) {
+	reviews, err := u.Repo.ReviewRepo.GetByUser(userID)
+	if err != nil {
+		u.Logger.Error("Error get user reviews usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var response []dto.ReviewResponse
+	for _, rv := range reviews {
+		response = append(response, toReviewResponse(rv, true))
+	}
+	return response, nil
+}
+
+func (u *reviewUsecase) GetAll(q dto.PaginationQuery, status string) ([]dto.ReviewResponse, *dto.Pagination, error) {
+	if status != "" && status != "pending" && status != "approved" && status != "rejected" {
+		return nil, nil, errors.New("status must be pending, approved or rejected")
+	}
+
+	reviews, total, err := u.Repo.ReviewRepo.GetAll(q, status)
+	if err != nil {
+		u.Logger.Error("Error get reviews usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	var response []dto.ReviewResponse
+	for _, rv := range reviews {
+		response = append(response, toReviewResponse(rv, true))
+	}
+	return response, reviewPagination(q, total), nil
+}
+
+func (u *reviewUsecase) Update(id int, userID int, data dto.ReviewRequest) error {
+	err := u.Repo.ReviewRepo.Update(id, userID, data.Rating, strings.TrimSpace(data.Content))
+	if err != nil {
+		u.Logger.Error("Error update review usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *reviewUsecase) Moderate(id int, adminID int, data dto.ModerateReviewRequest) error {
+	var note *string
+	if n := strings.TrimSpace(data.Note); n != "" {
+		note = &n
+	}
+
+	err := u.Repo.ReviewRepo.Moderate(id, data.Status, note, adminID)
+	if err != nil {
+		u.Logger.Error("Error moderate review usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *reviewUsecase) Delete(id int, userID int) error {
+	err := u.Repo.ReviewRepo.Delete(id, userID)
+	if err != nil {
+		u.Logger.Error("Error delete review usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *reviewUsecase) Remove(id int) error {
+	// Zero user id lets the repository remove any author's review
+	err := u.Repo.ReviewRepo.Delete(id, 0)
+	if err != nil {
+		u.Logger.Error("Error remove review usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func reviewPagination(q dto.PaginationQuery, total int) *dto.Pagination {
+	if q.All {
+		return &dto.Pagination{
+			TotalRecords: total,
+		}
+	}
+	totalPages := utils.TotalPage(q.Limit, total)
+	return &dto.Pagination{
+		CurrentPage:  &q.Page,
+		Limit:        &q.Limit,
+		TotalPages:   &totalPages,
+		TotalRecords: total,
+	}
+}
+
+// toReviewResponse hides moderation details from the public listing
+func toReviewResponse(rv entity.Review, withModeration bool) dto.ReviewResponse {
+	response := dto.ReviewResponse{
+		ReviewID:   rv.ID,
+		MovieID:    rv.MovieID,
+		MovieTitle: rv.MovieTitle,
+		UserName:   rv.UserName,
+		Rating:     rv.Rating,
+		Content:    rv.Content,
+		CreatedAt:  rv.CreatedAt,
+		UpdatedAt:  rv.UpdatedAt,
+	}
+	if withModeration {
+		response.Status = rv.Status
+		response.ModerationNote = rv.ModerationNote
+		response.ModeratedAt = rv.ModeratedAt
+	}
+	return response
+}
@@ -1,8 +1,13 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"github.com/project-app-bioskop-golang/pkg/utils"
@@ -11,10 +16,13 @@ import (
 
 type ScreeningUsecase interface{
 	GetByCinema(q dto.ScreeningQuery) (*dto.MovieByCinema, *dto.Pagination, error)
-	GetByID(id int) (*dto.MovieScreeningRow, error)
+	SearchShowtimes(q dto.ScreeningQuery) (*dto.MovieShowtimes, error)
+	GetByID(id int, locale string) (*dto.MovieScreeningRow, error)
 	Create(s dto.ScreeningRequest) error
 	Update(id int, data dto.UpdateScreeningRequest) error
 	Delete(id int) error
+	GetFormats() ([]dto.ScreeningFormatResponse, error)
+	UpdateFormat(code string, req dto.ScreeningFormatRequest) (*dto.ScreeningFormatResponse, error)
 }
 
 type screeningUsecase struct {
@@ -62,17 +70,19 @@ func (s *screeningUsecase) GetByCinema(q dto.ScreeningQuery) (*dto.MovieByCinema
 		dateStr = time.Now().Format("02-01-2006")
 	}
 
+	names := studioTypeNames(s.Repo, q.Locale)
 	result := map[int]*dto.MovieScreening{}
 	for _, sc := range screenings {
     if _, ok := result[sc.MovieID]; !ok {
 			m, _ := s.Repo.MovieRepo.GetByID(sc.MovieID)
 			st, _ := s.Repo.StudioRepo.GetByID(sc.StudioID)
+			localizeMovie(s.Repo, q.Locale, m)
 			result[sc.MovieID] = &dto.MovieScreening{
 				Movie: *m,
 				Studio: dto.StudioResponse{
 					StudioID: st.ID,
 					Name: st.Name,
-					Type: st.Type,
+					Type: localizeStudioType(names, st.Type),
 					Price: st.Price,
 				},
 			}
@@ -83,11 +93,7 @@ func (s *screeningUsecase) GetByCinema(q dto.ScreeningQuery) (*dto.MovieByCinema
 
     result[sc.MovieID].Screenings = append(
 			result[sc.MovieID].Screenings,
-			dto.ScreeningResponse{
-				ScreeningID: sc.ID,
-				StartTime: startTime,
-				EndTime: endTime,
-			},
+			toScreeningResponse(sc, startTime, endTime),
     )
 	}
 
@@ -104,7 +110,102 @@ func (s *screeningUsecase) GetByCinema(q dto.ScreeningQuery) (*dto.MovieByCinema
 	return &response, &pagination, nil
 }
 
-func (s *screeningUsecase) GetByID(id int) (*dto.MovieScreeningRow, error) {
+// Longest date range a showtime search may cover
+const showtimeMaxDays = 14
+
+func (s *screeningUsecase) SearchShowtimes(q dto.ScreeningQuery) (*dto.MovieShowtimes, error) {
+	// Dates are local, defaulting to today only
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if q.Date != "" {
+		d, err := time.ParseInLocation("02-01-2006", q.Date, loc)
+		if err != nil {
+			return nil, errors.New("date must use DD-MM-YYYY")
+		}
+		from = d
+	}
+	to := from
+	if q.DateTo != "" {
+		d, err := time.ParseInLocation("02-01-2006", q.DateTo, loc)
+		if err != nil {
+			return nil, errors.New("dateTo must use DD-MM-YYYY")
+		}
+		to = d
+	}
+	if to.Before(from) {
+		return nil, errors.New("dateTo must not be before date")
+	}
+	if to.Sub(from) >= showtimeMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("date range must not exceed %d days", showtimeMaxDays)
+	}
+
+	movie, err := s.Repo.MovieRepo.GetByID(q.MovieID)
+	if err != nil {
+		s.Logger.Error("Error get movie by id usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Execute repo to search showtimes, the end date is inclusive
+	showtimes, err := s.Repo.ScreeningRepo.SearchShowtimes(q, from.UTC(), to.AddDate(0, 0, 1).UTC())
+	if err != nil {
+		s.Logger.Error("Error search showtimes usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Rows come ordered by cinema then format, group them in that order
+	cinemas := []dto.CinemaShowtimes{}
+	for _, sh := range showtimes {
+		if len(cinemas) == 0 || cinemas[len(cinemas)-1].Cinema.CinemaID != sh.Cinema.ID {
+			cinema := toCinemaResponse(sh.Cinema)
+			if sh.DistanceKm != nil {
+				distance := math.Round(*sh.DistanceKm*100) / 100
+				cinema.DistanceKm = &distance
+			}
+			cinemas = append(cinemas, dto.CinemaShowtimes{Cinema: cinema})
+		}
+		c := &cinemas[len(cinemas)-1]
+		if len(c.Formats) == 0 || c.Formats[len(c.Formats)-1].Format != sh.Format {
+			c.Formats = append(c.Formats, dto.FormatShowtimes{Format: sh.Format})
+		}
+		f := &c.Formats[len(c.Formats)-1]
+		f.Showtimes = append(f.Showtimes, dto.ShowtimeResponse{
+			ScreeningID: sh.ID,
+			Date: sh.StartTime.Format("02-01-2006"),
+			StartTime: sh.StartTime.Format("15.04"),
+			EndTime: sh.EndTime.Format("15.04"),
+			Studio: sh.StudioName,
+			AudioLanguage: sh.AudioLanguage,
+			SubtitleLanguage: sh.SubtitleLanguage,
+			AudioDescription: sh.AudioDescription,
+			ClosedCaptions: sh.ClosedCaptions,
+			Price: sh.Price,
+			SeatsTotal: sh.SeatsTotal,
+			SeatsAvailable: sh.SeatsAvailable,
+			Availability: availability(sh.SeatsTotal, sh.SeatsAvailable),
+		})
+	}
+
+	localizeMovie(s.Repo, q.Locale, movie)
+	var cinemaList []dto.CinemaResponse
+	for _, c := range cinemas {
+		cinemaList = append(cinemaList, c.Cinema)
+	}
+	localizeCinemas(s.Repo, q.Locale, cinemaList)
+	for i := range cinemas {
+		cinemas[i].Cinema = cinemaList[i]
+	}
+
+	response := dto.MovieShowtimes{
+		Movie: *movie,
+		DateFrom: from.Format("02-01-2006"),
+		DateTo: to.Format("02-01-2006"),
+		Cinemas: cinemas,
+	}
+	return &response, nil
+}
+
+func (s *screeningUsecase) GetByID(id int, locale string) (*dto.MovieScreeningRow, error) {
 	sc, err := s.Repo.ScreeningRepo.GetByID(id)
 	if err != nil {
 		s.Logger.Error("Error get screening by id Usecase: ", zap.Error(err))
@@ -112,6 +213,7 @@ func (s *screeningUsecase) GetByID(id int) (*dto.MovieScreeningRow, error) {
 	}
 	m, _ := s.Repo.MovieRepo.GetByID(sc.MovieID)
 	st, _ := s.Repo.StudioRepo.GetByID(sc.StudioID)
+	localizeMovie(s.Repo, locale, m)
 
 	// Format time into string
 	startTime := sc.StartTime.Format("15.04")
@@ -119,11 +221,7 @@ func (s *screeningUsecase) GetByID(id int) (*dto.MovieScreeningRow, error) {
 	date := sc.StartTime.Format("02-01-2006")
 
 	response := &dto.MovieScreeningRow{
-		Screening: dto.ScreeningResponse{
-			ScreeningID: sc.ID,
-			StartTime: startTime,
-			EndTime: endTime,
-		},
+		Screening: toScreeningResponse(*sc, startTime, endTime),
 		Date: date,
 		Movie: *m,
 		Studio: dto.StudioResponse{
@@ -135,7 +233,12 @@ func (s *screeningUsecase) GetByID(id int) (*dto.MovieScreeningRow, error) {
 }
 
 func (s *screeningUsecase) Create(data dto.ScreeningRequest) error {
-	err := s.Repo.ScreeningRepo.Create(data)
+	err := s.normalizeAttributes(&data.ScreeningAttributes)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repo.ScreeningRepo.Create(data)
 	if err != nil {
 		s.Logger.Error("Error create screening Usecase: ", zap.Error(err))
 		return err
@@ -144,7 +247,12 @@ func (s *screeningUsecase) Create(data dto.ScreeningRequest) error {
 }
 
 func (s *screeningUsecase) Update(id int, data dto.UpdateScreeningRequest) error {
-	err := s.Repo.ScreeningRepo.Update(id, data)
+	err := s.normalizeAttributes(&data.ScreeningAttributes)
+	if err != nil {
+		return err
+	}
+
+	err = s.Repo.ScreeningRepo.Update(id, data)
 	if err != nil {
 		s.Logger.Error("Error update screening Usecase: ", zap.Error(err))
 		return err
@@ -159,4 +267,102 @@ func (s *screeningUsecase) Delete(id int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *screeningUsecase) GetFormats() ([]dto.ScreeningFormatResponse, error) {
+	formats, err := s.Repo.ScreeningRepo.GetFormats()
+	if err != nil {
+		s.Logger.Error("Error get screening formats Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := []dto.ScreeningFormatResponse{}
+	for _, f := range formats {
+		response = append(response, dto.ScreeningFormatResponse{
+			Code:      f.Code,
+			Name:      f.Name,
+			Surcharge: f.Surcharge,
+		})
+	}
+	return response, nil
+}
+
+func (s *screeningUsecase) UpdateFormat(code string, req dto.ScreeningFormatRequest) (*dto.ScreeningFormatResponse, error) {
+	f, err := s.Repo.ScreeningRepo.UpdateFormat(strings.ToUpper(code), req)
+	if err != nil {
+		s.Logger.Error("Error update screening format Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &dto.ScreeningFormatResponse{
+		Code:      f.Code,
+		Name:      f.Name,
+		Surcharge: f.Surcharge,
+	}, nil
+}
+
+// normalizeAttributes defaults the format to 2D and checks it is a known format
+func (s *screeningUsecase) normalizeAttributes(a *dto.ScreeningAttributes) error {
+	a.Format = strings.ToUpper(strings.TrimSpace(a.Format))
+	if a.Format == "" {
+		a.Format = "2D"
+	}
+	a.AudioLanguage = trimOptional(a.AudioLanguage)
+	a.SubtitleLanguage = trimOptional(a.SubtitleLanguage)
+
+	formats, err := s.Repo.ScreeningRepo.GetFormats()
+	if err != nil {
+		s.Logger.Error("Error get screening formats Usecase: ", zap.Error(err))
+		return err
+	}
+	for _, f := range formats {
+		if f.Code == a.Format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown screening format %q", a.Format)
+}
+
+// trimOptional trims an optional text field, blank becomes unset
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func toScreeningResponse(sc entity.Screening, startTime string, endTime string) dto.ScreeningResponse {
+	return dto.ScreeningResponse{
+		ScreeningID:      sc.ID,
+		StartTime:        startTime,
+		EndTime:          endTime,
+		Format:           sc.Format,
+		AudioLanguage:    sc.AudioLanguage,
+		SubtitleLanguage: sc.SubtitleLanguage,
+		AudioDescription: sc.AudioDescription,
+		ClosedCaptions:   sc.ClosedCaptions,
+		Price:            sc.Price,
+		SeatsTotal:       sc.SeatsTotal,
+		SeatsAvailable:   sc.SeatsAvailable,
+		Availability:     availability(sc.SeatsTotal, sc.SeatsAvailable),
+	}
+}
+
+// A screening is almost full once this share of its seats or fewer is left
+const almostFullRatio = 0.1
+
+// availability labels a screening sold_out, almost_full or available
+func availability(total int, available int) string {
+	switch {
+	case available <= 0:
+		return "sold_out"
+	case float64(available) <= float64(total)*almostFullRatio:
+		return "almost_full"
+	default:
+		return "available"
+	}
+}
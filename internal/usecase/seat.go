@@ -1,13 +1,17 @@
 package usecase
 
 import (
+	"errors"
+
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/seatpicker"
 	"go.uber.org/zap"
 )
 
 type SeatUsecase interface {
 	GetSeats(screeningID int) ([]dto.SeatResponse, error)
+	Suggest(q dto.SeatSuggestionQuery) (*dto.SeatSuggestion, error)
 }
 
 type seatUsecase struct {
@@ -29,4 +33,57 @@ func (u *seatUsecase) GetSeats(screeningID int) ([]dto.SeatResponse, error) {
 		return nil, err
 	}
 	return seats, nil
-}
\ No newline at end of file
+}
+
+// Suggest picks the best block of adjacent free seats for the party
+func (u *seatUsecase) Suggest(q dto.SeatSuggestionQuery) (*dto.SeatSuggestion, error) {
+	seats, err := u.repo.SeatRepo.GetSeats(q.ScreeningID)
+	if err != nil {
+		u.Logger.Error("Error get available seats usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	var layout []seatpicker.Seat
+	for _, s := range seats {
+		row, column, err := seatpicker.ParseCode(s.SeatCode)
+		if err != nil {
+			u.Logger.Error("Error parse seat code: ", zap.Error(err))
+			continue
+		}
+		layout = append(layout, seatpicker.Seat{
+			ID: s.ID,
+			Code: s.SeatCode,
+			Row: row,
+			Column: column,
+			Available: s.Status != nil && *s.Status == "available",
+			Accessible: s.Accessible,
+		})
+	}
+
+	best, err := seatpicker.Best(layout, q.PartySize, seatpicker.Preferences{
+		Aisle: q.Aisle,
+		Accessible: q.Accessible,
+	})
+	if errors.Is(err, seatpicker.ErrNoBlock) {
+		return nil, errors.New("no adjacent seats left for this party size and preferences")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := dto.SeatSuggestion{
+		ScreeningID: q.ScreeningID,
+		PartySize: q.PartySize,
+		Score: best.Score,
+	}
+	for _, s := range best.Seats {
+		suggestion.Seats = append(suggestion.Seats, s.ID)
+		suggestion.SeatCodes = append(suggestion.SeatCodes, s.Code)
+		suggestion.Details = append(suggestion.Details, dto.SeatResponse{
+			ID: s.ID,
+			SeatCode: s.Code,
+			Accessible: s.Accessible,
+		})
+	}
+	return &suggestion, nil
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"strings"
+
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
 	"github.com/project-app-bioskop-golang/internal/dto"
@@ -11,10 +13,11 @@ import (
 type StudioUsecase interface{
 	CreateStudioType(req dto.StudioType) (*entity.StudioType, error)
 	GetAll(q dto.PaginationQuery) ([]entity.Studio, *dto.Pagination, error)
-	GetByID(id int) (*entity.Studio, error)
+	GetByID(id int, locale string) (*entity.Studio, error)
 	Create(data dto.StudioRequest) (*entity.Studio, error)
 	Update(id int, data dto.StudioRequest) error
 	Delete(id int) error
+	SetAccessibleSeats(id int, data dto.AccessibleSeatsRequest) error
 }
 
 type studioUsecase struct {
@@ -66,15 +69,21 @@ func (s *studioUsecase) GetAll(q dto.PaginationQuery) ([]entity.Studio, *dto.Pag
 		}
 	}
 
+	names := studioTypeNames(s.Repo, q.Locale)
+	for i := range studios {
+		studios[i].Type = localizeStudioType(names, studios[i].Type)
+	}
+
 	return studios, &pagination, nil
 }
 
-func (s *studioUsecase) GetByID(id int) (*entity.Studio, error) {
+func (s *studioUsecase) GetByID(id int, locale string) (*entity.Studio, error) {
 	studio, err := s.Repo.StudioRepo.GetByID(id)
 	if err != nil {
 		s.Logger.Error("Error get studio by id Usecase: ", zap.Error(err))
 		return nil, err
 	}
+	studio.Type = localizeStudioType(studioTypeNames(s.Repo, locale), studio.Type)
 	return studio, err
 }
 
@@ -103,4 +112,23 @@ func (s *studioUsecase) Delete(id int) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (s *studioUsecase) SetAccessibleSeats(id int, data dto.AccessibleSeatsRequest) error {
+	codes := []string{}
+	seen := map[string]bool{}
+	for _, code := range data.SeatCodes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	err := s.Repo.StudioRepo.SetAccessibleSeats(id, codes)
+	if err != nil {
+		s.Logger.Error("Error set accessible seats Usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
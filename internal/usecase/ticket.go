@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TicketUsecase interface {
+	GetByHolder(userID int, locale string) ([]dto.TicketResponse, error)
+	CheckIn(data dto.CheckInRequest, locale string) (*dto.TicketResponse, error)
+	Transfer(ctx context.Context, ticketID int, data dto.TransferRequest, locale string) (*dto.TransferResponse, error)
+	GetTransfers(userID int, locale string) ([]dto.TransferResponse, error)
+	AcceptTransfer(ctx context.Context, id int, locale string) (*dto.TransferResponse, error)
+	CancelTransfer(id int, userID int) error
+}
+
+type ticketUsecase struct {
+	Repo      *repository.Repository
+	Logger    *zap.Logger
+	emailJobs chan<- utils.EmailJob
+	payment   PaymentUsecase
+	Config    utils.Configuration
+}
+
+func NewTicketUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan<- utils.EmailJob, payment PaymentUsecase, config utils.Configuration) TicketUsecase {
+	return &ticketUsecase{
+		Repo:      repo,
+		Logger:    log,
+		emailJobs: emailJobs,
+		payment:   payment,
+		Config:    config,
+	}
+}
+
+func (u *ticketUsecase) GetByHolder(userID int, locale string) ([]dto.TicketResponse, error) {
+	tickets, err := u.Repo.TicketRepo.GetByHolder(userID)
+	if err != nil {
+		u.Logger.Error("Error get held tickets usecase: ", zap.Error(err))
+		return nil, err
+	}
+	localizeTickets(u.Repo, locale, tickets)
+
+	var response []dto.TicketResponse
+	for _, t := range tickets {
+		response = append(response, toTicketResponse(t))
+	}
+	return response, nil
+}
+
+func (u *ticketUsecase) CheckIn(data dto.CheckInRequest, locale string) (*dto.TicketResponse, error) {
+	ticket, err := u.Repo.TicketRepo.CheckIn(data.QRToken)
+	if err != nil {
+		u.Logger.Error("Error check in ticket usecase: ", zap.Error(err))
+		return nil, err
+	}
+	tickets := []entity.Ticket{*ticket}
+	localizeTickets(u.Repo, locale, tickets)
+
+	response := toTicketResponse(tickets[0])
+	return &response, nil
+}
+
+// Transfer offers a ticket to another registered user and emails them
+func (u *ticketUsecase) Transfer(ctx context.Context, ticketID int, data dto.TransferRequest, locale string) (*dto.TransferResponse, error) {
+	user := ctx.Value("user").(entity.User)
+
+	recipient, err := u.Repo.UserRepo.FindByEmail(data.Email)
+	if err != nil {
+		u.Logger.Error("Error find transfer recipient usecase: ", zap.Error(err))
+		return nil, errors.New("no registered user with this email")
+	}
+	if recipient.ID == user.ID {
+		return nil, errors.New("cannot transfer a ticket to yourself")
+	}
+
+	id, err := u.Repo.TransferRepo.Create(ticketID, user.ID, recipient.ID, time.Now().Add(u.Config.TransferCutoff))
+	if err != nil {
+		u.Logger.Error("Error create ticket transfer usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	transfer, err := u.Repo.TransferRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get ticket transfer usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Format email content in the recipient's language
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	recipientLocale := userLocale(*recipient, u.Config)
+	mail := []entity.TicketTransfer{*transfer}
+	localizeTransfers(u.Repo, recipientLocale, mail)
+	body := utils.SendTicketTransfer(dto.TransferEmail{
+		Name:       recipient.Name,
+		FromName:   user.Name,
+		MovieTitle: mail[0].MovieTitle,
+		CinemaName: mail[0].CinemaName,
+		Date:       mail[0].StartTime.In(loc).Format("02-01-2006"),
+		StartTime:  mail[0].StartTime.In(loc).Format("15.04"),
+		SeatCode:   mail[0].SeatCode,
+		Locale:     recipientLocale,
+	})
+	content := dto.EmailRequest{
+		To:      recipient.Email,
+		Subject: utils.T(recipientLocale, "transfer.subject"),
+		Body:    body,
+	}
+
+	// Send notification
+	u.emailJobs <- utils.EmailJob{
+		EmailContent: content,
+		Config:       u.Config,
+		Log:          u.Logger,
+	}
+
+	transfers := []entity.TicketTransfer{*transfer}
+	localizeTransfers(u.Repo, locale, transfers)
+	response := toTransferResponse(transfers[0], user.ID)
+	return &response, nil
+}
+
+func (u *ticketUsecase) GetTransfers(userID int, locale string) ([]dto.TransferResponse, error) {
+	transfers, err := u.Repo.TransferRepo.GetByUser(userID)
+	if err != nil {
+		u.Logger.Error("Error get ticket transfers usecase: ", zap.Error(err))
+		return nil, err
+	}
+	localizeTransfers(u.Repo, locale, transfers)
+
+	var response []dto.TransferResponse
+	for _, t := range transfers {
+		response = append(response, toTransferResponse(t, userID))
+	}
+	return response, nil
+}
+
+// AcceptTransfer moves the ticket to the recipient and emails them the new one
+func (u *ticketUsecase) AcceptTransfer(ctx context.Context, id int, locale string) (*dto.TransferResponse, error) {
+	user := ctx.Value("user").(entity.User)
+
+	accepted, err := u.Repo.TransferRepo.Accept(id, user.ID, time.Now().Add(u.Config.TransferCutoff))
+	if err != nil {
+		u.Logger.Error("Error accept ticket transfer usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Send ticket
+	u.payment.SendTicket(ctx, accepted.BookingID)
+
+	transfer, err := u.Repo.TransferRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get ticket transfer usecase: ", zap.Error(err))
+		return nil, err
+	}
+	transfers := []entity.TicketTransfer{*transfer}
+	localizeTransfers(u.Repo, locale, transfers)
+
+	response := toTransferResponse(transfers[0], user.ID)
+	return &response, nil
+}
+
+func (u *ticketUsecase) CancelTransfer(id int, userID int) error {
+	err := u.Repo.TransferRepo.Cancel(id, userID)
+	if err != nil {
+		u.Logger.Error("Error cancel ticket transfer usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func toTicketResponse(t entity.Ticket) dto.TicketResponse {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	return dto.TicketResponse{
+		TicketID:    t.ID,
+		BookingID:   t.BookingID,
+		ScreeningID: t.ScreeningID,
+		MovieTitle:  t.MovieTitle,
+		CinemaName:  t.CinemaName,
+		StudioName:  t.StudioName,
+		Date:        t.StartTime.In(loc).Format("02-01-2006"),
+		StartTime:   t.StartTime.In(loc).Format("15.04"),
+		SeatCode:    t.SeatCode,
+		QRToken:     t.QRToken,
+		Received:    t.HolderUserID != nil && *t.HolderUserID != t.OwnerID,
+		CheckedInAt: t.CheckedInAt,
+	}
+}
+
+func toTransferResponse(t entity.TicketTransfer, userID int) dto.TransferResponse {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	return dto.TransferResponse{
+		TransferID:  t.ID,
+		TicketID:    t.TicketID,
+		BookingID:   t.BookingID,
+		ScreeningID: t.ScreeningID,
+		MovieTitle:  t.MovieTitle,
+		CinemaName:  t.CinemaName,
+		Date:        t.StartTime.In(loc).Format("02-01-2006"),
+		StartTime:   t.StartTime.In(loc).Format("15.04"),
+		SeatCode:    t.SeatCode,
+		From:        t.FromName,
+		To:          t.ToEmail,
+		Incoming:    t.ToUserID == userID,
+		Status:      t.Status,
+		NewTicketID: t.NewTicketID,
+		CreatedAt:   t.CreatedAt,
+	}
+}
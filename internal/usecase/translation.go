@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type TranslationUsecase interface {
+	GetAll(kind string, id int) ([]entity.Translation, error)
+	Save(kind string, id int, locale string, fields map[string]string) (*entity.Translation, error)
+	Delete(kind string, id int, locale string) error
+}
+
+type translationUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+	Config utils.Configuration
+}
+
+func NewTranslationUsecase(repo *repository.Repository, log *zap.Logger, config utils.Configuration) TranslationUsecase {
+	return &translationUsecase{
+		Repo:   repo,
+		Logger: log,
+		Config: config,
+	}
+}
+
+// TranslationRecord names the record behind a translation type, as used in its not found error
+func TranslationRecord(kind string) string {
+	return repository.TranslationTables[kind].Name
+}
+
+func (u *translationUsecase) GetAll(kind string, id int) ([]entity.Translation, error) {
+	table, ok := repository.TranslationTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation type %q", kind)
+	}
+
+	translations, err := u.Repo.TranslationRepo.GetAll(table, id)
+	if err != nil {
+		u.Logger.Error("Error get translations usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (u *translationUsecase) Save(kind string, id int, locale string, fields map[string]string) (*entity.Translation, error) {
+	table, ok := repository.TranslationTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation type %q", kind)
+	}
+
+	locale, err := u.checkLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every required field must be given, nothing else is stored
+	allowed := map[string]bool{}
+	for _, f := range table.Required {
+		allowed[f] = true
+		if strings.TrimSpace(fields[f]) == "" {
+			return nil, fmt.Errorf("%s is required", f)
+		}
+	}
+	for _, f := range table.Optional {
+		allowed[f] = true
+	}
+	cleaned := map[string]string{}
+	for f, v := range fields {
+		if !allowed[f] {
+			return nil, fmt.Errorf("%s cannot be translated", f)
+		}
+		cleaned[f] = strings.TrimSpace(v)
+	}
+
+	translation, err := u.Repo.TranslationRepo.Save(table, id, locale, cleaned)
+	if err != nil {
+		u.Logger.Error("Error save translation usecase: ", zap.Error(err))
+		return nil, err
+	}
+	return translation, nil
+}
+
+func (u *translationUsecase) Delete(kind string, id int, locale string) error {
+	table, ok := repository.TranslationTables[kind]
+	if !ok {
+		return fmt.Errorf("unknown translation type %q", kind)
+	}
+
+	err := u.Repo.TranslationRepo.Delete(table, id, strings.ToLower(locale))
+	if err != nil {
+		u.Logger.Error("Error delete translation usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// checkLocale accepts supported locales other than the default, whose text
+// lives on the record itself
+func (u *translationUsecase) checkLocale(locale string) (string, error) {
+	locale = strings.ToLower(locale)
+	if locale == u.Config.DefaultLocale {
+		return "", fmt.Errorf("%s is the default locale, edit the record itself", locale)
+	}
+	if !utils.IsSupportedLocale(locale, u.Config) {
+		return "", fmt.Errorf("locale must be one of %s", strings.Join(u.Config.Locales, ", "))
+	}
+	return locale, nil
+}
+
+// userLocale is the language used for emails to a user
+func userLocale(user entity.User, config utils.Configuration) string {
+	if user.Locale != "" && utils.IsSupportedLocale(user.Locale, config) {
+		return user.Locale
+	}
+	return config.DefaultLocale
+}
+
+// localizeMovies swaps titles, synopses and genre names for their translation.
+// Anything without a translation keeps the default locale text.
+func localizeMovies(repo *repository.Repository, locale string, movies []dto.MovieResponse) {
+	if locale == "" || len(movies) == 0 {
+		return
+	}
+
+	var ids []int
+	for _, m := range movies {
+		ids = append(ids, m.MovieID)
+	}
+	texts, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["movies"], locale, ids)
+	if err != nil {
+		return
+	}
+	genres, err := repo.TranslationRepo.GetNames(repository.TranslationTables["genres"], locale)
+	if err != nil {
+		return
+	}
+
+	for i := range movies {
+		if t, ok := texts[movies[i].MovieID]; ok {
+			movies[i].Title = t["title"]
+			movies[i].Synopsis = t["synopsis"]
+		}
+		for j, g := range movies[i].Genres {
+			if name, ok := genres[g]; ok {
+				movies[i].Genres[j] = name
+			}
+		}
+	}
+}
+
+func localizeMovie(repo *repository.Repository, locale string, movie *dto.MovieResponse) {
+	if movie == nil {
+		return
+	}
+	movies := []dto.MovieResponse{*movie}
+	localizeMovies(repo, locale, movies)
+	*movie = movies[0]
+}
+
+func localizeCinemas(repo *repository.Repository, locale string, cinemas []dto.CinemaResponse) {
+	if locale == "" || len(cinemas) == 0 {
+		return
+	}
+
+	var ids []int
+	for _, c := range cinemas {
+		ids = append(ids, c.CinemaID)
+	}
+	texts, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["cinemas"], locale, ids)
+	if err != nil {
+		return
+	}
+
+	for i := range cinemas {
+		if t, ok := texts[cinemas[i].CinemaID]; ok {
+			cinemas[i].Name = t["name"]
+			if location, ok := t["location"]; ok {
+				cinemas[i].Location = location
+			}
+		}
+	}
+}
+
+func localizeCinema(repo *repository.Repository, locale string, cinema *dto.CinemaResponse) {
+	if cinema == nil {
+		return
+	}
+	cinemas := []dto.CinemaResponse{*cinema}
+	localizeCinemas(repo, locale, cinemas)
+	*cinema = cinemas[0]
+}
+
+// studioTypeNames maps studio types to their translation in the locale
+func studioTypeNames(repo *repository.Repository, locale string) map[string]string {
+	if locale == "" {
+		return map[string]string{}
+	}
+	names, err := repo.TranslationRepo.GetNames(repository.TranslationTables["studio-types"], locale)
+	if err != nil {
+		return map[string]string{}
+	}
+	return names
+}
+
+func localizeStudioType(names map[string]string, studioType string) string {
+	if name, ok := names[studioType]; ok {
+		return name
+	}
+	return studioType
+}
+
+// localizeWaitlist swaps the movie titles of waitlist entries
+func localizeWaitlist(repo *repository.Repository, locale string, entries []entity.WaitlistEntry) {
+	if locale == "" || len(entries) == 0 {
+		return
+	}
+
+	var movieIDs []int
+	for _, e := range entries {
+		movieIDs = append(movieIDs, e.MovieID)
+	}
+	movies, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["movies"], locale, movieIDs)
+	if err != nil {
+		return
+	}
+
+	for i := range entries {
+		if t, ok := movies[entries[i].MovieID]; ok {
+			entries[i].MovieTitle = t["title"]
+		}
+	}
+}
+
+func localizeTickets(repo *repository.Repository, locale string, tickets []entity.Ticket) {
+	if locale == "" || len(tickets) == 0 {
+		return
+	}
+
+	var movieIDs []int
+	for _, t := range tickets {
+		movieIDs = append(movieIDs, t.MovieID)
+	}
+	movies, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["movies"], locale, movieIDs)
+	if err != nil {
+		return
+	}
+
+	for i := range tickets {
+		if t, ok := movies[tickets[i].MovieID]; ok {
+			tickets[i].MovieTitle = t["title"]
+		}
+	}
+}
+
+func localizeTransfers(repo *repository.Repository, locale string, transfers []entity.TicketTransfer) {
+	if locale == "" || len(transfers) == 0 {
+		return
+	}
+
+	var movieIDs []int
+	for _, t := range transfers {
+		movieIDs = append(movieIDs, t.MovieID)
+	}
+	movies, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["movies"], locale, movieIDs)
+	if err != nil {
+		return
+	}
+
+	for i := range transfers {
+		if t, ok := movies[transfers[i].MovieID]; ok {
+			transfers[i].MovieTitle = t["title"]
+		}
+	}
+}
+
+func localizeSale(repo *repository.Repository, locale string, sale *entity.BoxOfficeSale) {
+	if locale == "" {
+		return
+	}
+
+	movies, err := repo.TranslationRepo.GetTexts(repository.TranslationTables["movies"], locale, []int{sale.MovieID})
+	if err != nil {
+		return
+	}
+	if t, ok := movies[sale.MovieID]; ok {
+		sale.MovieTitle = t["title"]
+	}
+}
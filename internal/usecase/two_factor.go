@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"github.com/skip2/go-qrcode"
+	"go.uber.org/zap"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorUsecase interface {
+	GetStatus(user entity.User) (*dto.TwoFactorStatusResponse, error)
+	Setup(user entity.User) (*dto.TwoFactorSetupResponse, error)
+	Enable(userID int, data dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error)
+	Disable(user entity.User, data dto.TwoFactorDisableRequest) error
+	RegenerateRecoveryCodes(userID int, data dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error)
+	IsEnabled(userID int) (bool, error)
+	IsRequired(user entity.User) bool
+}
+
+type twoFactorUsecase struct {
+	Repo   *repository.Repository
+	Logger *zap.Logger
+	Config utils.Configuration
+}
+
+func NewTwoFactorUsecase(repo *repository.Repository, log *zap.Logger, config utils.Configuration) TwoFactorUsecase {
+	return &twoFactorUsecase{
+		Repo:   repo,
+		Logger: log,
+		Config: config,
+	}
+}
+
+func (u *twoFactorUsecase) GetStatus(user entity.User) (*dto.TwoFactorStatusResponse, error) {
+	res := dto.TwoFactorStatusResponse{
+		Required: u.IsRequired(user),
+	}
+
+	totp, err := u.Repo.TwoFactorRepo.GetTOTP(user.ID)
+	if err != nil && err.Error() == utils.ErrNotFound("two factor").Error() {
+		return &res, nil
+	}
+	if err != nil {
+		u.Logger.Error("Error get totp usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	if totp.EnabledAt == nil {
+		return &res, nil
+	}
+
+	codes, err := u.Repo.TwoFactorRepo.GetRecoveryCodes(user.ID)
+	if err != nil {
+		u.Logger.Error("Error get recovery codes usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	res.Enabled = true
+	res.EnabledAt = totp.EnabledAt
+	res.RecoveryCodesLeft = len(codes)
+	return &res, nil
+}
+
+func (u *twoFactorUsecase) Setup(user entity.User) (*dto.TwoFactorSetupResponse, error) {
+	enabled, err := u.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.New("two factor authentication is already enabled")
+	}
+
+	// Generate secret, enrolment is pending until a code is confirmed
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		u.Logger.Error("Error generate totp secret: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = u.Repo.TwoFactorRepo.SaveSecret(user.ID, secret)
+	if err != nil {
+		u.Logger.Error("Error save totp secret usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// QR provisioning for authenticator apps
+	uri := utils.TOTPURI(u.Config.AppName, user.Email, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		u.Logger.Error("Error generate QR code: ", zap.Error(err))
+		return nil, err
+	}
+
+	res := dto.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURL: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}
+	return &res, nil
+}
+
+func (u *twoFactorUsecase) Enable(userID int, data dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	totp, err := u.Repo.TwoFactorRepo.GetTOTP(userID)
+	if err != nil && err.Error() == utils.ErrNotFound("two factor").Error() {
+		return nil, errors.New("two factor setup has not been started")
+	}
+	if err != nil {
+		u.Logger.Error("Error get totp usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if totp.EnabledAt != nil {
+		return nil, errors.New("two factor authentication is already enabled")
+	}
+
+	// Confirm the authenticator app is configured correctly
+	err = verifyTOTP(u.Repo, u.Logger, totp, data.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		u.Logger.Error("Error generate recovery codes: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = u.Repo.TwoFactorRepo.Enable(userID, hashes)
+	if err != nil {
+		u.Logger.Error("Error enable two factor usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *twoFactorUsecase) Disable(user entity.User, data dto.TwoFactorDisableRequest) error {
+	if u.IsRequired(user) {
+		return errors.New("two factor authentication is mandatory for this account")
+	}
+
+	// Confirm with password and a second factor
+	password, err := u.Repo.UserRepo.GetPassword(user.ID)
+	if err != nil {
+		u.Logger.Error("Error get user password usecase: ", zap.Error(err))
+		return err
+	}
+
+	if !utils.CheckPassword(data.Password, *password) {
+		u.Logger.Error("Incorrect password: ", zap.Error(errors.New("incorrect password")))
+		return errors.New("incorrect password")
+	}
+
+	err = verifySecondFactor(u.Repo, u.Logger, user.ID, data.Code)
+	if err != nil {
+		return err
+	}
+
+	err = u.Repo.TwoFactorRepo.Disable(user.ID)
+	if err != nil {
+		u.Logger.Error("Error disable two factor usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (u *twoFactorUsecase) RegenerateRecoveryCodes(userID int, data dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	totp, err := u.Repo.TwoFactorRepo.GetTOTP(userID)
+	if err != nil && err.Error() == utils.ErrNotFound("two factor").Error() {
+		return nil, errors.New("two factor authentication is not enabled")
+	}
+	if err != nil {
+		u.Logger.Error("Error get totp usecase: ", zap.Error(err))
+		return nil, err
+	}
+	if totp.EnabledAt == nil {
+		return nil, errors.New("two factor authentication is not enabled")
+	}
+
+	// Only a fresh authenticator code can replace recovery codes
+	err = verifyTOTP(u.Repo, u.Logger, totp, data.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		u.Logger.Error("Error generate recovery codes: ", zap.Error(err))
+		return nil, err
+	}
+
+	err = u.Repo.TwoFactorRepo.ReplaceRecoveryCodes(userID, hashes)
+	if err != nil {
+		u.Logger.Error("Error replace recovery codes usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *twoFactorUsecase) IsEnabled(userID int) (bool, error) {
+	return isTwoFactorEnabled(u.Repo, u.Logger, userID)
+}
+
+func (u *twoFactorUsecase) IsRequired(user entity.User) bool {
+	return u.Config.RequireAdmin2FA && user.Role == "admin"
+}
+
+func isTwoFactorEnabled(repo *repository.Repository, log *zap.Logger, userID int) (bool, error) {
+	totp, err := repo.TwoFactorRepo.GetTOTP(userID)
+	if err != nil && err.Error() == utils.ErrNotFound("two factor").Error() {
+		return false, nil
+	}
+	if err != nil {
+		log.Error("Error get totp usecase: ", zap.Error(err))
+		return false, err
+	}
+	return totp.EnabledAt != nil, nil
+}
+
+// verifySecondFactor accepts either an authenticator code or an unused recovery code.
+func verifySecondFactor(repo *repository.Repository, log *zap.Logger, userID int, code string) error {
+	totp, err := repo.TwoFactorRepo.GetTOTP(userID)
+	if err != nil && err.Error() == utils.ErrNotFound("two factor").Error() {
+		return errors.New("two factor authentication is not enabled")
+	}
+	if err != nil {
+		log.Error("Error get totp usecase: ", zap.Error(err))
+		return err
+	}
+	if totp.EnabledAt == nil {
+		return errors.New("two factor authentication is not enabled")
+	}
+
+	if len(code) == 6 {
+		return verifyTOTP(repo, log, totp, code)
+	}
+
+	codes, err := repo.TwoFactorRepo.GetRecoveryCodes(userID)
+	if err != nil {
+		log.Error("Error get recovery codes usecase: ", zap.Error(err))
+		return err
+	}
+
+	normalized := utils.NormalizeRecoveryCode(code)
+	for _, c := range codes {
+		if !utils.CheckPassword(normalized, c.CodeHash) {
+			continue
+		}
+
+		used, err := repo.TwoFactorRepo.UseRecoveryCode(c.ID)
+		if err != nil {
+			log.Error("Error use recovery code usecase: ", zap.Error(err))
+			return err
+		}
+		if !used {
+			break
+		}
+		return nil
+	}
+
+	return errors.New("invalid two factor code")
+}
+
+func verifyTOTP(repo *repository.Repository, log *zap.Logger, totp *entity.UserTOTP, code string) error {
+	step, ok := utils.ValidateTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return errors.New("invalid two factor code")
+	}
+
+	// Reject replay of a code that was already accepted
+	fresh, err := repo.TwoFactorRepo.MarkStepUsed(totp.UserID, step)
+	if err != nil {
+		log.Error("Error mark totp step used usecase: ", zap.Error(err))
+		return err
+	}
+	if !fresh {
+		return errors.New("two factor code has already been used")
+	}
+	return nil
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, utils.HashPassword(utils.NormalizeRecoveryCode(c)))
+	}
+	return codes, hashes, nil
+}
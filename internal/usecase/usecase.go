@@ -17,19 +17,48 @@ type Usecase struct {
 	SeatUsecase SeatUsecase
 	BookingUsecase BookingUsecase
 	PaymentUsecase PaymentUsecase
+	PrivacyUsecase PrivacyUsecase
+	TwoFactorUsecase TwoFactorUsecase
+	APIKeyUsecase APIKeyUsecase
+	OIDCUsecase OIDCUsecase
+	MovieRunUsecase MovieRunUsecase
+	PersonUsecase PersonUsecase
+	ReviewUsecase ReviewUsecase
+	MediaUsecase MediaUsecase
+	CatalogUsecase CatalogUsecase
+	TranslationUsecase TranslationUsecase
+	WaitlistUsecase WaitlistUsecase
+	TicketUsecase TicketUsecase
+	BoxOfficeUsecase BoxOfficeUsecase
 }
 
-func NewUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan <- utils.EmailJob, ticketJobs chan <- utils.TicketJob, config utils.Configuration) Usecase {
+func NewUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan <- utils.EmailJob, ticketJobs chan <- utils.TicketJob, exportJobs chan <- utils.ExportJob, config utils.Configuration) Usecase {
+	waitlist := NewWaitlistUsecase(repo, log, emailJobs, config)
+	payment := NewPaymentUsecase(repo, log, ticketJobs, config)
+
 	return Usecase{
 		AuthUsecase: NewAuthUsecase(repo, log, emailJobs, config),
-		UserUsecase: NewUserUsecase(repo, log),
+		UserUsecase: NewUserUsecase(repo, log, emailJobs, config),
 		CinemaUsecase: NewCinemaUsecase(repo, log),
 		StudioUsecase: NewStudioUsecase(repo, log),
 		GenreUsecase: NewGenreUsecase(repo, log),
 		MovieUsecase: NewMovieUsecase(repo, log),
 		ScreeningUsecase: NewScreeningUsecase(repo, log),
 		SeatUsecase: NewSeatUsecase(repo, log),
-		BookingUsecase: NewBookingUsecase(repo, log),
-		PaymentUsecase: NewPaymentUsecase(repo, log, ticketJobs, config),
+		BookingUsecase: NewBookingUsecase(repo, log, waitlist, payment, config),
+		PaymentUsecase: payment,
+		PrivacyUsecase: NewPrivacyUsecase(repo, log, emailJobs, exportJobs, config),
+		TwoFactorUsecase: NewTwoFactorUsecase(repo, log, config),
+		APIKeyUsecase: NewAPIKeyUsecase(repo, log),
+		OIDCUsecase: NewOIDCUsecase(repo, log, config),
+		MovieRunUsecase: NewMovieRunUsecase(repo, log),
+		PersonUsecase: NewPersonUsecase(repo, log),
+		ReviewUsecase: NewReviewUsecase(repo, log),
+		MediaUsecase: NewMediaUsecase(repo, log, config),
+		CatalogUsecase: NewCatalogUsecase(repo, log),
+		TranslationUsecase: NewTranslationUsecase(repo, log, config),
+		WaitlistUsecase: waitlist,
+		TicketUsecase: NewTicketUsecase(repo, log, emailJobs, payment, config),
+		BoxOfficeUsecase: NewBoxOfficeUsecase(repo, log, config),
 	}
 }
\ No newline at end of file
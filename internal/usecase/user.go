@@ -1,24 +1,43 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/project-app-bioskop-golang/internal/data/entity"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
 	"go.uber.org/zap"
 )
 
 type UserUsecase interface {
 	GetByID(id int) (entity.User, error)
+	GetAll(q dto.PaginationQuery) ([]dto.UserResponse, *dto.Pagination, error)
+	GetProfile(id int) (*dto.UserResponse, error)
+	UpdateProfile(id int, data dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	ChangePassword(id int, token string, data dto.ChangePasswordRequest) error
+	RequestEmailChange(id int, data dto.ChangeEmailRequest) error
+	VerifyEmailChange(id int, data dto.OTPRequest) (*dto.UserResponse, error)
+	DeleteAccount(id int, data dto.DeleteAccountRequest) error
+	Update(id int, data dto.UserRequest) error
+	Delete(id int) error
 }
 
 type userUsecase struct {
 	Repo *repository.Repository
 	Logger *zap.Logger
+	emailJobs chan <- utils.EmailJob
+	Config utils.Configuration
 }
 
-func NewUserUsecase(repo *repository.Repository, log *zap.Logger) UserUsecase {
+func NewUserUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan <- utils.EmailJob, config utils.Configuration) UserUsecase {
 	return &userUsecase{
 		Repo: repo,
 		Logger: log,
+		emailJobs: emailJobs,
+		Config: config,
 	}
 }
 
@@ -29,4 +48,299 @@ func (s *userUsecase) GetByID(id int) (entity.User, error) {
 		return user, err
 	}
 	return user, nil
-}
\ No newline at end of file
+}
+
+func (s *userUsecase) GetAll(q dto.PaginationQuery) ([]dto.UserResponse, *dto.Pagination, error) {
+	// Execute repo to get all users
+	users, total, err := s.Repo.UserRepo.GetAll(q)
+	if err != nil {
+		s.Logger.Error("Error get all users Usecase: ", zap.Error(err))
+		return nil, nil, err
+	}
+
+	// Calculate total pages
+	var totalPages int
+	totalPages = utils.TotalPage(q.Limit, total)
+
+	// Create pagination
+	var pagination dto.Pagination
+
+	if q.All {
+		pagination = dto.Pagination{
+			TotalRecords: total,
+		}
+	} else {
+		pagination = dto.Pagination{
+			CurrentPage:  &q.Page,
+			Limit:        &q.Limit,
+			TotalPages:   &totalPages,
+			TotalRecords: total,
+		}
+	}
+
+	var response []dto.UserResponse
+	for _, u := range users {
+		response = append(response, toUserResponse(u))
+	}
+
+	return response, &pagination, nil
+}
+
+func (s *userUsecase) GetProfile(id int) (*dto.UserResponse, error) {
+	user, err := s.Repo.UserRepo.GetByID(id)
+	if err != nil {
+		s.Logger.Error("Error get profile Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+func (s *userUsecase) UpdateProfile(id int, data dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	user, err := s.Repo.UserRepo.GetByID(id)
+	if err != nil {
+		s.Logger.Error("Error get profile Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	// Only the display name and language can be changed here, email has its own flow
+	user.Name = data.Name
+	if data.Locale != "" {
+		locale := strings.ToLower(data.Locale)
+		if !utils.IsSupportedLocale(locale, s.Config) {
+			return nil, fmt.Errorf("locale must be one of %s", strings.Join(s.Config.Locales, ", "))
+		}
+		user.Locale = locale
+	}
+	err = s.Repo.UserRepo.Update(id, &user)
+	if err != nil {
+		s.Logger.Error("Error update profile Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+func (s *userUsecase) ChangePassword(id int, token string, data dto.ChangePasswordRequest) error {
+	// Check current password
+	password, err := s.Repo.UserRepo.GetPassword(id)
+	if err != nil {
+		s.Logger.Error("Error get user password Usecase: ", zap.Error(err))
+		return err
+	}
+
+	if !utils.CheckPassword(data.CurrentPassword, *password) {
+		s.Logger.Error("Incorrect password: ", zap.Error(errors.New("incorrect password")))
+		return errors.New("incorrect password")
+	}
+
+	if data.CurrentPassword == data.NewPassword {
+		return errors.New("new password must be different from current password")
+	}
+
+	// Hash and store new password
+	passwordHashed := utils.HashPassword(data.NewPassword)
+	err = s.Repo.UserRepo.UpdatePassword(id, passwordHashed)
+	if err != nil {
+		s.Logger.Error("Error update password Usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Sign out every other device
+	err = s.Repo.SessionRepo.RevokeByUserID(id, token)
+	if err != nil {
+		s.Logger.Error("Error revoke sessions Usecase: ", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *userUsecase) RequestEmailChange(id int, data dto.ChangeEmailRequest) error {
+	user, err := s.Repo.UserRepo.GetByID(id)
+	if err != nil {
+		s.Logger.Error("Error get profile Usecase: ", zap.Error(err))
+		return err
+	}
+
+	if user.Email == data.Email {
+		return errors.New("new email must be different from current email")
+	}
+
+	// Check if email is registered
+	existing, _ := s.Repo.UserRepo.FindByEmail(data.Email)
+	if existing != nil {
+		return errors.New("email already registered")
+	}
+
+	// Generate OTP
+	otpStr, err := utils.GenerateOTP(6)
+	if err != nil {
+		s.Logger.Error("Error generate OTP: ", zap.Error(err))
+		return err
+	}
+
+	// OTP is bound to the new address and the requesting account
+	otp := entity.OTP{
+		UserID: &id,
+		Email: data.Email,
+		OTPHash: utils.HashPassword(otpStr),
+	}
+
+	err = s.Repo.OTPRepo.CreateForUser(otp)
+	if err != nil {
+		s.Logger.Error("Error insert OTP: ", zap.Error(err))
+		return err
+	}
+
+	// Format email content
+	locale := userLocale(user, s.Config)
+	body := utils.SendEmailChangeOTP(dto.OTPResponse{
+		Name: user.Name,
+		Email: data.Email,
+		OTP: otpStr,
+	}, locale)
+	content := dto.EmailRequest{
+		To: data.Email,
+		Subject: utils.T(locale, "email_change.subject"),
+		Body: body,
+	}
+
+	// Send OTP
+	s.emailJobs <- utils.EmailJob{
+		EmailContent: content,
+		Config: s.Config,
+		Log: s.Logger,
+	}
+
+	return nil
+}
+
+func (s *userUsecase) VerifyEmailChange(id int, data dto.OTPRequest) (*dto.UserResponse, error) {
+	if data.OTP == nil {
+		return nil, errors.New("otp is required")
+	}
+
+	// Find OTP this user requested for the new email
+	otp, err := s.Repo.OTPRepo.FindForUser(id, data.Email)
+	if err != nil {
+		s.Logger.Error("Error find OTP: ", zap.Error(err))
+		return nil, errors.New("invalid OTP")
+	}
+
+	// Compare OTP
+	if !utils.CheckPassword(*data.OTP, otp.OTPHash) {
+		s.Logger.Error("Invalid OTP: ", zap.Error(errors.New("invalid OTP")))
+		return nil, errors.New("invalid OTP")
+	}
+
+	// Email might have been taken while waiting for verification
+	existing, _ := s.Repo.UserRepo.FindByEmail(data.Email)
+	if existing != nil {
+		return nil, errors.New("email already registered")
+	}
+
+	// Fails if a concurrent request already used the code
+	err = s.Repo.OTPRepo.UseForUser(otp.ID, id)
+	if err != nil {
+		s.Logger.Error("Error use OTP: ", zap.Error(err))
+		return nil, err
+	}
+
+	user, err := s.Repo.UserRepo.GetByID(id)
+	if err != nil {
+		s.Logger.Error("Error get profile Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	user.Email = data.Email
+	err = s.Repo.UserRepo.Update(id, &user)
+	if err != nil {
+		s.Logger.Error("Error update email Usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+func (s *userUsecase) DeleteAccount(id int, data dto.DeleteAccountRequest) error {
+	// Confirm with password before deleting
+	password, err := s.Repo.UserRepo.GetPassword(id)
+	if err != nil {
+		s.Logger.Error("Error get user password Usecase: ", zap.Error(err))
+		return err
+	}
+
+	if !utils.CheckPassword(data.Password, *password) {
+		s.Logger.Error("Incorrect password: ", zap.Error(errors.New("incorrect password")))
+		return errors.New("incorrect password")
+	}
+
+	return s.Delete(id)
+}
+
+func (s *userUsecase) Update(id int, data dto.UserRequest) error {
+	// Make sure email is not used by another account
+	existing, _ := s.Repo.UserRepo.FindByEmail(data.Email)
+	if existing != nil && existing.ID != id {
+		return errors.New("email already registered")
+	}
+
+	user := entity.User{
+		Name: data.Name,
+		Email: data.Email,
+		Role: data.Role,
+	}
+	err := s.Repo.UserRepo.Update(id, &user)
+	if err != nil {
+		s.Logger.Error("Error update user Usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Only cashiers are tied to a cinema
+	var cinemaID *int
+	if data.Role == "cashier" {
+		if _, err := s.Repo.CinemaRepo.GetByID(*data.CinemaID); err != nil {
+			s.Logger.Error("Error get cashier cinema Usecase: ", zap.Error(err))
+			return err
+		}
+		cinemaID = data.CinemaID
+	}
+	err = s.Repo.UserRepo.SetCinema(id, cinemaID)
+	if err != nil {
+		s.Logger.Error("Error set user cinema Usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *userUsecase) Delete(id int) error {
+	err := s.Repo.UserRepo.Delete(id)
+	if err != nil {
+		s.Logger.Error("Error delete user Usecase: ", zap.Error(err))
+		return err
+	}
+
+	// Deleted users must not keep any active session
+	err = s.Repo.SessionRepo.RevokeByUserID(id, "")
+	if err != nil {
+		s.Logger.Error("Error revoke sessions Usecase: ", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func toUserResponse(u entity.User) dto.UserResponse {
+	return dto.UserResponse{
+		UserID: u.ID,
+		Name: u.Name,
+		Email: u.Email,
+		Role: u.Role,
+		Locale: u.Locale,
+		CinemaID: u.CinemaID,
+		CreatedAt: u.CreatedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/data/entity"
+	"github.com/project-app-bioskop-golang/internal/data/repository"
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type WaitlistUsecase interface {
+	Join(screeningID int, userID int, data dto.WaitlistRequest, locale string) (*dto.WaitlistResponse, error)
+	GetByUser(userID int, locale string) ([]dto.WaitlistResponse, error)
+	Leave(id int, userID int) error
+	OfferSeats(screeningID int)
+	Sweep()
+}
+
+type waitlistUsecase struct {
+	Repo      *repository.Repository
+	Logger    *zap.Logger
+	emailJobs chan<- utils.EmailJob
+	Config    utils.Configuration
+}
+
+func NewWaitlistUsecase(repo *repository.Repository, log *zap.Logger, emailJobs chan<- utils.EmailJob, config utils.Configuration) WaitlistUsecase {
+	return &waitlistUsecase{
+		Repo:      repo,
+		Logger:    log,
+		emailJobs: emailJobs,
+		Config:    config,
+	}
+}
+
+func (u *waitlistUsecase) Join(screeningID int, userID int, data dto.WaitlistRequest, locale string) (*dto.WaitlistResponse, error) {
+	id, err := u.Repo.WaitlistRepo.Join(screeningID, userID, data.SeatCount)
+	if err != nil {
+		u.Logger.Error("Error join waitlist usecase: ", zap.Error(err))
+		return nil, err
+	}
+
+	entry, err := u.Repo.WaitlistRepo.GetByID(id)
+	if err != nil {
+		u.Logger.Error("Error get waitlist entry usecase: ", zap.Error(err))
+		return nil, err
+	}
+	entries := []entity.WaitlistEntry{*entry}
+	localizeWaitlist(u.Repo, locale, entries)
+
+	response := toWaitlistResponse(entries[0])
+	return &response, nil
+}
+
+func (u *waitlistUsecase) GetByUser(userID int, locale string) ([]dto.WaitlistResponse, error) {
+	entries, err := u.Repo.WaitlistRepo.GetByUser(userID)
+	if err != nil {
+		u.Logger.Error("Error get user waitlist usecase: ", zap.Error(err))
+		return nil, err
+	}
+	localizeWaitlist(u.Repo, locale, entries)
+
+	var response []dto.WaitlistResponse
+	for _, e := range entries {
+		response = append(response, toWaitlistResponse(e))
+	}
+	return response, nil
+}
+
+func (u *waitlistUsecase) Leave(id int, userID int) error {
+	screeningID, err := u.Repo.WaitlistRepo.Leave(id, userID)
+	if err != nil {
+		u.Logger.Error("Error leave waitlist usecase: ", zap.Error(err))
+		return err
+	}
+
+	// A declined offer goes to the next in line
+	u.OfferSeats(screeningID)
+	return nil
+}
+
+// OfferSeats offers the screening's free seats to the waitlist and emails
+// every user who got an offer
+func (u *waitlistUsecase) OfferSeats(screeningID int) {
+	offers, err := u.Repo.WaitlistRepo.Offer(screeningID)
+	if err != nil {
+		u.Logger.Error("Error offer waitlist seats usecase: ", zap.Error(err))
+		return
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	for _, e := range offers {
+		user, err := u.Repo.UserRepo.GetByID(e.UserID)
+		if err != nil {
+			u.Logger.Error("Error get waitlisted user: ", zap.Error(err))
+			continue
+		}
+
+		// Format email content
+		locale := userLocale(user, u.Config)
+		entries := []entity.WaitlistEntry{e}
+		localizeWaitlist(u.Repo, locale, entries)
+		e = entries[0]
+
+		var expiresAt string
+		if e.OfferExpiresAt != nil {
+			expiresAt = e.OfferExpiresAt.In(loc).Format("15.04")
+		}
+		body := utils.SendWaitlistOffer(dto.WaitlistOfferEmail{
+			Name: user.Name,
+			ScreeningID: e.ScreeningID,
+			MovieTitle: e.MovieTitle,
+			CinemaName: e.CinemaName,
+			Date: e.StartTime.In(loc).Format("02-01-2006"),
+			StartTime: e.StartTime.In(loc).Format("15.04"),
+			SeatCount: e.SeatCount,
+			OfferExpiresAt: expiresAt,
+			Locale: locale,
+		})
+		content := dto.EmailRequest{
+			To: user.Email,
+			Subject: utils.T(locale, "waitlist.subject"),
+			Body: body,
+		}
+
+		// Send offer
+		u.emailJobs <- utils.EmailJob{
+			EmailContent: content,
+			Config: u.Config,
+			Log: u.Logger,
+		}
+	}
+}
+
+// Sweep passes lapsed offers and expired booking holds on to the waitlist
+func (u *waitlistUsecase) Sweep() {
+	screenings, err := u.Repo.WaitlistRepo.GetOpenScreenings()
+	if err != nil {
+		u.Logger.Error("Error get open waitlists usecase: ", zap.Error(err))
+		return
+	}
+	for _, id := range screenings {
+		u.OfferSeats(id)
+	}
+}
+
+func toWaitlistResponse(e entity.WaitlistEntry) dto.WaitlistResponse {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	response := dto.WaitlistResponse{
+		WaitlistID:  e.ID,
+		ScreeningID: e.ScreeningID,
+		MovieTitle:  e.MovieTitle,
+		CinemaName:  e.CinemaName,
+		Date:        e.StartTime.In(loc).Format("02-01-2006"),
+		StartTime:   e.StartTime.In(loc).Format("15.04"),
+		SeatCount:   e.SeatCount,
+		Status:      e.Status,
+		Position:    e.Position,
+		CreatedAt:   e.CreatedAt,
+	}
+	if e.Status == "offered" {
+		response.OfferExpiresAt = e.OfferExpiresAt
+	}
+	return response
+}
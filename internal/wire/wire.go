@@ -2,6 +2,7 @@ package wire
 
 import (
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
@@ -24,14 +25,17 @@ func Wiring(repo *repository.Repository, log *zap.Logger, config utils.Configura
 
 	emailJobs := make(chan utils.EmailJob, 10) // BUFFER
 	ticketJobs := make(chan utils.TicketJob)
+	exportJobs := make(chan utils.ExportJob, 10)
 	stop := make(chan struct{})
 	metrics := &utils.Metrics{}
 	wg := &sync.WaitGroup{}
 
 	utils.StartEmailWorkers(3, emailJobs, stop, metrics, wg)
 	utils.StartTicketWorkers(3, ticketJobs, stop, metrics, wg)
+	utils.StartExportWorkers(2, exportJobs, stop, log, wg)
 
-	usecase := usecase.NewUsecase(repo, log, emailJobs, ticketJobs, config)
+	usecase := usecase.NewUsecase(repo, log, emailJobs, ticketJobs, exportJobs, config)
+	utils.StartSweeper(time.Minute, usecase.WaitlistUsecase.Sweep, stop, wg)
 	handler := adaptor.NewHandler(usecase, log, config)
 	mw := mCustom.NewMiddlewareCustom(usecase, log)
 	r.Mount("/api/v1", ApiV1(&handler, mw))
@@ -52,6 +56,7 @@ func ApiV1(handler *adaptor.Handler, mw mCustom.MiddlewareCustom) *chi.Mux {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			r.Post("/studio-type", handler.StudioHandler.CreateStudioType)			
 		})				
 	})
@@ -59,42 +64,122 @@ func ApiV1(handler *adaptor.Handler, mw mCustom.MiddlewareCustom) *chi.Mux {
 	// Authentication
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/login", handler.AuthHandler.Login)
+		r.Post("/2fa", handler.AuthHandler.VerifyTwoFactor)
+		r.Get("/oidc/login", handler.AuthHandler.OIDCLogin)
+		r.Get("/oidc/callback", handler.AuthHandler.OIDCCallback)
 		r.Post("/register", handler.AuthHandler.Register)
 		r.Post("/logout", handler.AuthHandler.Logout)
 		r.Post("/resend", handler.AuthHandler.ResendOTP)
 		r.Post("/verify", handler.AuthHandler.VerifyOTP)
 	})
 
+	// Profile
+	r.Route("/me", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Get("/", handler.UserHandler.GetProfile)
+		r.Put("/", handler.UserHandler.UpdateProfile)
+		r.Delete("/", handler.UserHandler.DeleteAccount)
+		r.Put("/password", handler.UserHandler.ChangePassword)
+		r.Post("/email", handler.UserHandler.RequestEmailChange)
+		r.Post("/email/verify", handler.UserHandler.VerifyEmailChange)
+
+		// Personal data
+		r.Post("/exports", handler.PrivacyHandler.RequestExport)
+		r.Get("/exports", handler.PrivacyHandler.GetExports)
+		r.Get("/exports/{id}", handler.PrivacyHandler.GetExport)
+		r.Get("/exports/{id}/download", handler.PrivacyHandler.DownloadExport)
+		r.Post("/erasure", handler.PrivacyHandler.EraseAccount)
+
+		// Reviews
+		r.Get("/reviews", handler.ReviewHandler.GetMine)
+
+		// Waitlist
+		r.Get("/waitlist", handler.WaitlistHandler.GetMine)
+		r.Delete("/waitlist/{id}", handler.WaitlistHandler.Leave)
+
+		// Tickets and transfers
+		r.Get("/tickets", handler.TicketHandler.GetMine)
+		r.Post("/tickets/{id}/transfer", handler.TicketHandler.Transfer)
+		r.Get("/transfers", handler.TicketHandler.GetTransfers)
+		r.Post("/transfers/{id}/accept", handler.TicketHandler.AcceptTransfer)
+		r.Delete("/transfers/{id}", handler.TicketHandler.CancelTransfer)
+
+		// Two-factor authentication
+		r.Get("/2fa", handler.TwoFactorHandler.GetStatus)
+		r.Post("/2fa/setup", handler.TwoFactorHandler.Setup)
+		r.Post("/2fa/enable", handler.TwoFactorHandler.Enable)
+		r.Post("/2fa/disable", handler.TwoFactorHandler.Disable)
+		r.Post("/2fa/recovery-codes", handler.TwoFactorHandler.RegenerateRecoveryCodes)
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Route("/", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
+			// Manage users
+			r.Get("/", handler.UserHandler.GetAll)
+			r.Get("/{id}", handler.UserHandler.GetByID)
+			r.Put("/{id}", handler.UserHandler.Update)
+			r.Delete("/{id}", handler.UserHandler.Delete)
+			r.Post("/{id}/erase", handler.PrivacyHandler.Erase)
+		})
+	})
+
+	// API keys
+	r.Route("/api-keys", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Use(mw.RequirePermission("admin"))
+		r.Use(mw.RequireTwoFactor())
+		r.Post("/", handler.APIKeyHandler.Create)
+		r.Get("/", handler.APIKeyHandler.GetAll)
+		r.Get("/{id}", handler.APIKeyHandler.GetByID)
+		r.Post("/{id}/rotate", handler.APIKeyHandler.Rotate)
+		r.Delete("/{id}", handler.APIKeyHandler.Revoke)
+	})
+
 	// Seats
 	r.Route("/seats", func(r chi.Router) {
-		r.Use(mw.AuthMiddleware())
+		r.Use(mw.AuthMiddleware("seats"))
 		r.Get("/", handler.SeatHandler.GetSeatsByScreening)
+		r.Get("/best", handler.SeatHandler.Suggest)
 	})
 
 	r.Route("/cinemas", func(r chi.Router) {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			// CRUD cinemas
 			r.Post("/", handler.CinemaHandler.Create)
 			r.Put("/{id}", handler.CinemaHandler.Update)
 			r.Delete("/{id}", handler.CinemaHandler.Delete)
+			r.Post("/{id}/media", handler.MediaHandler.LinkCinemaMedia)
+			r.Delete("/{id}/media/{mediaId}", handler.MediaHandler.UnlinkCinemaMedia)
+			r.Post("/{id}/closures", handler.CinemaHandler.CreateClosure)
+			r.Delete("/{id}/closures/{closureId}", handler.CinemaHandler.DeleteClosure)
 		})
 
 		r.Get("/", handler.CinemaHandler.GetAll)
+		r.Get("/nearest", handler.CinemaHandler.GetNearest)
+		r.Get("/amenities", handler.CinemaHandler.GetAmenities)
 		r.Get("/{id}", handler.CinemaHandler.GetByID)
+		r.Get("/{id}/media", handler.MediaHandler.GetCinemaMedia)
+		r.Get("/{id}/closures", handler.CinemaHandler.GetClosures)
 	})
 
 	r.Route("/studios", func(r chi.Router) {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			// CRUD studios
 			r.Get("/", handler.StudioHandler.GetAll)
 			r.Get("/{id}", handler.StudioHandler.GetByID)
 			r.Post("/", handler.StudioHandler.Create)
 			r.Put("/{id}", handler.StudioHandler.Update)
 			r.Delete("/{id}", handler.StudioHandler.Delete)
+			r.Put("/{id}/seats/accessible", handler.StudioHandler.SetAccessibleSeats)
 		})
 	})
 
@@ -102,11 +187,14 @@ func ApiV1(handler *adaptor.Handler, mw mCustom.MiddlewareCustom) *chi.Mux {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			// CRUD genres
 			r.Get("/", handler.GenreHandler.GetAll)
 			r.Get("/{id}", handler.GenreHandler.GetByID)
 			r.Post("/", handler.GenreHandler.Create)
+			r.Put("/{id}", handler.GenreHandler.Update)
 			r.Delete("/{id}", handler.GenreHandler.Delete)
+			r.Post("/{id}/merge", handler.GenreHandler.Merge)
 		})
 	})
 
@@ -114,46 +202,187 @@ func ApiV1(handler *adaptor.Handler, mw mCustom.MiddlewareCustom) *chi.Mux {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			// CRUD movies
 			r.Post("/", handler.MovieHandler.Create)
 			r.Put("/{id}", handler.MovieHandler.Update)
 			r.Delete("/{id}", handler.MovieHandler.Delete)
+			r.Put("/{id}/credits", handler.MovieHandler.SetCredits)
+			r.Post("/{id}/media", handler.MediaHandler.LinkMovieMedia)
+			r.Delete("/{id}/media/{mediaId}", handler.MediaHandler.UnlinkMovieMedia)
 		})
 
 		r.Get("/", handler.MovieHandler.GetAll)
+		r.Get("/search", handler.MovieHandler.Search)
+		r.Get("/now-showing", handler.MovieRunHandler.NowShowing)
+		r.Get("/coming-soon", handler.MovieRunHandler.ComingSoon)
 		r.Get("/{id}", handler.MovieHandler.GetByID)
+		r.Get("/{id}/runs", handler.MovieRunHandler.GetByMovie)
+		r.Get("/{id}/media", handler.MediaHandler.GetMovieMedia)
+		r.Get("/{id}/reviews", handler.ReviewHandler.GetByMovie)
+
+		r.Group(func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Post("/{id}/reviews", handler.ReviewHandler.Create)
+		})
+	})
+
+	r.Route("/reviews", func(r chi.Router) {
+		r.Route("/", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Put("/{id}", handler.ReviewHandler.Update)
+			r.Delete("/{id}", handler.ReviewHandler.Delete)
+		})
+
+		// Moderation
+		r.Route("/moderation", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
+			r.Get("/", handler.ReviewHandler.GetAll)
+			r.Put("/{id}", handler.ReviewHandler.Moderate)
+			r.Delete("/{id}", handler.ReviewHandler.Remove)
+		})
+	})
+
+	r.Route("/media", func(r chi.Router) {
+		r.Route("/", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
+			r.Post("/", handler.MediaHandler.Upload)
+			r.Delete("/{id}", handler.MediaHandler.Delete)
+		})
+
+		r.Get("/{id}", handler.MediaHandler.GetByID)
+		r.Get("/{id}/{variant}", handler.MediaHandler.Serve)
+	})
+
+	r.Route("/people", func(r chi.Router) {
+		r.Route("/", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
+			// CRUD people
+			r.Post("/", handler.PersonHandler.Create)
+			r.Put("/{id}", handler.PersonHandler.Update)
+			r.Delete("/{id}", handler.PersonHandler.Delete)
+		})
+
+		r.Get("/", handler.PersonHandler.GetAll)
+		r.Get("/{id}", handler.PersonHandler.GetByID)
+		r.Get("/{id}/movies", handler.PersonHandler.GetMovies)
+	})
+
+	r.Route("/movie-runs", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Use(mw.RequirePermission("admin"))
+		r.Use(mw.RequireTwoFactor())
+		// CRUD movie runs
+		r.Post("/", handler.MovieRunHandler.Create)
+		r.Put("/{id}", handler.MovieRunHandler.Update)
+		r.Delete("/{id}", handler.MovieRunHandler.Delete)
 	})
 
 	r.Route("/screenings", func(r chi.Router) {
 		r.Route("/", func(r chi.Router) {
 			r.Use(mw.AuthMiddleware())
 			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
 			// CRUD screenings
 			r.Post("/", handler.ScreeningHandler.Create)
 			r.Put("/{id}", handler.ScreeningHandler.Update)
 			r.Delete("/{id}", handler.ScreeningHandler.Delete)
+			r.Put("/formats/{code}", handler.ScreeningHandler.UpdateFormat)
 		})
 
 		r.Get("/", handler.ScreeningHandler.GetByCinema)
+		r.Get("/formats", handler.ScreeningHandler.GetFormats)
+		r.Get("/showtimes", handler.ScreeningHandler.SearchShowtimes)
 		r.Get("/{id}", handler.ScreeningHandler.GetByID)
+
+		r.Group(func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Post("/{id}/waitlist", handler.WaitlistHandler.Join)
+		})
+	})
+
+	r.Route("/catalog", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Use(mw.RequirePermission("admin"))
+		r.Use(mw.RequireTwoFactor())
+		// Bulk movies and screenings
+		r.Post("/import/{kind}", handler.CatalogHandler.Import)
+		r.Get("/export/{kind}", handler.CatalogHandler.Export)
+	})
+
+	r.Route("/translations", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Use(mw.RequirePermission("admin"))
+		r.Use(mw.RequireTwoFactor())
+		// Movies, genres, cinemas and studio types in other locales
+		r.Get("/{kind}/{id}", handler.TranslationHandler.GetAll)
+		r.Put("/{kind}/{id}/{locale}", handler.TranslationHandler.Save)
+		r.Delete("/{kind}/{id}/{locale}", handler.TranslationHandler.Delete)
 	})
 
 	r.Route("/bookings", func(r chi.Router) {
 		r.Route("/", func(r chi.Router) {
-			r.Use(mw.AuthMiddleware())
+			r.Use(mw.AuthMiddleware("bookings"))
 			r.Post("/", handler.BookingHandler.Create)
 			r.Get("/", handler.BookingHandler.GetBookingHistory)
 			r.Get("/{id}", handler.BookingHandler.GetByID)
+			r.Post("/{id}/cancel", handler.BookingHandler.Cancel)
+			r.Put("/{id}/seats", handler.BookingHandler.SwapSeats)
+			r.Post("/{id}/exchange", handler.BookingHandler.Exchange)
+			r.Get("/{id}/documents", handler.BookingHandler.GetDocuments)
+			r.Get("/{id}/documents/{kind}", handler.BookingHandler.DownloadDocument)
+		})
+	})
+
+	r.Route("/tickets", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		r.Use(mw.RequirePermission("admin"))
+		r.Use(mw.RequireTwoFactor())
+		// Admit ticket holders at the door
+		r.Post("/check-in", handler.TicketHandler.CheckIn)
+	})
+
+	r.Route("/box-office", func(r chi.Router) {
+		r.Use(mw.AuthMiddleware())
+		// Walk-in sales taken by cashiers during their shift
+		r.Group(func(r chi.Router) {
+			r.Use(mw.RequirePermission("cashier"))
+			r.Post("/shifts", handler.BoxOfficeHandler.OpenShift)
+			r.Get("/shifts/current", handler.BoxOfficeHandler.CurrentShift)
+			r.Post("/shifts/current/close", handler.BoxOfficeHandler.CloseShift)
+			r.Post("/bookings", handler.BoxOfficeHandler.Sell)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(mw.RequirePermission("admin", "cashier"))
+			r.Get("/shifts/{id}", handler.BoxOfficeHandler.GetShift)
+			r.Get("/bookings/{id}/receipt", handler.BoxOfficeHandler.GetReceipt)
+		})
+		// End-of-shift cash reconciliation per cashier
+		r.Group(func(r chi.Router) {
+			r.Use(mw.RequirePermission("admin"))
+			r.Use(mw.RequireTwoFactor())
+			r.Get("/shifts", handler.BoxOfficeHandler.GetReports)
 		})
 	})
 
 	r.Route("/payments", func(r chi.Router) {
 		r.Route("/", func(r chi.Router) {
-			r.Use(mw.AuthMiddleware())
+			r.Use(mw.AuthMiddleware("payments"))
 			r.Post("/", handler.PaymentHandler.Create)
-			r.Post("/callback", handler.PaymentHandler.Callback)
 			r.Get("/method", handler.PaymentHandler.GetPaymentMethod)
 		})
+
+		// Gateway callback is not available to api keys
+		r.Route("/callback", func(r chi.Router) {
+			r.Use(mw.AuthMiddleware())
+			r.Post("/", handler.PaymentHandler.Callback)
+		})
 	})
 	
 	return r
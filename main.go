@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/project-app-bioskop-golang/cmd"
 	"github.com/project-app-bioskop-golang/internal/data/repository"
@@ -11,6 +12,12 @@ import (
 )
 
 func main() {
+	// Bulk catalog tooling shares the app configuration
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		cmd.Catalog(os.Args[2:])
+		return
+	}
+
 	config, err := utils.ReadConfiguration()
 	if err != nil {
 		log.Fatalf("failed to read file config: %v", err)
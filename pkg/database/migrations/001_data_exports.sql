@@ -0,0 +1,17 @@
+-- Personal data export jobs and right-to-erasure support
+
+CREATE TABLE IF NOT EXISTS public.data_exports (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    status character varying(20) DEFAULT 'pending' NOT NULL,
+    file_path text,
+    error text,
+    expired_at timestamp with time zone,
+    completed_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_id_idx ON public.data_exports USING btree (user_id);
+
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS erased_at timestamp with time zone;
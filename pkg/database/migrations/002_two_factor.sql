@@ -0,0 +1,30 @@
+-- TOTP two-factor authentication
+
+CREATE TABLE IF NOT EXISTS public.user_totp (
+    user_id integer PRIMARY KEY REFERENCES public.users(id),
+    secret character varying(64) NOT NULL,
+    enabled_at timestamp with time zone,
+    last_used_step bigint,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS public.user_recovery_codes (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    code_hash character varying(200) NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_codes_user_id_idx ON public.user_recovery_codes USING btree (user_id);
+
+CREATE TABLE IF NOT EXISTS public.two_factor_challenges (
+    id serial PRIMARY KEY,
+    token uuid NOT NULL UNIQUE,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    attempts integer DEFAULT 0 NOT NULL,
+    expired_at timestamp with time zone NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone DEFAULT now()
+);
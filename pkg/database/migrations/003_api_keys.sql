@@ -0,0 +1,20 @@
+-- API keys for partner integrations and kiosks
+
+CREATE TABLE IF NOT EXISTS public.api_keys (
+    id serial PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    prefix character varying(16) NOT NULL UNIQUE,
+    key_hash character varying(64) NOT NULL,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    cinema_ids integer[] DEFAULT '{}'::integer[] NOT NULL,
+    allowed_ips text[] DEFAULT '{}'::text[] NOT NULL,
+    expired_at timestamp with time zone,
+    last_used_at timestamp with time zone,
+    revoked_at timestamp with time zone,
+    created_by integer REFERENCES public.users(id),
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON public.api_keys USING btree (user_id);
@@ -0,0 +1,23 @@
+-- Login with external identity providers (OIDC)
+
+CREATE TABLE IF NOT EXISTS public.user_identities (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES public.users(id),
+    provider character varying(50) NOT NULL,
+    subject character varying(255) NOT NULL,
+    email character varying(200),
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_id_idx ON public.user_identities USING btree (user_id);
+
+-- Pending authorization requests, consumed once by the callback
+CREATE TABLE IF NOT EXISTS public.oidc_states (
+    state character varying(100) PRIMARY KEY,
+    nonce character varying(100) NOT NULL,
+    code_verifier character varying(128) NOT NULL,
+    expired_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone DEFAULT now()
+);
@@ -0,0 +1,15 @@
+-- Full-text and fuzzy movie search
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 'simple' keeps Indonesian and English titles searchable without stemming
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(synopsis, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS movies_search_vector_idx ON public.movies USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS movies_title_trgm_idx ON public.movies USING gin (title gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS screenings_movie_id_start_time_idx ON public.screenings USING btree (movie_id, start_time);
@@ -0,0 +1,17 @@
+-- Movie run lifecycle per cinema: announced, pre_sale, now_showing, ended.
+-- Status is derived from the dates, see repository/movie_run.go.
+
+CREATE TABLE IF NOT EXISTS public.movie_runs (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies(id),
+    cinema_id integer NOT NULL REFERENCES public.cinemas(id),
+    presale_start_at timestamp with time zone,
+    start_date date NOT NULL,
+    end_date date,
+    created_at timestamp with time zone DEFAULT now(),
+    updated_at timestamp with time zone DEFAULT now(),
+    deleted_at timestamp with time zone,
+    CONSTRAINT movie_runs_dates_check CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS movie_runs_movie_cinema_idx ON public.movie_runs USING btree (movie_id, cinema_id) WHERE (deleted_at IS NULL);
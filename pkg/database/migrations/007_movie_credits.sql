@@ -0,0 +1,30 @@
+-- Cast and crew
+
+CREATE TABLE IF NOT EXISTS public.people (
+    id serial PRIMARY KEY,
+    name varchar(150) NOT NULL,
+    biography text,
+    photo_url text,
+    birth_date date,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS people_name_trgm_idx ON public.people USING gin (name gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS public.movie_credits (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies (id),
+    person_id integer NOT NULL REFERENCES public.people (id),
+    role varchar(30) NOT NULL CHECK (role IN ('director', 'writer', 'producer', 'cast', 'composer', 'cinematographer', 'editor')),
+    character_name varchar(150),
+    billing_order integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+-- An actor may play several characters in the same movie
+CREATE UNIQUE INDEX IF NOT EXISTS movie_credits_unique_idx
+    ON public.movie_credits (movie_id, person_id, role, COALESCE(character_name, ''));
+CREATE INDEX IF NOT EXISTS movie_credits_person_id_idx ON public.movie_credits (person_id);
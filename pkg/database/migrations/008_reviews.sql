@@ -0,0 +1,25 @@
+-- Customer reviews with moderation
+
+-- Running totals of approved reviews, average is rating_sum / rating_count
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS rating_sum integer NOT NULL DEFAULT 0;
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS rating_count integer NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS public.reviews (
+    id serial PRIMARY KEY,
+    movie_id integer NOT NULL REFERENCES public.movies (id),
+    user_id integer NOT NULL REFERENCES public.users (id),
+    rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    content text NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    moderation_note text,
+    moderated_by integer REFERENCES public.users (id),
+    moderated_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    deleted_at timestamptz
+);
+
+-- One live review per customer and movie
+CREATE UNIQUE INDEX IF NOT EXISTS reviews_movie_user_idx ON public.reviews (movie_id, user_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reviews_movie_status_idx ON public.reviews (movie_id, status, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS reviews_status_idx ON public.reviews (status, created_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,43 @@
+-- Uploaded images and their resized variants
+
+CREATE TABLE IF NOT EXISTS public.media (
+    id serial PRIMARY KEY,
+    original_name text NOT NULL,
+    mime_type varchar(100) NOT NULL,
+    size_bytes bigint NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    uploaded_by integer REFERENCES public.users (id),
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    deleted_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS public.media_variants (
+    id serial PRIMARY KEY,
+    media_id integer NOT NULL REFERENCES public.media (id) ON DELETE CASCADE,
+    variant varchar(20) NOT NULL,
+    storage_key text NOT NULL UNIQUE,
+    mime_type varchar(100) NOT NULL,
+    size_bytes bigint NOT NULL,
+    width integer NOT NULL,
+    height integer NOT NULL,
+    UNIQUE (media_id, variant)
+);
+
+CREATE TABLE IF NOT EXISTS public.media_links (
+    id serial PRIMARY KEY,
+    media_id integer NOT NULL REFERENCES public.media (id) ON DELETE CASCADE,
+    entity_type varchar(20) NOT NULL CHECK (entity_type IN ('movie', 'cinema')),
+    entity_id integer NOT NULL,
+    kind varchar(20) NOT NULL,
+    position integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (media_id, entity_type, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS media_links_entity_idx ON public.media_links (entity_type, entity_id, kind, position);
+
+-- A movie has one poster and a cinema one logo
+CREATE UNIQUE INDEX IF NOT EXISTS media_links_single_kind_idx
+    ON public.media_links (entity_type, entity_id, kind) WHERE kind IN ('poster', 'logo');
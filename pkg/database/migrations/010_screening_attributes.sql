@@ -0,0 +1,28 @@
+-- Screening attributes: projection format, audio and subtitle language, accessibility
+
+-- Formats with a per-seat surcharge on top of the studio price
+CREATE TABLE IF NOT EXISTS public.screening_formats (
+    code varchar(20) PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    surcharge numeric(12,2) NOT NULL DEFAULT 0 CHECK (surcharge >= 0),
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO public.screening_formats (code, name, surcharge) VALUES
+    ('2D', '2D', 0),
+    ('3D', '3D', 15000),
+    ('IMAX', 'IMAX', 25000),
+    ('IMAX_3D', 'IMAX 3D', 40000),
+    ('4DX', '4DX', 45000),
+    ('SCREENX', 'ScreenX', 20000)
+ON CONFLICT (code) DO NOTHING;
+
+-- Audio language NULL means the movie's original language, subtitle NULL means none
+ALTER TABLE public.screenings ADD COLUMN IF NOT EXISTS format varchar(20) NOT NULL DEFAULT '2D' REFERENCES public.screening_formats (code);
+ALTER TABLE public.screenings ADD COLUMN IF NOT EXISTS audio_language varchar(50);
+ALTER TABLE public.screenings ADD COLUMN IF NOT EXISTS subtitle_language varchar(50);
+ALTER TABLE public.screenings ADD COLUMN IF NOT EXISTS audio_description boolean NOT NULL DEFAULT false;
+ALTER TABLE public.screenings ADD COLUMN IF NOT EXISTS closed_captions boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS screenings_format_idx ON public.screenings (format, start_time) WHERE deleted_at IS NULL;
@@ -0,0 +1,23 @@
+-- Genre hierarchy and localized display names
+
+ALTER TABLE public.genres ADD COLUMN IF NOT EXISTS parent_id integer REFERENCES public.genres (id);
+ALTER TABLE public.genres DROP CONSTRAINT IF EXISTS genres_parent_check;
+ALTER TABLE public.genres ADD CONSTRAINT genres_parent_check CHECK (parent_id IS NULL OR parent_id <> id);
+CREATE INDEX IF NOT EXISTS genres_parent_idx ON public.genres (parent_id) WHERE deleted_at IS NULL;
+
+-- Display name per locale, genres.name stays the canonical name
+CREATE TABLE IF NOT EXISTS public.genre_translations (
+    genre_id integer NOT NULL REFERENCES public.genres (id),
+    locale varchar(10) NOT NULL,
+    name varchar(200) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (genre_id, locale)
+);
+
+-- Links left behind by genres deleted before delete cleaned them up
+DELETE FROM public.genre_movies gm
+USING public.genres g
+WHERE g.id = gm.genre_id AND g.deleted_at IS NOT NULL;
+
+DELETE FROM public.genre_movies WHERE genre_id IS NULL OR movie_id IS NULL;
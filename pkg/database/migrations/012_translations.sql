@@ -0,0 +1,35 @@
+-- Catalog translations. The columns on the base tables hold the default
+-- locale (DEFAULT_LOCALE), these tables hold every other locale.
+-- genre_translations is created in 011_genre_hierarchy.sql.
+
+CREATE TABLE IF NOT EXISTS public.movie_translations (
+    movie_id integer NOT NULL REFERENCES public.movies (id),
+    locale varchar(10) NOT NULL,
+    title varchar(200) NOT NULL,
+    synopsis text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS public.cinema_translations (
+    cinema_id integer NOT NULL REFERENCES public.cinemas (id),
+    locale varchar(10) NOT NULL,
+    name varchar(200) NOT NULL,
+    location text,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cinema_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS public.studio_type_translations (
+    studio_type_id integer NOT NULL REFERENCES public.studio_types (id),
+    locale varchar(10) NOT NULL,
+    name varchar(200) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (studio_type_id, locale)
+);
+
+-- Preferred language for emails, NULL means the default locale
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS locale varchar(10);
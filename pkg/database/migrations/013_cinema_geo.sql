@@ -0,0 +1,17 @@
+-- Structured cinema address and coordinates for "cinemas near me".
+-- Distances are computed with the haversine formula so PostGIS is not needed.
+
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS address text;
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS city varchar(100);
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS region varchar(100);
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS longitude double precision;
+
+ALTER TABLE public.cinemas DROP CONSTRAINT IF EXISTS cinemas_coordinates_check;
+ALTER TABLE public.cinemas ADD CONSTRAINT cinemas_coordinates_check CHECK (
+    (latitude IS NULL AND longitude IS NULL)
+    OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+
+CREATE INDEX IF NOT EXISTS cinemas_city_idx ON public.cinemas (lower(city)) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS cinemas_coordinates_idx ON public.cinemas (latitude, longitude) WHERE deleted_at IS NULL;
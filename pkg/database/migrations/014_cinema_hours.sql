@@ -0,0 +1,46 @@
+-- Cinema opening hours, closure dates and amenities
+
+-- Weekday follows Go's time.Weekday (0 = Sunday). A close_time at or before
+-- open_time closes after midnight. Cinemas without rows have no restriction.
+CREATE TABLE IF NOT EXISTS public.cinema_opening_hours (
+    cinema_id integer NOT NULL REFERENCES public.cinemas (id),
+    weekday smallint NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    open_time time NOT NULL,
+    close_time time NOT NULL CHECK (close_time <> open_time),
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (cinema_id, weekday)
+);
+
+-- Whole days the cinema is closed, in local time
+CREATE TABLE IF NOT EXISTS public.cinema_closures (
+    id serial PRIMARY KEY,
+    cinema_id integer NOT NULL REFERENCES public.cinemas (id),
+    date date NOT NULL,
+    reason text,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    UNIQUE (cinema_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS public.amenities (
+    code varchar(50) PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO public.amenities (code, name) VALUES
+    ('parking', 'Parking'),
+    ('food_beverage', 'Food & Beverage'),
+    ('wheelchair_access', 'Wheelchair Access'),
+    ('prayer_room', 'Prayer Room')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS public.cinema_amenities (
+    cinema_id integer NOT NULL REFERENCES public.cinemas (id),
+    amenity_code varchar(50) NOT NULL REFERENCES public.amenities (code),
+    PRIMARY KEY (cinema_id, amenity_code)
+);
+
+CREATE INDEX IF NOT EXISTS cinema_amenities_code_idx ON public.cinema_amenities (amenity_code);
@@ -0,0 +1,35 @@
+-- Waitlist for sold-out screenings
+
+-- waiting: queued in created_at order
+-- offered: holds seat_count seats for the user until offer_expires_at
+-- booked, expired, cancelled: no longer in the queue
+CREATE TYPE public.waitlist_status AS ENUM (
+    'waiting',
+    'offered',
+    'booked',
+    'expired',
+    'cancelled'
+);
+
+CREATE TABLE IF NOT EXISTS public.waitlist_entries (
+    id serial PRIMARY KEY,
+    screening_id integer NOT NULL REFERENCES public.screenings (id),
+    user_id integer NOT NULL REFERENCES public.users (id),
+    seat_count integer NOT NULL CHECK (seat_count > 0),
+    status public.waitlist_status NOT NULL DEFAULT 'waiting',
+    offer_expires_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    CHECK (status <> 'offered' OR offer_expires_at IS NOT NULL)
+);
+
+-- One open entry per user and screening
+CREATE UNIQUE INDEX IF NOT EXISTS unique_open_waitlist_entry
+    ON public.waitlist_entries (screening_id, user_id)
+    WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_queue
+    ON public.waitlist_entries (screening_id, status, created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_user
+    ON public.waitlist_entries (user_id, created_at DESC);
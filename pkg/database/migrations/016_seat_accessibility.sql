@@ -0,0 +1,3 @@
+-- Seats for wheelchair users and their companions, used by seat suggestions
+ALTER TABLE public.seats
+    ADD COLUMN IF NOT EXISTS accessible boolean NOT NULL DEFAULT false;
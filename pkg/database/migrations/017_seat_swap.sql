@@ -0,0 +1,12 @@
+-- Seat changes on existing bookings
+
+-- Tickets of seats given up in a swap stay for the record but no longer admit
+ALTER TABLE public.tickets
+    ADD COLUMN IF NOT EXISTS revoked_at timestamptz;
+
+-- payment: the booking itself, charge: extra seats added later, refund: seats given up
+ALTER TABLE public.payments
+    ADD COLUMN IF NOT EXISTS type varchar(20) NOT NULL DEFAULT 'payment'
+    CHECK (type IN ('payment', 'charge', 'refund'));
+
+CREATE INDEX IF NOT EXISTS idx_tickets_booking ON public.tickets (booking_id) WHERE revoked_at IS NULL;
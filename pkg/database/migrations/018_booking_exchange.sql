@@ -0,0 +1,10 @@
+-- Exchanging a paid booking to another screening
+
+-- The new booking points at the one it replaced. The old booking is cancelled
+-- once the exchange completes and keeps exchanged_at for the record.
+ALTER TABLE public.bookings
+    ADD COLUMN IF NOT EXISTS exchanged_from_id integer REFERENCES public.bookings (id),
+    ADD COLUMN IF NOT EXISTS exchanged_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_exchanged_from ON public.bookings (exchanged_from_id)
+    WHERE exchanged_from_id IS NOT NULL;
@@ -0,0 +1,39 @@
+-- Ticket transfers between registered users
+
+-- holder_user_id: who the ticket belongs to when it is not the booking owner
+-- checked_in_at: set at the door, a checked-in ticket cannot change hands
+ALTER TABLE public.tickets
+    ADD COLUMN IF NOT EXISTS holder_user_id integer REFERENCES public.users (id),
+    ADD COLUMN IF NOT EXISTS checked_in_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_tickets_holder ON public.tickets (holder_user_id) WHERE revoked_at IS NULL;
+
+-- pending: waiting for the recipient
+-- accepted: the old ticket is revoked and new_ticket_id was issued to the recipient
+-- declined, cancelled: turned down by the recipient or taken back by the sender
+CREATE TYPE public.ticket_transfer_status AS ENUM (
+    'pending',
+    'accepted',
+    'declined',
+    'cancelled'
+);
+
+CREATE TABLE IF NOT EXISTS public.ticket_transfers (
+    id serial PRIMARY KEY,
+    ticket_id integer NOT NULL REFERENCES public.tickets (id),
+    from_user_id integer NOT NULL REFERENCES public.users (id),
+    to_user_id integer NOT NULL REFERENCES public.users (id),
+    status public.ticket_transfer_status NOT NULL DEFAULT 'pending',
+    new_ticket_id integer REFERENCES public.tickets (id),
+    created_at timestamptz NOT NULL DEFAULT NOW(),
+    updated_at timestamptz NOT NULL DEFAULT NOW(),
+    CHECK (from_user_id <> to_user_id)
+);
+
+-- One open transfer per ticket
+CREATE UNIQUE INDEX IF NOT EXISTS unique_pending_ticket_transfer
+    ON public.ticket_transfers (ticket_id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_from ON public.ticket_transfers (from_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_to ON public.ticket_transfers (to_user_id, created_at DESC);
@@ -0,0 +1,56 @@
+-- Box office sales by cashier staff
+
+ALTER TYPE public.user_role ADD VALUE IF NOT EXISTS 'cashier';
+
+-- The cinema a cashier sells for
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS cinema_id integer REFERENCES public.cinemas (id);
+
+-- Walk-in bookings have no customer account, only an optional phone number
+ALTER TABLE public.bookings
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS cashier_id integer REFERENCES public.users (id),
+    ADD COLUMN IF NOT EXISTS customer_phone varchar(20),
+    ADD COLUMN IF NOT EXISTS channel varchar(20) NOT NULL DEFAULT 'online'
+    CHECK (channel IN ('online', 'box_office'));
+
+ALTER TABLE public.bookings DROP CONSTRAINT IF EXISTS bookings_customer_check;
+ALTER TABLE public.bookings
+    ADD CONSTRAINT bookings_customer_check CHECK (user_id IS NOT NULL OR channel = 'box_office');
+
+-- online: paid through the gateway, cash and edc: taken at the box office
+ALTER TABLE public.payment_methods
+    ADD COLUMN IF NOT EXISTS kind varchar(20) NOT NULL DEFAULT 'online'
+    CHECK (kind IN ('online', 'cash', 'edc'));
+
+INSERT INTO public.payment_methods (name, kind)
+SELECT 'Cash', 'cash' WHERE NOT EXISTS (SELECT 1 FROM public.payment_methods WHERE kind = 'cash');
+INSERT INTO public.payment_methods (name, kind)
+SELECT 'EDC', 'edc' WHERE NOT EXISTS (SELECT 1 FROM public.payment_methods WHERE kind = 'edc');
+
+-- A cashier's shift from opening to closing the drawer
+CREATE TABLE IF NOT EXISTS public.cashier_shifts (
+    id serial PRIMARY KEY,
+    cashier_id integer NOT NULL REFERENCES public.users (id),
+    cinema_id integer REFERENCES public.cinemas (id),
+    opening_float numeric(12,2) NOT NULL DEFAULT 0 CHECK (opening_float >= 0),
+    counted_cash numeric(12,2),
+    note text,
+    opened_at timestamptz NOT NULL DEFAULT NOW(),
+    closed_at timestamptz,
+    CHECK (closed_at IS NULL OR counted_cash IS NOT NULL)
+);
+
+-- One open shift per cashier
+CREATE UNIQUE INDEX IF NOT EXISTS unique_open_cashier_shift
+    ON public.cashier_shifts (cashier_id)
+    WHERE closed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_cashier_shifts_opened ON public.cashier_shifts (opened_at DESC);
+
+-- tendered: cash handed over, the change is tendered - amount
+ALTER TABLE public.payments
+    ADD COLUMN IF NOT EXISTS shift_id integer REFERENCES public.cashier_shifts (id),
+    ADD COLUMN IF NOT EXISTS tendered numeric(12,2);
+
+CREATE INDEX IF NOT EXISTS idx_payments_shift ON public.payments (shift_id) WHERE shift_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Email change codes belong to the account that asked for them
+
+ALTER TABLE public.otp_codes
+    ADD COLUMN IF NOT EXISTS user_id integer REFERENCES public.users(id);
+
+CREATE INDEX IF NOT EXISTS otp_codes_user_id_idx ON public.otp_codes USING btree (user_id) WHERE user_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Failed second factor attempts are counted per user across challenges
+
+CREATE INDEX IF NOT EXISTS two_factor_challenges_user_id_idx ON public.two_factor_challenges USING btree (user_id, created_at);
@@ -0,0 +1,179 @@
+// Package pdf writes simple PDF documents: monospaced text, lines and
+// bitmaps such as QR codes. It uses the Courier fonts every PDF reader
+// has built in, so no font files are embedded and every character has the
+// same width, which keeps receipts and tickets easy to lay out.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// MM is one millimetre in PDF points
+const MM = 72 / 25.4
+
+// Page sizes in points
+const (
+	A4Width      = 210 * MM
+	A4Height     = 297 * MM
+	ReceiptWidth = 80 * MM
+)
+
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+// Width of a Courier glyph in thousandths of the font size
+const glyphWidth = 600
+
+type page struct {
+	width   float64
+	height  float64
+	content bytes.Buffer
+}
+
+// Document holds the pages being drawn. Coordinates are in points from the
+// top left corner of the page, text is placed by its baseline.
+type Document struct {
+	pages []*page
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage starts a new page, later drawing goes to it
+func (d *Document) AddPage(width, height float64) {
+	d.pages = append(d.pages, &page{width: width, height: height})
+}
+
+func (d *Document) current() *page {
+	if len(d.pages) == 0 {
+		d.AddPage(A4Width, A4Height)
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// TextWidth returns how wide s is at the given font size
+func TextWidth(size float64, s string) float64 {
+	return float64(utf8.RuneCountInString(s)) * size * glyphWidth / 1000
+}
+
+// Text draws s with its baseline starting at x, y
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	p := d.current()
+	name := "F1"
+	if font == Bold {
+		name = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", name, size, x, p.height-y, escape(s))
+}
+
+// TextRight draws s so that it ends at x
+func (d *Document) TextRight(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(size, s), y, font, size, s)
+}
+
+// TextCenter draws s centred on x
+func (d *Document) TextCenter(x, y float64, font Font, size float64, s string) {
+	d.Text(x-TextWidth(size, s)/2, y, font, size, s)
+}
+
+// Line draws a straight line of the given width
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	p := d.current()
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, p.height-y1, x2, p.height-y2)
+}
+
+// Bitmap fills a cell square for every set bit with its top left corner at
+// x, y. Bits are given row by row from the top.
+func (d *Document) Bitmap(x, y, cell float64, bits [][]bool) {
+	p := d.current()
+	for r, row := range bits {
+		// Runs of set bits in a row are drawn as one rectangle
+		for c := 0; c < len(row); {
+			if !row[c] {
+				c++
+				continue
+			}
+			start := c
+			for c < len(row) && row[c] {
+				c++
+			}
+			fmt.Fprintf(&p.content, "%.2f %.2f %.2f %.2f re\n",
+				x+float64(start)*cell, p.height-y-float64(r+1)*cell, float64(c-start)*cell, cell)
+		}
+	}
+	p.content.WriteString("f\n")
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	d.current()
+
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Catalog, page tree and fonts come first, each page is followed by its content
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			p.width, p.height, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// Bytes returns the document as a PDF file
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// escape encodes s for a PDF string. Latin-1 characters map to the same
+// WinAnsi codes, anything else the built-in fonts cannot show becomes '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// xrefOffsets reads the byte offsets of the objects from the xref table
+// that startxref points at
+func xrefOffsets(t *testing.T, out []byte) []int {
+	t.Helper()
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("missing startxref trailer")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	lines := strings.Split(string(out[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil {
+		t.Fatalf("invalid xref subsection %q: %v", lines[1], err)
+	}
+	if first != 0 {
+		t.Fatalf("xref starts at object %d, want 0", first)
+	}
+	if lines[2] != "0000000000 65535 f " {
+		t.Errorf("xref free entry = %q", lines[2])
+	}
+
+	var offsets []int
+	for _, line := range lines[3 : 2+count] {
+		if len(line) != 19 || !strings.HasSuffix(line, " 00000 n ") {
+			t.Fatalf("invalid xref entry %q", line)
+		}
+		offset, _ := strconv.Atoi(line[:10])
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+func TestWriteTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages int
+	}{
+		{name: "empty document gets one page", pages: 0},
+		{name: "one page", pages: 1},
+		{name: "three pages", pages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			for i := 0; i < tt.pages; i++ {
+				d.AddPage(A4Width, A4Height)
+				d.Text(10, 20, Regular, 12, fmt.Sprintf("Page (%d) caf\u00e9", i+1))
+				d.Line(10, 30, 100, 30, 1)
+				d.Bitmap(10, 40, 2, [][]bool{{true, true, false, true}, {false, true, true, false}})
+			}
+
+			var buf bytes.Buffer
+			n, err := d.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("WriteTo() unexpected error: %v", err)
+			}
+			out := buf.Bytes()
+			if n != int64(len(out)) {
+				t.Errorf("WriteTo() = %d bytes, wrote %d", n, len(out))
+			}
+			if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+				t.Errorf("missing PDF header")
+			}
+
+			pages := tt.pages
+			if pages == 0 {
+				pages = 1
+			}
+
+			// Catalog, page tree, two fonts, then a page and its content per page
+			objects := 4 + 2*pages
+			offsets := xrefOffsets(t, out)
+			if len(offsets) != objects {
+				t.Fatalf("xref has %d objects, want %d", len(offsets), objects)
+			}
+			for i, offset := range offsets {
+				want := fmt.Sprintf("%d 0 obj\n", i+1)
+				if !bytes.HasPrefix(out[offset:], []byte(want)) {
+					t.Errorf("xref offset %d of object %d points at %q", offset, i+1, out[offset:min(offset+12, len(out))])
+				}
+			}
+
+			if got := bytes.Count(out, []byte(" 0 obj\n")); got != objects {
+				t.Errorf("document has %d objects, want %d", got, objects)
+			}
+			if !bytes.Contains(out, []byte(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>", objects+1))) {
+				t.Errorf("trailer size does not match %d objects", objects)
+			}
+			if !bytes.Contains(out, []byte(fmt.Sprintf("/Count %d >>", pages))) {
+				t.Errorf("page tree count does not match %d pages", pages)
+			}
+			if got := bytes.Count(out, []byte("/Type /Page /Parent 2 0 R")); got != pages {
+				t.Errorf("document has %d page objects, want %d", got, pages)
+			}
+		})
+	}
+}
+
+func TestWriteToStreamLength(t *testing.T) {
+	d := New()
+	d.AddPage(ReceiptWidth, 100*MM)
+	d.Text(5, 10, Bold, 9, "Total")
+	d.TextRight(75, 10, Regular, 9, "Rp 50.000")
+
+	out := d.Bytes()
+	m := regexp.MustCompile(`(?s)/Length (\d+) >>\nstream\n(.*?)endstream`).FindSubmatch(out)
+	if m == nil {
+		t.Fatalf("missing content stream")
+	}
+	length, _ := strconv.Atoi(string(m[1]))
+	if length != len(m[2]) {
+		t.Errorf("stream /Length = %d, content is %d bytes", length, len(m[2]))
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain ascii", in: "Seat A1 - Studio 2", want: "Seat A1 - Studio 2"},
+		{name: "parentheses", in: "Movie (2D)", want: `Movie \(2D\)`},
+		{name: "backslash", in: `C:\tickets`, want: `C:\\tickets`},
+		{name: "unbalanced parenthesis", in: "a)b(", want: `a\)b\(`},
+		{name: "latin-1 letters", in: "Caf\u00e9 \u00fcber", want: `Caf\351 \374ber`},
+		{name: "latin-1 symbols", in: "\u00a0\u00a9\u00ff", want: `\240\251\377`},
+		{name: "outside latin-1", in: "\u20ac5", want: "?5"},
+		{name: "non-latin script", in: "\u65e5\u672c", want: "??"},
+		{name: "emoji", in: "\U0001F3AC", want: "?"},
+		{name: "control characters", in: "a\tb\nc", want: "a?b?c"},
+		{name: "delete and c1 range", in: "\u007f\u0085", want: "??"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escape(tt.in); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// Multi byte characters are one glyph each
+	if got, want := TextWidth(10, "caf\u00e9"), 24.0; got != want {
+		t.Errorf("TextWidth() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+// Package seatpicker suggests the best block of adjacent free seats for a
+// party. It only looks at seat positions, so it works for any seat map where
+// rows are labelled with letters from the screen backwards and seats are
+// numbered along the row.
+package seatpicker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Weights of the score parts. A single-seat gap costs as much as the best
+// position is worth, so a block that leaves one is only picked if nothing
+// else fits.
+const (
+	rowWeight    = 0.5
+	columnWeight = 0.5
+	gapPenalty   = 1.0
+)
+
+var ErrNoBlock = errors.New("no block of adjacent free seats fits the party")
+
+type Seat struct {
+	ID         int
+	Code       string
+	Row        string
+	Column     int
+	Available  bool
+	Accessible bool
+}
+
+type Preferences struct {
+	// Aisle asks for a block with a seat at the end of the row or next to a walkway
+	Aisle bool
+	// Accessible asks for a block with at least one accessible seat
+	Accessible bool
+}
+
+type Suggestion struct {
+	Seats []Seat
+	Score float64
+}
+
+// ParseCode splits a seat code such as "C12" into its row and seat number
+func ParseCode(code string) (string, int, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	i := strings.IndexFunc(code, func(r rune) bool { return r >= '0' && r <= '9' })
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid seat code %q", code)
+	}
+	column, err := strconv.Atoi(code[i:])
+	if err != nil || column <= 0 {
+		return "", 0, fmt.Errorf("invalid seat code %q", code)
+	}
+	return code[:i], column, nil
+}
+
+// Best returns the highest scoring block of size adjacent free seats in one
+// row. Blocks close to the centre of the room score higher, blocks that
+// leave a single free seat stranded next to them score lower.
+func Best(seats []Seat, size int, pref Preferences) (*Suggestion, error) {
+	if size <= 0 {
+		return nil, errors.New("party size must be at least 1")
+	}
+
+	rows := groupRows(seats)
+	var best *Suggestion
+	for r, row := range rows {
+		for i := 0; i+size <= len(row); i++ {
+			block := row[i : i+size]
+			if !adjacentFree(block) || !matches(row, i, size, pref) {
+				continue
+			}
+
+			score := rowWeight*rowScore(r, len(rows)) + columnWeight*columnScore(row, block) - gapPenalty*float64(strandedSeats(row, i, size))
+			if best == nil || score > best.Score {
+				best = &Suggestion{Seats: append([]Seat(nil), block...), Score: score}
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoBlock
+	}
+	best.Score = math.Round(best.Score*1000) / 1000
+	return best, nil
+}
+
+// groupRows orders rows front to back and seats left to right. Labels are
+// compared by length first so that row AA comes after row Z.
+func groupRows(seats []Seat) [][]Seat {
+	byRow := map[string][]Seat{}
+	var labels []string
+	for _, s := range seats {
+		if _, ok := byRow[s.Row]; !ok {
+			labels = append(labels, s.Row)
+		}
+		byRow[s.Row] = append(byRow[s.Row], s)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		if len(labels[i]) != len(labels[j]) {
+			return len(labels[i]) < len(labels[j])
+		}
+		return labels[i] < labels[j]
+	})
+
+	var rows [][]Seat
+	for _, label := range labels {
+		row := byRow[label]
+		sort.Slice(row, func(i, j int) bool {
+			return row[i].Column < row[j].Column
+		})
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func adjacentFree(block []Seat) bool {
+	for i, s := range block {
+		if !s.Available {
+			return false
+		}
+		if i > 0 && s.Column != block[i-1].Column+1 {
+			return false
+		}
+	}
+	return true
+}
+
+func matches(row []Seat, start int, size int, pref Preferences) bool {
+	if pref.Accessible {
+		found := false
+		for _, s := range row[start : start+size] {
+			if s.Accessible {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if pref.Aisle {
+		end := start + size - 1
+		return isAisle(row, start) || isAisle(row, end)
+	}
+	return true
+}
+
+// isAisle reports whether a seat has no seat next to it on one side
+func isAisle(row []Seat, i int) bool {
+	if i == 0 || i == len(row)-1 {
+		return true
+	}
+	return row[i-1].Column != row[i].Column-1 || row[i+1].Column != row[i].Column+1
+}
+
+// rowScore is 1 for the middle row and falls to 0 for the front and back rows
+func rowScore(r int, rows int) float64 {
+	if rows <= 1 {
+		return 1
+	}
+	centre := float64(rows-1) / 2
+	return 1 - math.Abs(float64(r)-centre)/centre
+}
+
+// columnScore is 1 for a block centred in its row and falls to 0 at the ends
+func columnScore(row []Seat, block []Seat) float64 {
+	first := float64(row[0].Column)
+	last := float64(row[len(row)-1].Column)
+	if last == first {
+		return 1
+	}
+	centre := (first + last) / 2
+	blockCentre := float64(block[0].Column+block[len(block)-1].Column) / 2
+	return math.Max(0, 1-math.Abs(blockCentre-centre)/((last-first)/2))
+}
+
+// strandedSeats counts free seats on either side of the block that would be
+// left with no free neighbour, seats nobody buys on their own
+func strandedSeats(row []Seat, start int, size int) int {
+	stranded := 0
+
+	// Left side
+	if l := start - 1; l >= 0 && row[l].Available && row[l].Column == row[start].Column-1 {
+		if l == 0 || !row[l-1].Available || row[l-1].Column != row[l].Column-1 {
+			stranded++
+		}
+	}
+
+	// Right side
+	end := start + size - 1
+	if r := end + 1; r < len(row) && row[r].Available && row[r].Column == row[end].Column+1 {
+		if r == len(row)-1 || !row[r+1].Available || row[r+1].Column != row[r].Column+1 {
+			stranded++
+		}
+	}
+	return stranded
+}
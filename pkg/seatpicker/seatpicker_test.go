@@ -0,0 +1,336 @@
+package seatpicker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// makeRow builds a row with the given seat numbers, all free except taken
+func makeRow(label string, columns []int, taken ...int) []Seat {
+	isTaken := map[int]bool{}
+	for _, c := range taken {
+		isTaken[c] = true
+	}
+
+	var row []Seat
+	for _, c := range columns {
+		row = append(row, Seat{
+			ID:        c,
+			Code:      fmt.Sprintf("%s%d", label, c),
+			Row:       label,
+			Column:    c,
+			Available: !isTaken[c],
+		})
+	}
+	return row
+}
+
+// span returns the seat numbers from first to last
+func span(first, last int) []int {
+	var columns []int
+	for c := first; c <= last; c++ {
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// makeRoom builds rows of seats numbered 1 to size, all free
+func makeRoom(labels []string, size int) []Seat {
+	var seats []Seat
+	for _, label := range labels {
+		seats = append(seats, makeRow(label, span(1, size))...)
+	}
+	return seats
+}
+
+func codes(seats []Seat) []string {
+	var out []string
+	for _, s := range seats {
+		out = append(out, s.Code)
+	}
+	return out
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		row     string
+		column  int
+		wantErr bool
+	}{
+		{code: "C12", row: "C", column: 12},
+		{code: "a1", row: "A", column: 1},
+		{code: " aa3 ", row: "AA", column: 3},
+		{code: "12", wantErr: true},
+		{code: "C", wantErr: true},
+		{code: "C0", wantErr: true},
+		{code: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			row, column, err := ParseCode(tt.code)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCode(%q) expected error, got %q %d", tt.code, row, column)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCode(%q) unexpected error: %v", tt.code, err)
+			}
+			if row != tt.row || column != tt.column {
+				t.Errorf("ParseCode(%q) = %q %d, want %q %d", tt.code, row, column, tt.row, tt.column)
+			}
+		})
+	}
+}
+
+func TestBest(t *testing.T) {
+	accessibleRoom := makeRoom([]string{"A", "B", "C", "D", "E"}, 10)
+	accessibleRoom[0].Accessible = true
+	accessibleRoom[1].Accessible = true
+
+	tests := []struct {
+		name  string
+		seats []Seat
+		size  int
+		pref  Preferences
+		want  []string
+		score float64
+	}{
+		{
+			name:  "centre of the middle row",
+			seats: makeRoom([]string{"A", "B", "C", "D", "E"}, 10),
+			size:  2,
+			want:  []string{"C5", "C6"},
+			score: 1,
+		},
+		{
+			// Off centre in the middle row still beats the centre of row B
+			name:  "taken centre stays in the middle row",
+			seats: append(append(makeRoom([]string{"A", "B"}, 10), makeRow("C", span(1, 10), 5, 6)...), makeRoom([]string{"D", "E"}, 10)...),
+			size:  2,
+			want:  []string{"C3", "C4"},
+			score: 0.778,
+		},
+		{
+			// C3 C4 is closer to the centre but strands C2
+			name:  "block leaving a single seat gap loses",
+			seats: makeRow("C", span(1, 7), 1, 7),
+			size:  2,
+			want:  []string{"C2", "C3"},
+			score: 0.75,
+		},
+		{
+			name:  "aisle seat at the end of the row",
+			seats: makeRoom([]string{"A", "B", "C"}, 10),
+			size:  2,
+			pref:  Preferences{Aisle: true},
+			want:  []string{"B1", "B2"},
+			score: 0.556,
+		},
+		{
+			name:  "aisle seat next to a walkway",
+			seats: makeRow("A", []int{1, 2, 3, 4, 6, 7, 8, 9}),
+			size:  2,
+			pref:  Preferences{Aisle: true},
+			want:  []string{"A3", "A4"},
+			score: 0.813,
+		},
+		{
+			name:  "block never spans a walkway",
+			seats: makeRow("A", []int{1, 2, 3, 4, 6, 7, 8, 9}),
+			size:  4,
+			want:  []string{"A1", "A2", "A3", "A4"},
+			score: 0.688,
+		},
+		{
+			name:  "accessible seat without stranding a neighbour",
+			seats: accessibleRoom,
+			size:  2,
+			pref:  Preferences{Accessible: true},
+			want:  []string{"A1", "A2"},
+			score: 0.056,
+		},
+		{
+			// Sorted as text AA would sit between A and B and be the middle row
+			name:  "row AA comes after Z",
+			seats: makeRoom([]string{"AA", "A", "B"}, 5),
+			size:  1,
+			want:  []string{"B3"},
+			score: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Best(tt.seats, tt.size, tt.pref)
+			if err != nil {
+				t.Fatalf("Best() unexpected error: %v", err)
+			}
+			if fmt.Sprint(codes(got.Seats)) != fmt.Sprint(tt.want) {
+				t.Errorf("Best() seats = %v, want %v", codes(got.Seats), tt.want)
+			}
+			if got.Score != tt.score {
+				t.Errorf("Best() score = %v, want %v", got.Score, tt.score)
+			}
+		})
+	}
+}
+
+func TestBestNoBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		seats []Seat
+		size  int
+		pref  Preferences
+	}{
+		{name: "sold out", seats: makeRow("A", span(1, 4), 1, 2, 3, 4), size: 1},
+		{name: "party larger than any free run", seats: makeRow("A", span(1, 6), 3), size: 4},
+		{name: "party split by a walkway", seats: makeRow("A", []int{1, 2, 4, 5}), size: 3},
+		{name: "no accessible seats", seats: makeRoom([]string{"A", "B"}, 6), size: 2, pref: Preferences{Accessible: true}},
+		{name: "no seats", seats: nil, size: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Best(tt.seats, tt.size, tt.pref)
+			if !errors.Is(err, ErrNoBlock) {
+				t.Errorf("Best() = %v, %v, want ErrNoBlock", got, err)
+			}
+		})
+	}
+}
+
+func TestBestInvalidSize(t *testing.T) {
+	_, err := Best(makeRoom([]string{"A"}, 4), 0, Preferences{})
+	if err == nil || errors.Is(err, ErrNoBlock) {
+		t.Errorf("Best() with size 0 error = %v, want party size error", err)
+	}
+}
+
+func TestGroupRows(t *testing.T) {
+	seats := append(makeRow("AA", []int{2, 1}), makeRow("Z", []int{1})...)
+	seats = append(seats, makeRow("B", []int{3, 1, 2})...)
+	seats = append(seats, makeRow("A", []int{1})...)
+
+	var got []string
+	for _, row := range groupRows(seats) {
+		got = append(got, codes(row)...)
+	}
+
+	want := []string{"A1", "B1", "B2", "B3", "Z1", "AA1", "AA2"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("groupRows() = %v, want %v", got, want)
+	}
+}
+
+func TestStrandedSeats(t *testing.T) {
+	tests := []struct {
+		name  string
+		row   []Seat
+		start int
+		size  int
+		want  int
+	}{
+		{name: "two free seats on each side", row: makeRow("A", span(1, 6)), start: 2, size: 2, want: 0},
+		{name: "block at the row end", row: makeRow("A", span(1, 6)), start: 0, size: 2, want: 0},
+		{name: "single seat left at the row start", row: makeRow("A", span(1, 6)), start: 1, size: 2, want: 1},
+		{name: "single seat left before a taken seat", row: makeRow("A", span(1, 6), 6), start: 2, size: 2, want: 1},
+		{name: "single seats on both sides", row: makeRow("A", span(1, 5), 1, 5), start: 2, size: 1, want: 2},
+		{name: "taken neighbours", row: makeRow("A", span(1, 5), 2, 4), start: 2, size: 1, want: 0},
+		{name: "single seat left before a walkway", row: makeRow("A", []int{1, 2, 3, 5, 6}), start: 0, size: 2, want: 1},
+		{name: "walkway right next to the block", row: makeRow("A", []int{1, 2, 4, 5}), start: 0, size: 2, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strandedSeats(tt.row, tt.start, tt.size); got != tt.want {
+				t.Errorf("strandedSeats() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAisle(t *testing.T) {
+	walkway := makeRow("A", []int{1, 2, 3, 5, 6, 7})
+
+	tests := []struct {
+		name string
+		row  []Seat
+		i    int
+		want bool
+	}{
+		{name: "first seat", row: makeRow("A", span(1, 10)), i: 0, want: true},
+		{name: "last seat", row: makeRow("A", span(1, 10)), i: 9, want: true},
+		{name: "middle seat", row: makeRow("A", span(1, 10)), i: 4, want: false},
+		{name: "before a walkway", row: walkway, i: 2, want: true},
+		{name: "after a walkway", row: walkway, i: 3, want: true},
+		{name: "inside a section", row: walkway, i: 1, want: false},
+		{name: "only seat", row: makeRow("A", []int{4}), i: 0, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAisle(tt.row, tt.i); got != tt.want {
+				t.Errorf("isAisle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRowScore(t *testing.T) {
+	tests := []struct {
+		r    int
+		rows int
+		want float64
+	}{
+		{r: 0, rows: 1, want: 1},
+		{r: 0, rows: 2, want: 0},
+		{r: 1, rows: 2, want: 0},
+		{r: 0, rows: 5, want: 0},
+		{r: 1, rows: 5, want: 0.5},
+		{r: 2, rows: 5, want: 1},
+		{r: 4, rows: 5, want: 0},
+		{r: 1, rows: 4, want: 2.0 / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("row %d of %d", tt.r, tt.rows), func(t *testing.T) {
+			if got := rowScore(tt.r, tt.rows); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("rowScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnScore(t *testing.T) {
+	row := makeRow("A", span(1, 10))
+	walkway := makeRow("A", []int{1, 2, 3, 4, 6, 7, 8, 9})
+
+	tests := []struct {
+		name  string
+		row   []Seat
+		block []Seat
+		want  float64
+	}{
+		{name: "centred block", row: row, block: row[4:6], want: 1},
+		{name: "block at the row end", row: row, block: row[0:2], want: 1.0 / 9},
+		{name: "whole row", row: row, block: row, want: 1},
+		{name: "single seat at the end", row: row, block: row[9:], want: 0},
+		{name: "only seat", row: row[:1], block: row[:1], want: 1},
+		{name: "block next to a walkway", row: walkway, block: walkway[2:4], want: 0.625},
+		{name: "numbering does not start at one", row: row[4:], block: row[7:9], want: 0.6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnScore(tt.row, tt.block); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("columnScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
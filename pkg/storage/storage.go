@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotExist = errors.New("object does not exist")
+
+// Object is an open stored file
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// Storage keeps uploaded files under slash separated keys
+type Storage interface {
+	Put(key string, r io.Reader) error
+	Open(key string) (Object, time.Time, error)
+	Delete(key string) error
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"strings"
+)
+
+const apiKeyPrefix = "bk"
+
+// GenerateAPIKey returns a new key formatted as bk_<prefix>_<secret> and its
+// public prefix. Only the prefix and the hash of the key are stored.
+func GenerateAPIKey() (string, string, error) {
+	prefix := make([]byte, 4)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	p := hex.EncodeToString(prefix)
+	return apiKeyPrefix + "_" + p + "_" + hex.EncodeToString(secret), p, nil
+}
+
+// APIKeyPrefix extracts the lookup prefix from a presented key.
+func APIKeyPrefix(key string) (string, bool) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// HashAPIKey hashes a key for storage. Keys are random so a fast hash is enough.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckAPIKey compares a presented key with a stored hash in constant time.
+func CheckAPIKey(key string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashAPIKey(key)), []byte(hash)) == 1
+}
+
+// IPAllowed reports whether ip matches an entry of the allowlist. Entries can
+// be single addresses or CIDR ranges; an empty allowlist allows every address.
+func IPAllowed(ip string, allowlist []string) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, entry := range allowlist {
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err == nil && network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
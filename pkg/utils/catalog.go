@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Separator for list values inside a single CSV cell
+const csvListSeparator = "|"
+
+// Decode catalog rows from CSV or JSON. CSV needs a header row naming the
+// columns by their json tag, in any order.
+func DecodeCatalog[T any](format string, r io.Reader) ([]T, error) {
+	var rows []T
+	switch format {
+	case "json":
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rows); err != nil {
+			return nil, fmt.Errorf("invalid json: %w", err)
+		}
+		return rows, nil
+	case "csv":
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	// Map header names to struct fields
+	fields := catalogFields(reflect.TypeOf(rows).Elem())
+	columns := make([]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		idx, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		columns[i] = idx
+	}
+
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+
+		var row T
+		v := reflect.ValueOf(&row).Elem()
+		for i, value := range record {
+			f := v.Field(columns[i])
+			value = strings.TrimSpace(value)
+			switch f.Kind() {
+			case reflect.String:
+				f.SetString(value)
+			case reflect.Int:
+				if value == "" {
+					continue
+				}
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: column %q must be a number", line, header[i])
+				}
+				f.SetInt(int64(n))
+			case reflect.Slice:
+				var items []string
+				for _, item := range strings.Split(value, csvListSeparator) {
+					if item = strings.TrimSpace(item); item != "" {
+						items = append(items, item)
+					}
+				}
+				f.Set(reflect.ValueOf(items))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Encode catalog rows as CSV or JSON, the inverse of DecodeCatalog
+func EncodeCatalog[T any](format string, w io.Writer, rows []T) error {
+	switch format {
+	case "json":
+		if rows == nil {
+			rows = []T{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	t := reflect.TypeOf(rows).Elem()
+	var header []string
+	for i := 0; i < t.NumField(); i++ {
+		header = append(header, catalogName(t.Field(i)))
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		v := reflect.ValueOf(row)
+		record := make([]string, t.NumField())
+		for i := range record {
+			f := v.Field(i)
+			switch f.Kind() {
+			case reflect.String:
+				record[i] = f.String()
+			case reflect.Int:
+				record[i] = strconv.FormatInt(f.Int(), 10)
+			case reflect.Slice:
+				record[i] = strings.Join(f.Interface().([]string), csvListSeparator)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func catalogFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		fields[catalogName(t.Field(i))] = i
+	}
+	return fields
+}
+
+func catalogName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -14,6 +17,16 @@ type Configuration struct {
 	DB          DatabaseCofig
 	SMTP SMTPConfig
 	BaseURL string
+	ExportPath string
+	MediaPath string
+	MediaMaxSize int64
+	DefaultLocale string
+	Locales []string
+	RequireAdmin2FA bool
+	ExchangeFee float64
+	TransferCutoff time.Duration
+	Invoice InvoiceConfig
+	OIDC OIDCConfig
 }
 
 type DatabaseCofig struct {
@@ -25,6 +38,22 @@ type DatabaseCofig struct {
 	MaxConn  int32
 }
 
+type OIDCConfig struct {
+	Name string
+	Issuer string
+	ClientID string
+	ClientSecret string
+	RedirectURL string
+	Scopes []string
+}
+
+// Seller shown on tax invoices, ticket prices include TaxRate percent tax
+type InvoiceConfig struct {
+	Seller string
+	TaxID string
+	TaxRate float64
+}
+
 type SMTPConfig struct {
 	Port int
 	Email string
@@ -44,6 +73,15 @@ func ReadConfiguration() (Configuration, error) {
 	// get config from os variable
 	viper.AutomaticEnv()
 
+	// defaults for optional settings
+	viper.SetDefault("MEDIA_PATH", "./internal/media/")
+	viper.SetDefault("MEDIA_MAX_SIZE_MB", 5)
+	viper.SetDefault("DEFAULT_LOCALE", "en")
+	viper.SetDefault("LOCALES", "en,id")
+	viper.SetDefault("EXCHANGE_FEE", 0)
+	viper.SetDefault("TRANSFER_CUTOFF_MINUTES", 60)
+	viper.SetDefault("INVOICE_TAX_RATE", 10)
+
 	// get config from flag
 	pflag.Int("port-app", 0, "port for app golang")
 	pflag.Parse()
@@ -69,6 +107,27 @@ func ReadConfiguration() (Configuration, error) {
 			Password: viper.GetString("SMTP_PASSWORD"),
 		},
 		BaseURL: viper.GetString("APP_URL"),
+		ExportPath: viper.GetString("EXPORT_PATH"),
+		MediaPath: viper.GetString("MEDIA_PATH"),
+		MediaMaxSize: viper.GetInt64("MEDIA_MAX_SIZE_MB") << 20,
+		DefaultLocale: strings.ToLower(viper.GetString("DEFAULT_LOCALE")),
+		Locales: strings.Split(strings.ToLower(viper.GetString("LOCALES")), ","),
+		RequireAdmin2FA: viper.GetBool("REQUIRE_ADMIN_2FA"),
+		ExchangeFee: viper.GetFloat64("EXCHANGE_FEE"),
+		TransferCutoff: time.Duration(viper.GetInt("TRANSFER_CUTOFF_MINUTES")) * time.Minute,
+		Invoice: InvoiceConfig{
+			Seller: viper.GetString("INVOICE_SELLER"),
+			TaxID: viper.GetString("INVOICE_TAX_ID"),
+			TaxRate: viper.GetFloat64("INVOICE_TAX_RATE"),
+		},
+		OIDC: OIDCConfig{
+			Name: viper.GetString("OIDC_PROVIDER"),
+			Issuer: viper.GetString("OIDC_ISSUER"),
+			ClientID: viper.GetString("OIDC_CLIENT_ID"),
+			ClientSecret: viper.GetString("OIDC_CLIENT_SECRET"),
+			RedirectURL: viper.GetString("OIDC_REDIRECT_URL"),
+			Scopes: viper.GetStringSlice("OIDC_SCOPES"),
+		},
 	}, nil
 
 }
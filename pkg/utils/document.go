@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/pdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// File names of the documents of a booking
+func TicketsFileName(bookingID int) string {
+	return fmt.Sprintf("tickets-%d.pdf", bookingID)
+}
+
+func InvoiceFileName(bookingID int) string {
+	return fmt.Sprintf("invoice-%d.pdf", bookingID)
+}
+
+// TicketURL is what a ticket's QR code holds
+func TicketURL(qrToken string, config Configuration) string {
+	return fmt.Sprintf("%s/tickets/verify?token=%s", config.BaseURL, qrToken)
+}
+
+func ticketQR(qrToken string, config Configuration) ([][]bool, error) {
+	q, err := qrcode.New(TicketURL(qrToken, config), qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	q.DisableBorder = true
+	return q.Bitmap(), nil
+}
+
+// Brand printed at the top of tickets and invoices
+func documentBrand(config Configuration) string {
+	if config.Invoice.Seller != "" {
+		return config.Invoice.Seller
+	}
+	return strings.ToUpper(config.AppName)
+}
+
+// TicketsPDF renders the tickets of a booking, one A6 page per ticket
+func TicketsPDF(data dto.TicketEmail, config Configuration) ([]byte, error) {
+	const (
+		width  = 105 * pdf.MM
+		height = 148 * pdf.MM
+		margin = 8 * pdf.MM
+		qr     = 50 * pdf.MM
+	)
+	locale := data.Locale
+	left := margin
+	right := width - margin
+	center := width / 2
+
+	doc := pdf.New()
+	for _, t := range data.Tickets {
+		bits, err := ticketQR(t.QRToken, config)
+		if err != nil {
+			return nil, err
+		}
+		doc.AddPage(width, height)
+
+		// Header
+		y := margin + 12
+		doc.Text(left, y, pdf.Bold, 14, fitText(documentBrand(config), 14, (right-left)/2))
+		doc.TextRight(right, y, pdf.Regular, 9, T(locale, "document.ticket"))
+		y += 6
+		doc.Line(left, y, right, y, 1.5)
+
+		// Movie
+		y += 20
+		doc.Text(left, y, pdf.Bold, 13, fitText(data.Movie.Title, 13, right-left))
+		y += 13
+		doc.Text(left, y, pdf.Regular, 8, fitText(data.Cinema.Name, 8, right-left))
+
+		// Details in two columns, a small label above each value
+		field := func(x, y float64, label, value string, size float64) {
+			doc.Text(x, y, pdf.Regular, 7, label)
+			doc.Text(x, y+size+2, pdf.Bold, size, fitText(value, size, (right-left)/2-4))
+		}
+		column := center + 2
+		y += 20
+		field(left, y, T(locale, "ticket.date"), data.BookingDate, 10)
+		field(column, y, T(locale, "ticket.start_time"), data.Screening.StartTime, 10)
+		y += 28
+		field(left, y, T(locale, "ticket.studio"), data.Studio.Name, 10)
+		field(column, y, T(locale, "document.format"), data.Screening.Format, 10)
+		y += 28
+		field(left, y, T(locale, "ticket.seat"), t.SeatCode, 18)
+		field(column, y, T(locale, "document.booking"), fmt.Sprintf("#%d", data.BookingID), 10)
+
+		// QR code
+		y += 36
+		doc.Line(left, y, right, y, 0.5)
+		y += 10
+		doc.Bitmap(center-qr/2, y, qr/float64(len(bits)), bits)
+		y += qr + 12
+		doc.TextCenter(center, y, pdf.Regular, 6, fitText(t.QRToken, 6, right-left))
+
+		// Footer
+		doc.Line(left, height-margin-14, right, height-margin-14, 0.5)
+		doc.TextCenter(center, height-margin-4, pdf.Regular, 6, fitText(T(locale, "document.admit"), 6, right-left))
+	}
+	return doc.Bytes(), nil
+}
+
+// InvoicePDF renders the tax invoice of a booking on an A4 page
+func InvoicePDF(data dto.TicketEmail, config Configuration) ([]byte, error) {
+	if data.Invoice == nil {
+		return nil, fmt.Errorf("booking %d has no invoice", data.BookingID)
+	}
+	const margin = 20 * pdf.MM
+	invoice := data.Invoice
+	locale := data.Locale
+	left := margin
+	right := pdf.A4Width - margin
+
+	doc := pdf.New()
+	doc.AddPage(pdf.A4Width, pdf.A4Height)
+
+	// Seller and invoice title
+	y := margin + 16
+	doc.Text(left, y, pdf.Bold, 16, fitText(documentBrand(config), 16, (right-left)/2))
+	doc.TextRight(right, y, pdf.Bold, 14, T(locale, "invoice.title"))
+	y += 8
+	doc.Line(left, y, right, y, 1.5)
+
+	// Seller details on the left, invoice details on the right
+	y += 18
+	top := y
+	if config.Invoice.TaxID != "" {
+		doc.Text(left, y, pdf.Regular, 9, T(locale, "invoice.tax_id")+": "+config.Invoice.TaxID)
+		y += 12
+	}
+	doc.Text(left, y, pdf.Regular, 9, fitText(data.Cinema.Name, 9, (right-left)/2))
+	if data.Cinema.Address != nil {
+		y += 12
+		doc.Text(left, y, pdf.Regular, 9, fitText(*data.Cinema.Address, 9, (right-left)/2))
+	}
+	if data.Cinema.City != nil {
+		y += 12
+		doc.Text(left, y, pdf.Regular, 9, fitText(*data.Cinema.City, 9, (right-left)/2))
+	}
+
+	labels := left + (right-left)*0.55
+	details := [][2]string{
+		{T(locale, "invoice.number"), invoice.Number},
+		{T(locale, "invoice.date"), invoice.IssuedAt},
+		{T(locale, "document.booking"), fmt.Sprintf("#%d", data.BookingID)},
+	}
+	for i, d := range details {
+		doc.Text(labels, top+float64(i)*12, pdf.Regular, 9, d[0])
+		doc.TextRight(right, top+float64(i)*12, pdf.Bold, 9, d[1])
+	}
+	y = max(y, top+float64(len(details)-1)*12)
+
+	// Customer
+	y += 26
+	doc.Text(left, y, pdf.Regular, 8, T(locale, "invoice.billed_to"))
+	y += 13
+	doc.Text(left, y, pdf.Bold, 10, fitText(data.Profile.Name, 10, right-left))
+	y += 12
+	doc.Text(left, y, pdf.Regular, 9, fitText(data.Profile.Email, 9, right-left))
+
+	// Line items
+	qtyRight := right - 190
+	priceRight := right - 95
+	y += 28
+	doc.Text(left, y, pdf.Bold, 9, T(locale, "invoice.description"))
+	doc.TextRight(qtyRight, y, pdf.Bold, 9, T(locale, "invoice.qty"))
+	doc.TextRight(priceRight, y, pdf.Bold, 9, T(locale, "invoice.unit_price"))
+	doc.TextRight(right, y, pdf.Bold, 9, T(locale, "invoice.amount"))
+	y += 6
+	doc.Line(left, y, right, y, 0.75)
+	for _, line := range invoice.Lines {
+		y += 14
+		doc.Text(left, y, pdf.Regular, 9, fitText(line.Description, 9, qtyRight-left-40))
+		doc.TextRight(qtyRight, y, pdf.Regular, 9, strconv.Itoa(line.Quantity))
+		doc.TextRight(priceRight, y, pdf.Regular, 9, FormatAmount(line.UnitPrice))
+		doc.TextRight(right, y, pdf.Regular, 9, FormatAmount(line.Amount))
+	}
+	y += 8
+	doc.Line(left, y, right, y, 0.75)
+
+	// Totals
+	totals := [][2]string{
+		{T(locale, "invoice.subtotal"), FormatAmount(invoice.Subtotal)},
+		{fmt.Sprintf("%s (%s%%)", T(locale, "invoice.tax"), strconv.FormatFloat(invoice.TaxRate, 'f', -1, 64)), FormatAmount(invoice.Tax)},
+	}
+	for _, t := range totals {
+		y += 14
+		doc.TextRight(priceRight, y, pdf.Regular, 9, t[0])
+		doc.TextRight(right, y, pdf.Regular, 9, t[1])
+	}
+	y += 6
+	doc.Line(priceRight-90, y, right, y, 0.75)
+	y += 14
+	doc.TextRight(priceRight, y, pdf.Bold, 10, T(locale, "invoice.total"))
+	doc.TextRight(right, y, pdf.Bold, 10, FormatAmount(invoice.Total))
+
+	// Payment
+	y += 30
+	doc.Text(left, y, pdf.Regular, 9, T(locale, "invoice.payment")+": "+invoice.PaymentMethod)
+	y += 12
+	doc.Text(left, y, pdf.Regular, 8, T(locale, "invoice.tax_included"))
+
+	return doc.Bytes(), nil
+}
+
+// BookingDocuments renders the PDFs attached to a ticket email, the invoice
+// only goes to the customer who paid for the booking
+func BookingDocuments(data dto.TicketEmail, config Configuration) ([]dto.Attachment, error) {
+	var attachments []dto.Attachment
+	if len(data.Tickets) > 0 {
+		tickets, err := TicketsPDF(data, config)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, dto.Attachment{
+			FileName:    TicketsFileName(data.BookingID),
+			FileByte:    tickets,
+			ContentType: "application/pdf",
+		})
+	}
+
+	if data.Invoice != nil {
+		invoice, err := InvoicePDF(data, config)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, dto.Attachment{
+			FileName:    InvoiceFileName(data.BookingID),
+			FileByte:    invoice,
+			ContentType: "application/pdf",
+		})
+	}
+	return attachments, nil
+}
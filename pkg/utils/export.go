@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+)
+
+// Write personal data export as a ZIP archive of JSON files
+func WriteExportArchive(path string, data dto.DataExport) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	files := map[string]any{
+		"export.json":   data,
+		"profile.json":  data.Profile,
+		"bookings.json": data.Bookings,
+		"payments.json": data.Payments,
+		"tickets.json":  data.Tickets,
+		"reviews.json":  data.Reviews,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(content); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
@@ -68,6 +68,7 @@ func GetPaginationQuery(r *http.Request, log *zap.Logger, config Configuration)
 		Page: page,
 		Limit: limit,
 		All: all,
+		Locale: GetLocale(r, config),
 	}
 
 	return pagination, nil
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetLocale picks the response locale from the Accept-Language header
+func GetLocale(r *http.Request, config Configuration) string {
+	return NegotiateLocale(r.Header.Get("Accept-Language"), config.Locales, config.DefaultLocale)
+}
+
+// NegotiateLocale returns the supported locale the client prefers most. A
+// regional tag such as id-ID falls back to its language, anything else to
+// the default locale.
+func NegotiateLocale(header string, supported []string, fallback string) string {
+	type tag struct {
+		name string
+		q    float64
+	}
+
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" || name == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name: name, q: q})
+	}
+
+	// Stable keeps header order between equal weights
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	for _, t := range tags {
+		if slices.Contains(supported, t.name) {
+			return t.name
+		}
+		base, _, _ := strings.Cut(t.name, "-")
+		if slices.Contains(supported, base) {
+			return base
+		}
+	}
+	return fallback
+}
+
+// IsSupportedLocale reports whether content can be served in the locale
+func IsSupportedLocale(locale string, config Configuration) bool {
+	return slices.Contains(config.Locales, locale)
+}
+
+// Email texts per locale, keys missing in a locale fall back to English
+var messages = map[string]map[string]string{
+	"en": {
+		"otp.subject":          "Email Verification",
+		"otp.title":            "Email Verification",
+		"otp.greeting":         "Hello %s!",
+		"otp.body":             "Thank you for registering. Please use the verification code below to confirm your email address:",
+		"otp.expiry":           "This code will expire in <strong>5 minutes</strong>.",
+		"otp.ignore":           "If you did not request this, please ignore this email.",
+		"otp.warning":          "Do not share this code with anyone.",
+		"email_change.subject": "Confirm Your New Email",
+		"email_change.title":   "Confirm Your New Email",
+		"email_change.body":    "We received a request to change the email address of your account to this address. Please use the verification code below to confirm it:",
+		"ticket.subject":       "Your Ticket Is Ready",
+		"ticket.title":         "Your Ticket Is Ready",
+		"ticket.greeting":      "Hi <strong>%s</strong>,",
+		"ticket.body":          "Your payment was successful. Your tickets are attached as a PDF with one page per seat. Please present the QR code at the entrance gate.",
+		"ticket.movie":         "Movie",
+		"ticket.cinema":        "Cinema",
+		"ticket.studio":        "Studio",
+		"ticket.date":          "Date",
+		"ticket.start_time":    "Start Time",
+		"ticket.seat":          "Seat",
+		"ticket.arrive":        "Please arrive at least <strong>15 minutes</strong> before the show.",
+		"ticket.single_entry":  "This ticket is valid for <strong>one-time entry only</strong>.",
+		"ticket.no_share":      "Do not share your QR code with others.",
+		"ticket.enjoy":         "Enjoy the movie",
+		"ticket.team":          "Cinema Booking Team",
+		"ticket.support":       "If you have any issues, please contact our support team.",
+		"waitlist.subject":     "Seats Are Waiting for You",
+		"waitlist.title":       "Seats Are Waiting for You",
+		"waitlist.body":        "Seats opened up for a screening you are waitlisted for. We are holding <strong>%d seat(s)</strong> for you until <strong>%s</strong>.",
+		"waitlist.action":      "Choose your seats and book them before then. After that the offer passes to the next person in line.",
+		"waitlist.seats":       "Seats",
+		"transfer.subject":     "A Ticket Was Sent to You",
+		"transfer.title":       "A Ticket Was Sent to You",
+		"transfer.body":        "<strong>%s</strong> wants to give you a ticket for the screening below.",
+		"transfer.action":      "Accept it from your transfers to get the ticket with your own QR code. Tickets cannot be transferred close to showtime.",
+		"receipt.title":        "Box Office Receipt",
+		"receipt.number":       "Receipt",
+		"receipt.issued":       "Issued",
+		"receipt.cashier":      "Cashier",
+		"receipt.phone":        "Phone",
+		"receipt.total":        "Total",
+		"receipt.payment":      "Payment",
+		"receipt.tendered":     "Cash",
+		"receipt.change":       "Change",
+		"receipt.approval":     "Approval",
+		"receipt.scan":         "Scan at the entrance gate",
+		"receipt.thanks":       "Thank you, enjoy the movie",
+		"document.ticket":      "Cinema Ticket",
+		"document.booking":     "Booking",
+		"document.format":      "Format",
+		"document.admit":       "Admits one. Show this QR code at the entrance gate.",
+		"invoice.title":        "Tax Invoice",
+		"invoice.number":       "Invoice No.",
+		"invoice.date":         "Date",
+		"invoice.billed_to":    "Billed to",
+		"invoice.tax_id":       "Tax ID",
+		"invoice.description":  "Description",
+		"invoice.qty":          "Qty",
+		"invoice.unit_price":   "Price",
+		"invoice.amount":       "Amount",
+		"invoice.subtotal":     "Subtotal",
+		"invoice.tax":          "Tax",
+		"invoice.total":        "Total",
+		"invoice.payment":      "Paid with",
+		"invoice.tax_included": "Prices include tax.",
+		"invoice.line_payment": "Tickets, booking #%d",
+		"invoice.line_charge":  "Seat change charge, booking #%d",
+		"invoice.line_refund":  "Refund, booking #%d",
+	},
+	"id": {
+		"otp.subject":          "Verifikasi Email",
+		"otp.title":            "Verifikasi Email",
+		"otp.greeting":         "Halo %s!",
+		"otp.body":             "Terima kasih telah mendaftar. Gunakan kode verifikasi di bawah ini untuk mengonfirmasi alamat email Anda:",
+		"otp.expiry":           "Kode ini akan kedaluwarsa dalam <strong>5 menit</strong>.",
+		"otp.ignore":           "Jika Anda tidak memintanya, abaikan email ini.",
+		"otp.warning":          "Jangan bagikan kode ini kepada siapa pun.",
+		"email_change.subject": "Konfirmasi Email Baru Anda",
+		"email_change.title":   "Konfirmasi Email Baru Anda",
+		"email_change.body":    "Kami menerima permintaan untuk mengganti alamat email akun Anda ke alamat ini. Gunakan kode verifikasi di bawah ini untuk mengonfirmasinya:",
+		"ticket.subject":       "Tiket Anda Sudah Siap",
+		"ticket.title":         "Tiket Anda Sudah Siap",
+		"ticket.greeting":      "Hai <strong>%s</strong>,",
+		"ticket.body":          "Pembayaran Anda berhasil. Tiket Anda terlampir sebagai PDF dengan satu halaman per kursi. Tunjukkan kode QR di pintu masuk.",
+		"ticket.movie":         "Film",
+		"ticket.cinema":        "Bioskop",
+		"ticket.studio":        "Studio",
+		"ticket.date":          "Tanggal",
+		"ticket.start_time":    "Jam Mulai",
+		"ticket.seat":          "Kursi",
+		"ticket.arrive":        "Harap datang paling lambat <strong>15 menit</strong> sebelum film dimulai.",
+		"ticket.single_entry":  "Tiket ini hanya berlaku untuk <strong>satu kali masuk</strong>.",
+		"ticket.no_share":      "Jangan bagikan kode QR Anda kepada orang lain.",
+		"ticket.enjoy":         "Selamat menonton",
+		"ticket.team":          "Tim Pemesanan Bioskop",
+		"ticket.support":       "Jika ada kendala, silakan hubungi tim dukungan kami.",
+		"waitlist.subject":     "Kursi Menanti Anda",
+		"waitlist.title":       "Kursi Menanti Anda",
+		"waitlist.body":        "Ada kursi kosong untuk jadwal yang Anda tunggu. Kami menyimpan <strong>%d kursi</strong> untuk Anda hingga <strong>%s</strong>.",
+		"waitlist.action":      "Pilih dan pesan kursi Anda sebelum waktu tersebut. Setelah itu penawaran diberikan kepada antrean berikutnya.",
+		"waitlist.seats":       "Jumlah Kursi",
+		"transfer.subject":     "Ada Tiket untuk Anda",
+		"transfer.title":       "Ada Tiket untuk Anda",
+		"transfer.body":        "<strong>%s</strong> ingin memberikan tiket untuk jadwal di bawah ini kepada Anda.",
+		"transfer.action":      "Terima tiket melalui daftar transfer Anda untuk mendapatkan tiket dengan kode QR Anda sendiri. Tiket tidak dapat ditransfer menjelang jam tayang.",
+		"receipt.title":        "Struk Loket",
+		"receipt.number":       "Struk",
+		"receipt.issued":       "Dicetak",
+		"receipt.cashier":      "Kasir",
+		"receipt.phone":        "Telepon",
+		"receipt.total":        "Total",
+		"receipt.payment":      "Pembayaran",
+		"receipt.tendered":     "Tunai",
+		"receipt.change":       "Kembali",
+		"receipt.approval":     "Kode Approval",
+		"receipt.scan":         "Pindai di pintu masuk",
+		"receipt.thanks":       "Terima kasih, selamat menonton",
+		"document.ticket":      "Tiket Bioskop",
+		"document.booking":     "Pemesanan",
+		"document.format":      "Format",
+		"document.admit":       "Berlaku untuk satu orang. Tunjukkan kode QR ini di pintu masuk.",
+		"invoice.title":        "Faktur Pajak",
+		"invoice.number":       "No. Faktur",
+		"invoice.date":         "Tanggal",
+		"invoice.billed_to":    "Ditagihkan kepada",
+		"invoice.tax_id":       "NPWP",
+		"invoice.description":  "Keterangan",
+		"invoice.qty":          "Jml",
+		"invoice.unit_price":   "Harga",
+		"invoice.amount":       "Jumlah",
+		"invoice.subtotal":     "Subtotal",
+		"invoice.tax":          "Pajak",
+		"invoice.total":        "Total",
+		"invoice.payment":      "Dibayar dengan",
+		"invoice.tax_included": "Harga sudah termasuk pajak.",
+		"invoice.line_payment": "Tiket, pemesanan #%d",
+		"invoice.line_charge":  "Biaya perubahan kursi, pemesanan #%d",
+		"invoice.line_refund":  "Pengembalian dana, pemesanan #%d",
+	},
+}
+
+// T returns the email text for a key in the given locale
+func T(locale string, key string) string {
+	if text, ok := messages[locale][key]; ok {
+		return text
+	}
+	if text, ok := messages["en"][key]; ok {
+		return text
+	}
+	return key
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+	"github.com/project-app-bioskop-golang/pkg/pdf"
+)
+
+// Receipt layout in points
+const (
+	receiptMargin  = 4 * pdf.MM
+	receiptSize    = 8.0
+	receiptLeading = 11.0
+	receiptQR      = 36 * pdf.MM
+)
+
+type receiptRow struct {
+	left   string
+	right  string
+	font   pdf.Font
+	center bool
+	rule   bool
+}
+
+// BoxOfficeReceipt renders a walk-in sale as an 80mm receipt PDF with a QR
+// code per ticket to be scanned at the entrance gate
+func BoxOfficeReceipt(sale dto.BoxOfficeSaleResponse, locale string, config Configuration) ([]byte, error) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	rule := receiptRow{rule: true}
+
+	rows := []receiptRow{
+		{left: sale.CinemaName, font: pdf.Bold, center: true},
+		{left: T(locale, "receipt.title"), center: true},
+		rule,
+		{left: T(locale, "receipt.number"), right: sale.ReceiptNumber},
+		{left: T(locale, "receipt.issued"), right: sale.CreatedAt.In(loc).Format("02-01-2006 15.04")},
+		{left: T(locale, "receipt.cashier"), right: sale.Cashier},
+	}
+	if sale.CustomerPhone != nil {
+		rows = append(rows, receiptRow{left: T(locale, "receipt.phone"), right: *sale.CustomerPhone})
+	}
+	rows = append(rows,
+		rule,
+		receiptRow{left: sale.MovieTitle, font: pdf.Bold},
+		receiptRow{left: T(locale, "ticket.studio"), right: fmt.Sprintf("%s (%s)", sale.StudioName, sale.Format)},
+		receiptRow{left: T(locale, "ticket.date"), right: sale.Date},
+		receiptRow{left: T(locale, "ticket.start_time"), right: sale.StartTime},
+		rule,
+	)
+	for _, t := range sale.Tickets {
+		rows = append(rows, receiptRow{left: T(locale, "ticket.seat") + " " + t.SeatCode, right: FormatAmount(sale.Price)})
+	}
+	rows = append(rows,
+		rule,
+		receiptRow{left: T(locale, "receipt.total"), right: FormatAmount(sale.Payment.Amount), font: pdf.Bold},
+		receiptRow{left: T(locale, "receipt.payment"), right: sale.Payment.Method},
+	)
+	if sale.Payment.Tendered != nil && sale.Payment.Change != nil {
+		rows = append(rows,
+			receiptRow{left: T(locale, "receipt.tendered"), right: FormatAmount(*sale.Payment.Tendered)},
+			receiptRow{left: T(locale, "receipt.change"), right: FormatAmount(*sale.Payment.Change)},
+		)
+	}
+	if sale.Payment.ApprovalCode != nil {
+		rows = append(rows, receiptRow{left: T(locale, "receipt.approval"), right: *sale.Payment.ApprovalCode})
+	}
+	rows = append(rows, rule)
+
+	// Each ticket is a seat label above its QR code
+	qrs := make([][][]bool, len(sale.Tickets))
+	for i, t := range sale.Tickets {
+		bits, err := ticketQR(t.QRToken, config)
+		if err != nil {
+			return nil, err
+		}
+		qrs[i] = bits
+	}
+
+	ticketHeight := receiptLeading + receiptQR + receiptLeading
+	height := 2*receiptMargin + float64(len(rows)+2)*receiptLeading + float64(len(qrs))*ticketHeight
+
+	doc := pdf.New()
+	doc.AddPage(pdf.ReceiptWidth, height)
+	left := receiptMargin
+	right := pdf.ReceiptWidth - receiptMargin
+	center := pdf.ReceiptWidth / 2
+
+	y := receiptMargin
+	for _, row := range rows {
+		y += receiptLeading
+		switch {
+		case row.rule:
+			doc.Line(left, y-receiptLeading/2+receiptSize/4, right, y-receiptLeading/2+receiptSize/4, 0.5)
+		case row.center:
+			doc.TextCenter(center, y, row.font, receiptSize, fitText(row.left, receiptSize, right-left))
+		default:
+			if row.right != "" {
+				doc.TextRight(right, y, row.font, receiptSize, row.right)
+			}
+			space := right - left - pdf.TextWidth(receiptSize, row.right+" ")
+			doc.Text(left, y, row.font, receiptSize, fitText(row.left, receiptSize, space))
+		}
+	}
+
+	for i, bits := range qrs {
+		y += receiptLeading
+		doc.TextCenter(center, y, pdf.Bold, receiptSize, T(locale, "ticket.seat")+" "+sale.Tickets[i].SeatCode)
+		y += receiptLeading / 2
+		doc.Bitmap(center-receiptQR/2, y, receiptQR/float64(len(bits)), bits)
+		y += receiptQR + receiptLeading/2
+	}
+
+	y += receiptLeading
+	doc.TextCenter(center, y, pdf.Regular, receiptSize, T(locale, "receipt.scan"))
+	y += receiptLeading
+	doc.TextCenter(center, y, pdf.Regular, receiptSize, T(locale, "receipt.thanks"))
+
+	return doc.Bytes(), nil
+}
+
+// fitText cuts s short with an ellipsis when it is wider than width at size
+func fitText(s string, size float64, width float64) string {
+	runes := []rune(s)
+	n := int(width / pdf.TextWidth(size, "M"))
+	if len(runes) <= n || n < 4 {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+// FormatAmount writes an amount in rupiah with dots between thousands
+func FormatAmount(amount float64) string {
+	// Refunds on an invoice are negative
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + "Rp " + b.String()
+}
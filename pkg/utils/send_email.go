@@ -1,11 +1,9 @@
 package utils
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/project-app-bioskop-golang/internal/dto"
-	"github.com/skip2/go-qrcode"
 	"go.uber.org/zap"
 	"gopkg.in/mail.v2"
 )
@@ -40,13 +38,3 @@ func SendEmail(data dto.EmailRequest, config Configuration, log *zap.Logger) err
 	log.Info("Email send successfully")
 	return nil
 }
-
-func GenerateQR(qrToken string, config Configuration, log *zap.Logger) ([]byte, error) {
-	url := fmt.Sprintf("%s/tickets/verify?token=%s", config.BaseURL, qrToken)
- 	png, err := qrcode.Encode(url, qrcode.Medium, 256)
-	if err != nil {
-		log.Error("Error generate QR: ", zap.Error(err))
-		return nil, err
-	}
-	return png, err
-}
\ No newline at end of file
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+)
+
+func SendExportReady(name string, downloadURL string) string {
+	return fmt.Sprintf(`
+	<h2>Your Data Export Is Ready</h2>
+
+	<p>Hello %s!</p>
+
+	<p>
+	The copy of your personal data you requested is ready. It contains your
+	profile, bookings, payments and tickets as JSON files in a ZIP archive.
+	</p>
+
+	<p>
+	<a href='%s'>Download your data</a>
+	</p>
+
+	<p>
+	The link requires you to be signed in and will expire in <strong>7 days</strong>.
+	</p>
+
+	<p style='color: #888; font-size: 12px;'>
+	If you did not request this, please change your password.
+	</p>
+	`, name, downloadURL)
+}
@@ -6,15 +6,14 @@ import (
 	"github.com/project-app-bioskop-golang/internal/dto"
 )
 
-func SendOTP(data dto.OTPResponse) string {
+func SendOTP(data dto.OTPResponse, locale string) string {
 	return fmt.Sprintf(`
-	<h2>Email Verification</h2>
+	<h2>%s</h2>
 
-	<p>Hello %s!</p>
+	<p>%s</p>
 
 	<p>
-	Thank you for registering. Please use the verification code below to
-	confirm your email address:
+	%s
 	</p>
 
 	<div style='
@@ -27,15 +26,49 @@ func SendOTP(data dto.OTPResponse) string {
 	</div>
 
 	<p>
-	This code will expire in <strong>5 minutes</strong>.
+	%s
 	</p>
 
 	<p>
-	If you did not request this, please ignore this email.
+	%s
 	</p>
 
 	<p style='color: #888; font-size: 12px;'>
-	Do not share this code with anyone.
+	%s
 	</p>
-	`, data.Name, data.OTP)
-}
\ No newline at end of file
+	`, T(locale, "otp.title"), fmt.Sprintf(T(locale, "otp.greeting"), data.Name), T(locale, "otp.body"),
+	data.OTP, T(locale, "otp.expiry"), T(locale, "otp.ignore"), T(locale, "otp.warning"))
+}
+func SendEmailChangeOTP(data dto.OTPResponse, locale string) string {
+	return fmt.Sprintf(`
+	<h2>%s</h2>
+
+	<p>%s</p>
+
+	<p>
+	%s
+	</p>
+
+	<div style='
+		font-size: 24px;
+		font-weight: bold;
+		letter-spacing: 4px;
+		margin: 16px 0;
+	'>
+		%v
+	</div>
+
+	<p>
+	%s
+	</p>
+
+	<p>
+	%s
+	</p>
+
+	<p style='color: #888; font-size: 12px;'>
+	%s
+	</p>
+	`, T(locale, "email_change.title"), fmt.Sprintf(T(locale, "otp.greeting"), data.Name), T(locale, "email_change.body"),
+	data.OTP, T(locale, "otp.expiry"), T(locale, "otp.ignore"), T(locale, "otp.warning"))
+}
@@ -12,6 +12,7 @@ func SendTicket(ticket dto.TicketEmail) string {
 	for _, t := range ticket.Tickets {
 		seats = append(seats, t.SeatCode)
 	}
+	locale := ticket.Locale
 
 	return fmt.Sprintf(`
 	<!DOCTYPE html>
@@ -24,14 +25,14 @@ func SendTicket(ticket dto.TicketEmail) string {
 						<!-- Header -->
 						<tr>
 							<td align="center" style="padding-bottom:16px;">
-								<h2 style="margin:0; color:#222;">🎬 Your Ticket Is Ready</h2>
+								<h2 style="margin:0; color:#222;">🎬 %s</h2>
 							</td>
 						</tr>
 
 						<!-- Greeting -->
 						<tr>
 							<td style="padding-bottom:12px; color:#333;">
-								<p style="margin:0;">Hi <strong>%s</strong>,</p>
+								<p style="margin:0;">%s</p>
 							</td>
 						</tr>
 
@@ -39,8 +40,7 @@ func SendTicket(ticket dto.TicketEmail) string {
 						<tr>
 							<td style="padding-bottom:16px; color:#333;">
 								<p style="margin:0;">
-									Your payment was successful. Below is your official cinema ticket.
-									Please present the QR code at the entrance gate.
+									%s
 								</p>
 							</td>
 						</tr>
@@ -48,12 +48,12 @@ func SendTicket(ticket dto.TicketEmail) string {
 						<!-- Ticket Details -->
 						<tr>
 							<td style="padding:16px; background:#f9f9f9; border-radius:6px; color:#333;">
-								<p style="margin:4px 0;"><strong>Movie:</strong> %s</p>
-								<p style="margin:4px 0;"><strong>Cinema:</strong> %s</p>
-								<p style="margin:4px 0;"><strong>Studio:</strong> %s</p>
-								<p style="margin:4px 0;"><strong>Date:</strong> %s</p>
-								<p style="margin:4px 0;"><strong>Start Time:</strong> %s</p>
-								<p style="margin:4px 0;"><strong>Seat:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
+								<p style="margin:4px 0;"><strong>%s:</strong> %s</p>
 							</td>
 						</tr>
 
@@ -61,9 +61,9 @@ func SendTicket(ticket dto.TicketEmail) string {
 						<tr>
 							<td style="padding-top:12px; color:#555; font-size:14px;">
 								<ul style="padding-left:18px; margin:0;">
-									<li>Please arrive at least <strong>15 minutes</strong> before the show.</li>
-									<li>This ticket is valid for <strong>one-time entry only</strong>.</li>
-									<li>Do not share your QR code with others.</li>
+									<li>%s</li>
+									<li>%s</li>
+									<li>%s</li>
 								</ul>
 							</td>
 						</tr>
@@ -72,11 +72,11 @@ func SendTicket(ticket dto.TicketEmail) string {
 						<tr>
 							<td style="padding-top:24px; color:#777; font-size:13px;">
 								<p style="margin:0;">
-									Enjoy the movie 🍿<br/>
-									<strong>Cinema Booking Team</strong>
+									%s 🍿<br/>
+									<strong>%s</strong>
 								</p>
 								<p style="margin-top:8px; font-size:12px;">
-									If you have any issues, please contact our support team.
+									%s
 								</p>
 							</td>
 						</tr>
@@ -87,7 +87,12 @@ func SendTicket(ticket dto.TicketEmail) string {
 		</table>
 			</body>
 		</html>
-	`, "100%", ticket.Profile.Name, ticket.Movie.Title, ticket.Cinema.Name, 
-	ticket.Studio.Name, ticket.BookingDate, ticket.Screening.StartTime, 
-	strings.Join(seats, ", "))
+	`, "100%", T(locale, "ticket.title"), fmt.Sprintf(T(locale, "ticket.greeting"), ticket.Profile.Name),
+	T(locale, "ticket.body"),
+	T(locale, "ticket.movie"), ticket.Movie.Title, T(locale, "ticket.cinema"), ticket.Cinema.Name, 
+	T(locale, "ticket.studio"), ticket.Studio.Name, T(locale, "ticket.date"), ticket.BookingDate,
+	T(locale, "ticket.start_time"), ticket.Screening.StartTime, 
+	T(locale, "ticket.seat"), strings.Join(seats, ", "),
+	T(locale, "ticket.arrive"), T(locale, "ticket.single_entry"), T(locale, "ticket.no_share"),
+	T(locale, "ticket.enjoy"), T(locale, "ticket.team"), T(locale, "ticket.support"))
 }
\ No newline at end of file
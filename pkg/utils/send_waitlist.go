@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/project-app-bioskop-golang/internal/dto"
+)
+
+func SendWaitlistOffer(data dto.WaitlistOfferEmail) string {
+	locale := data.Locale
+
+	return fmt.Sprintf(`
+	<h2>%s</h2>
+
+	<p>%s</p>
+
+	<p>
+	%s
+	</p>
+
+	<div style='padding:16px; background:#f9f9f9; border-radius:6px; color:#333;'>
+		<p style='margin:4px 0;'><strong>%s:</strong> %s</p>
+		<p style='margin:4px 0;'><strong>%s:</strong> %s</p>
+		<p style='margin:4px 0;'><strong>%s:</strong> %s</p>
+		<p style='margin:4px 0;'><strong>%s:</strong> %s</p>
+		<p style='margin:4px 0;'><strong>%s:</strong> %d</p>
+	</div>
+
+	<p>
+	%s
+	</p>
+
+	<p style='color: #888; font-size: 12px;'>
+	%s
+	</p>
+	`, T(locale, "waitlist.title"), fmt.Sprintf(T(locale, "ticket.greeting"), data.Name),
+	fmt.Sprintf(T(locale, "waitlist.body"), data.SeatCount, data.OfferExpiresAt),
+	T(locale, "ticket.movie"), data.MovieTitle, T(locale, "ticket.cinema"), data.CinemaName,
+	T(locale, "ticket.date"), data.Date, T(locale, "ticket.start_time"), data.StartTime,
+	T(locale, "waitlist.seats"), data.SeatCount, T(locale, "waitlist.action"), T(locale, "ticket.team"))
+}
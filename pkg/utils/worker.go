@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/project-app-bioskop-golang/internal/dto"
 	"go.uber.org/zap"
@@ -19,6 +20,12 @@ type TicketJob struct {
 	Log *zap.Logger
   Data dto.TicketEmail
 }
+
+type ExportJob struct {
+	FilePath string
+	Gather func() (*dto.DataExport, error)
+	Done func(data *dto.DataExport, err error)
+}
  
 // Worker pool
 func StartEmailWorkers(workerCount int, jobs <-chan EmailJob, stop <-chan struct{}, metrics *Metrics, wg *sync.WaitGroup) {
@@ -69,29 +76,18 @@ func StartTicketWorkers(workerCount int, jobs <-chan TicketJob, stop <-chan stru
               return
           }
 
-          var attachments []dto.Attachment
-          for _, t := range job.Data.Tickets {
-            png, err := GenerateQR(t.QRToken, job.Config, job.Log)
-            if err != nil {
-                job.Log.Error("Failed to generate QR", zap.Error(err))
-                continue
-            }
-
-            attachment := dto.Attachment{
-              FileName: fmt.Sprintf("ticket-%v-%v.png", i, job.Data.BookingDate),
-              FileByte: png,
-              ContentType: "image/png",
-            }
-
-            attachments = append(attachments, attachment)
+          // Tickets and invoice as PDF
+          attachments, err := BookingDocuments(job.Data, job.Config)
+          if err != nil {
+            job.Log.Error("Failed to render booking documents", zap.Error(err))
+            metrics.Failed()
+            continue
           }
 
-          fmt.Println(len(attachments))
-
           // Format email content
           body := SendTicket(job.Data)
           to := job.Data.Profile.Email
-          subject := "Your Ticket Is Ready"
+          subject := T(job.Data.Locale, "ticket.subject")
           content := dto.EmailRequest{
             To: to,
             Subject: subject,
@@ -115,4 +111,62 @@ func StartTicketWorkers(workerCount int, jobs <-chan TicketJob, stop <-chan stru
       }
     }(i)
   }
-}
\ No newline at end of file
+}
+
+func StartExportWorkers(workerCount int, jobs <-chan ExportJob, stop <-chan struct{}, log *zap.Logger, wg *sync.WaitGroup) {
+  wg.Add(workerCount)
+
+  for i := 1; i <= workerCount; i++ {
+    go func(id int) {
+      defer wg.Done()
+
+      for {
+        select {
+        case job, ok := <-jobs:
+          if !ok {
+            log.Info(fmt.Sprintf("worker %v jobs channel closed", id))
+            return
+          }
+
+          // collect user data, then build archive
+          data, err := job.Gather()
+          if err == nil {
+            err = WriteExportArchive(job.FilePath, *data)
+          }
+          if err != nil {
+            log.Error("Data export failed", zap.Error(err))
+          }
+
+          job.Done(data, err)
+
+        case <-stop:
+          fmt.Println("worker ", id, " received stop signal")
+          return
+        }
+      }
+    }(i)
+  }
+}
+
+// Periodic job, e.g. passing expired holds and offers on to the waitlist
+func StartSweeper(interval time.Duration, sweep func(), stop <-chan struct{}, wg *sync.WaitGroup) {
+  wg.Add(1)
+
+  go func() {
+    defer wg.Done()
+
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    for {
+      select {
+      case <-ticker.C:
+        sweep()
+
+      case <-stop:
+        fmt.Println("sweeper received stop signal")
+        return
+      }
+    }
+  }()
+}